@@ -17,6 +17,7 @@
 package t8ntool
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -95,6 +96,7 @@ type stEnv struct {
 	ParentExcessBlobGas   *uint64                             `json:"parentExcessBlobGas,omitempty"`
 	ParentBlobGasUsed     *uint64                             `json:"parentBlobGasUsed,omitempty"`
 	ParentBeaconBlockRoot *common.Hash                        `json:"parentBeaconBlockRoot"`
+	L1Attributes          *l1Attributes                       `json:"l1Attributes,omitempty"`
 }
 
 type stEnvMarshaling struct {
@@ -115,6 +117,44 @@ type stEnvMarshaling struct {
 	ParentBlobGasUsed   *math.HexOrDecimal64
 }
 
+// l1Attributes are the L1 gas attributes of an OP-stack block. If provided, they
+// are written to the L1Block predeploy before any transaction is applied, as an
+// alternative to starting the block with an L1 attributes deposit transaction.
+type l1Attributes struct {
+	BaseFee           *math.HexOrDecimal256 `json:"baseFee"`
+	Overhead          *math.HexOrDecimal256 `json:"overhead,omitempty"`
+	Scalar            *math.HexOrDecimal256 `json:"scalar,omitempty"`
+	BlobBaseFee       *math.HexOrDecimal256 `json:"blobBaseFee,omitempty"`
+	BaseFeeScalar     *math.HexOrDecimal64  `json:"baseFeeScalar,omitempty"`
+	BlobBaseFeeScalar *math.HexOrDecimal64  `json:"blobBaseFeeScalar,omitempty"`
+}
+
+// apply writes the L1 gas attributes to the storage of the L1Block predeploy,
+// where the L1 cost function reads them from.
+func (a *l1Attributes) apply(statedb *state.StateDB) {
+	setSlot := func(slot common.Hash, v *math.HexOrDecimal256) {
+		if v != nil {
+			statedb.SetState(types.L1BlockAddr, slot, common.BigToHash((*big.Int)(v)))
+		}
+	}
+	setSlot(types.L1BaseFeeSlot, a.BaseFee)
+	setSlot(types.OverheadSlot, a.Overhead)
+	setSlot(types.ScalarSlot, a.Scalar)
+	setSlot(types.L1BlobBaseFeeSlot, a.BlobBaseFee)
+
+	// The Ecotone fee scalars share their slot with other values, which are retained.
+	scalars := statedb.GetState(types.L1BlockAddr, types.L1FeeScalarsSlot)
+	if a.BaseFeeScalar != nil {
+		offset := 32 - types.BaseFeeScalarSlotOffset - 4
+		binary.BigEndian.PutUint32(scalars[offset:offset+4], uint32(*a.BaseFeeScalar))
+	}
+	if a.BlobBaseFeeScalar != nil {
+		offset := 32 - types.BlobBaseFeeScalarSlotOffset - 4
+		binary.BigEndian.PutUint32(scalars[offset:offset+4], uint32(*a.BlobBaseFeeScalar))
+	}
+	statedb.SetState(types.L1BlockAddr, types.L1FeeScalarsSlot, scalars)
+}
+
 type rejectedTx struct {
 	Index int    `json:"index"`
 	Err   string `json:"error"`
@@ -160,6 +200,7 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		Difficulty:  pre.Env.Difficulty,
 		GasLimit:    pre.Env.GasLimit,
 		GetHash:     getHash,
+		L1CostFunc:  types.NewL1CostFunc(chainConfig, statedb),
 	}
 	// If currentBaseFee is defined, add it to the vmContext.
 	if pre.Env.BaseFee != nil {
@@ -196,6 +237,13 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		evm := vm.NewEVM(vmContext, vm.TxContext{}, statedb, chainConfig, vmConfig)
 		core.ProcessBeaconBlockRoot(*beaconRoot, evm, statedb)
 	}
+	if l1Attrs := pre.Env.L1Attributes; l1Attrs != nil {
+		// The attributes would be wiped together with an empty account.
+		if statedb.Empty(types.L1BlockAddr) {
+			return nil, nil, nil, NewError(ErrorConfig, fmt.Errorf("l1Attributes provided, but L1Block predeploy %v missing in alloc", types.L1BlockAddr))
+		}
+		l1Attrs.apply(statedb)
+	}
 
 	for i := 0; txIt.Next(); i++ {
 		tx, err := txIt.Tx()
@@ -239,7 +287,13 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 			txContext = core.NewEVMTxContext(msg)
 			snapshot  = statedb.Snapshot()
 			prevGas   = gaspool.Gas()
+			nonce     = tx.Nonce()
 		)
+		// Deposit transactions carry no nonce, the one actually used is recorded
+		// in the receipt from Regolith onwards.
+		if msg.IsDepositTx && chainConfig.IsOptimismRegolith(vmContext.Time) {
+			nonce = statedb.GetNonce(msg.From)
+		}
 		evm := vm.NewEVM(vmContext, txContext, statedb, chainConfig, vmConfig)
 
 		if tracer != nil && tracer.OnTxStart != nil {
@@ -289,9 +343,22 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 			receipt.TxHash = tx.Hash()
 			receipt.GasUsed = msgResult.UsedGas
 
+			if msg.IsDepositTx && chainConfig.IsOptimismRegolith(vmContext.Time) {
+				receipt.DepositNonce = &nonce
+				if chainConfig.IsOptimismCanyon(vmContext.Time) {
+					receipt.DepositReceiptVersion = new(uint64)
+					*receipt.DepositReceiptVersion = types.CanyonDepositReceiptVersion
+				}
+			}
+			// The L1 fee fields are derived from the L1 gas attributes, which are
+			// only changed by deposit transactions.
+			if chainConfig.IsOptimismBedrock(vmContext.BlockNumber) && !msg.IsDepositTx {
+				receipt.SetL1FeeFieldsFromState(chainConfig, statedb, vmContext.Time, tx.RollupCostData())
+			}
+
 			// If the transaction created a contract, store the creation address in the receipt.
 			if msg.To == nil {
-				receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, tx.Nonce())
+				receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, nonce)
 			}
 
 			// Set the receipt logs and create the bloom filter.
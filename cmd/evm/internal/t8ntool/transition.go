@@ -27,6 +27,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
@@ -196,6 +197,9 @@ func Transition(ctx *cli.Context) error {
 	if err := applyCancunChecks(&prestate.Env, chainConfig); err != nil {
 		return err
 	}
+	if err := applyOptimismChecks(&prestate.Env, chainConfig); err != nil {
+		return err
+	}
 	// Run the test and aggregate the result
 	s, result, body, err := prestate.Apply(vmConfig, chainConfig, txIt, ctx.Int64(RewardFlag.Name), getTracer)
 	if err != nil {
@@ -285,6 +289,26 @@ func applyCancunChecks(env *stEnv, chainConfig *params.ChainConfig) error {
 	return nil
 }
 
+func applyOptimismChecks(env *stEnv, chainConfig *params.ChainConfig) error {
+	l1Attrs := env.L1Attributes
+	if l1Attrs == nil {
+		return nil
+	}
+	if !chainConfig.IsOptimismBedrock(new(big.Int).SetUint64(env.Number)) {
+		return NewError(ErrorConfig, errors.New("l1Attributes in env section requires an optimism config"))
+	}
+	if l1Attrs.BaseFee == nil {
+		return NewError(ErrorConfig, errors.New("l1Attributes in env section is missing 'baseFee'"))
+	}
+	if scalar := l1Attrs.BaseFeeScalar; scalar != nil && *scalar > math.MaxUint32 {
+		return NewError(ErrorConfig, fmt.Errorf("l1Attributes 'baseFeeScalar' exceeds 32 bits: %d", *scalar))
+	}
+	if scalar := l1Attrs.BlobBaseFeeScalar; scalar != nil && *scalar > math.MaxUint32 {
+		return NewError(ErrorConfig, fmt.Errorf("l1Attributes 'blobBaseFeeScalar' exceeds 32 bits: %d", *scalar))
+	}
+	return nil
+}
+
 type Alloc map[common.Address]types.Account
 
 func (g Alloc) OnRoot(common.Hash) {}
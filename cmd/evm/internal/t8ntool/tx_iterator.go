@@ -93,8 +93,8 @@ func signUnsignedTransactions(txs []*txWithKey, signer types.Signer) (types.Tran
 			signed  *types.Transaction
 			err     error
 		)
-		if tx.key == nil || v.BitLen()+r.BitLen()+s.BitLen() != 0 {
-			// Already signed
+		if tx.key == nil || v.BitLen()+r.BitLen()+s.BitLen() != 0 || tx.tx.IsDepositTx() {
+			// Already signed, or a deposit which is never signed
 			signedTxs = append(signedTxs, tx.tx)
 			continue
 		}
@@ -77,6 +77,7 @@ var (
 		utils.TxPoolNoLocalsFlag,
 		utils.TxPoolJournalFlag,
 		utils.TxPoolJournalRemotesFlag,
+		utils.TxPoolJournalSizeFlag,
 		utils.TxPoolRejournalFlag,
 		utils.TxPoolPriceLimitFlag,
 		utils.TxPoolPriceBumpFlag,
@@ -85,6 +86,12 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolSenderRateFlag,
+		utils.TxPoolSenderBurstFlag,
+		utils.TxPoolOriginRateFlag,
+		utils.TxPoolOriginBurstFlag,
+		utils.TxPoolOriginHeaderFlag,
+		utils.TxPoolTrustedProxiesFlag,
 		utils.BlobPoolDataDirFlag,
 		utils.BlobPoolDataCapFlag,
 		utils.BlobPoolPriceBumpFlag,
@@ -155,12 +162,18 @@ var (
 		utils.GpoIgnoreGasPriceFlag,
 		utils.GpoMinSuggestedPriorityFeeFlag,
 		utils.RollupSequencerHTTPFlag,
+		utils.RollupSequencerRetriesFlag,
+		utils.RollupSequencerHealthCheckIntervalFlag,
 		utils.RollupHistoricalRPCFlag,
 		utils.RollupHistoricalRPCTimeoutFlag,
 		utils.RollupDisableTxPoolGossipFlag,
 		utils.RollupComputePendingBlock,
 		utils.RollupHaltOnIncompatibleProtocolVersionFlag,
 		utils.RollupSuperchainUpgradesFlag,
+		utils.RollupInteropRPCFlag,
+		utils.RollupPayloadJournalFlag,
+		utils.RollupTxConditionalEnabledFlag,
+		utils.RollupTxConditionalMaxCostFlag,
 		configFileFlag,
 		utils.LogDebugFlag,
 		utils.LogBacktraceAtFlag,
@@ -241,6 +254,8 @@ func init() {
 		removedbCommand,
 		dumpCommand,
 		dumpGenesisCommand,
+		// See replaycmd.go:
+		replayCommand,
 		// See accountcmd.go:
 		accountCommand,
 		walletCommand,
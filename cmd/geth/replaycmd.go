@@ -0,0 +1,288 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/urfave/cli/v2"
+)
+
+var replayCommand = &cli.Command{
+	Action:    replayPayloads,
+	Name:      "replay",
+	Usage:     "Replay derived payload attributes against the local chain",
+	ArgsUsage: "<attributes.jsonl>",
+	Flags: flags.Merge([]cli.Flag{
+		utils.CacheFlag,
+	}, utils.DatabaseFlags),
+	Description: `
+The replay command builds blocks from a JSON-lines file of forkchoice states and
+payload attributes, as derived by the rollup node, through the same block building
+path as the Engine API, without the transaction pool:
+
+  {"forkchoiceState": {...}, "payloadAttributes": {...}, "expectedBlockHash": "0x..."}
+
+Each block is built on top of the head block of its forkchoice state, which must
+be available locally. The expected block hash is required, as only the replayed
+blocks matching it are added to the local database, without changing the head of
+the chain, so that the following attributes can build on top of them. The command stops at the first block whose
+hash differs from the expected one, and reports the differences between the state
+it produced and the state of the expected block, if known locally, or else the
+state changes of the replayed block.`,
+}
+
+// replayEntry is a line of the replay input.
+type replayEntry struct {
+	ForkchoiceState   engine.ForkchoiceStateV1 `json:"forkchoiceState"`
+	PayloadAttributes engine.PayloadAttributes `json:"payloadAttributes"`
+	ExpectedBlockHash common.Hash              `json:"expectedBlockHash"`
+}
+
+// replayBackend is the miner backend of the replay, without transaction pool.
+type replayBackend struct {
+	chain *core.BlockChain
+}
+
+func (b *replayBackend) BlockChain() *core.BlockChain { return b.chain }
+func (b *replayBackend) TxPool() *txpool.TxPool       { return nil }
+
+var (
+	// errReplayMismatch is returned if a replayed block differs from the expected one.
+	errReplayMismatch = errors.New("replayed block mismatch")
+
+	// errReplayNoExpectedHash is returned if the expected block hash is missing
+	// from the replay input.
+	errReplayNoExpectedHash = errors.New("expected block hash missing")
+)
+
+func replayPayloads(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("This command requires the attributes file as the only argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, false)
+	defer db.Close()
+	defer chain.Stop()
+
+	file, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return replay(chain, file, os.Stdout)
+}
+
+// replay builds the blocks of the given payload attributes on top of the chain,
+// and reports the first one differing from the expected block to out.
+func replay(chain *core.BlockChain, input io.Reader, out io.Writer) error {
+	m := miner.New(&replayBackend{chain}, miner.DefaultConfig, chain.Engine())
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry replayEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		// Never add blocks to the database which aren't known to be canonical
+		if entry.ExpectedBlockHash == (common.Hash{}) {
+			return fmt.Errorf("line %d: %w", line, errReplayNoExpectedHash)
+		}
+		block, err := replayEntryBlock(m, &entry)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if block.Hash() != entry.ExpectedBlockHash {
+			fmt.Fprintf(out, "Line %d: block %d mismatch\n", line, block.NumberU64())
+			if err := reportMismatch(chain, block, entry.ExpectedBlockHash, out); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			return fmt.Errorf("%w: block %d", errReplayMismatch, block.NumberU64())
+		}
+		if !chain.HasBlock(block.Hash(), block.NumberU64()) {
+			if err := chain.InsertBlockWithoutSetHead(block); err != nil {
+				return fmt.Errorf("line %d: failed to insert block %d: %w", line, block.NumberU64(), err)
+			}
+		}
+		log.Info("Replayed block", "line", line, "number", block.NumberU64(), "hash", block.Hash(), "txs", len(block.Transactions()))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "All replayed blocks match")
+	return nil
+}
+
+// replayEntryBlock builds the block of a replay entry.
+func replayEntryBlock(m *miner.Miner, entry *replayEntry) (*types.Block, error) {
+	attrs := entry.PayloadAttributes
+
+	txs := make([]*types.Transaction, len(attrs.Transactions))
+	for i, data := range attrs.Transactions {
+		txs[i] = new(types.Transaction)
+		if err := txs[i].UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("transaction %d not valid: %w", i, err)
+		}
+	}
+	payload, err := m.BuildPayload(&miner.BuildPayloadArgs{
+		Parent:        entry.ForkchoiceState.HeadBlockHash,
+		Timestamp:     attrs.Timestamp,
+		FeeRecipient:  attrs.SuggestedFeeRecipient,
+		Random:        attrs.Random,
+		Withdrawals:   attrs.Withdrawals,
+		BeaconRoot:    attrs.BeaconRoot,
+		NoTxPool:      true,
+		Transactions:  txs,
+		GasLimit:      attrs.GasLimit,
+		EIP1559Params: attrs.EIP1559Params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	envelope := payload.ResolveFull()
+	return engine.ExecutableDataToBlock(*envelope.ExecutionPayload, nil, envelope.ParentBeaconBlockRoot)
+}
+
+// touchedState tracks the accounts and storage slots modified by a block.
+type touchedState map[common.Address]map[common.Hash]struct{}
+
+func (t touchedState) hooks() *tracing.Hooks {
+	touch := func(addr common.Address) {
+		if _, ok := t[addr]; !ok {
+			t[addr] = make(map[common.Hash]struct{})
+		}
+	}
+	return &tracing.Hooks{
+		OnBalanceChange: func(addr common.Address, prev, new *big.Int, reason tracing.BalanceChangeReason) { touch(addr) },
+		OnNonceChange:   func(addr common.Address, prev, new uint64) { touch(addr) },
+		OnCodeChange: func(addr common.Address, prevCodeHash common.Hash, prevCode []byte, codeHash common.Hash, code []byte) {
+			touch(addr)
+		},
+		OnStorageChange: func(addr common.Address, slot common.Hash, prev, new common.Hash) {
+			touch(addr)
+			t[addr][slot] = struct{}{}
+		},
+	}
+}
+
+// processBlock executes a block on top of its parent state, tracking the state
+// it modifies.
+func processBlock(chain *core.BlockChain, block *types.Block, touched touchedState) (*state.StateDB, error) {
+	parent := chain.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %x of block %d not found", block.ParentHash(), block.NumberU64())
+	}
+	statedb, err := chain.StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+	statedb.SetLogger(touched.hooks())
+	if _, _, _, err := chain.Processor().Process(block, statedb, vm.Config{}); err != nil {
+		return nil, err
+	}
+	statedb.IntermediateRoot(chain.Config().IsEIP158(block.Number()))
+	return statedb, nil
+}
+
+// reportMismatch writes the differences between a replayed block and the expected
+// one to out. The states are compared if the expected block is known locally,
+// otherwise the state changes of the replayed block are listed.
+func reportMismatch(chain *core.BlockChain, replayed *types.Block, expectedHash common.Hash, out io.Writer) error {
+	touched := make(touchedState)
+	replayedState, err := processBlock(chain, replayed, touched)
+	if err != nil {
+		return fmt.Errorf("failed to execute replayed block: %w", err)
+	}
+	fmt.Fprintf(out, "  hash:       replayed %x, expected %x\n", replayed.Hash(), expectedHash)
+
+	var (
+		otherState *state.StateDB
+		otherName  string
+	)
+	if expected := chain.GetBlockByHash(expectedHash); expected != nil && expected.ParentHash() == replayed.ParentHash() {
+		if otherState, err = processBlock(chain, expected, touched); err != nil {
+			return fmt.Errorf("failed to execute expected block: %w", err)
+		}
+		otherName = "expected"
+		fmt.Fprintf(out, "  state root: replayed %x, expected %x\n", replayed.Root(), expected.Root())
+		fmt.Fprintf(out, "  gas used:   replayed %d, expected %d\n", replayed.GasUsed(), expected.GasUsed())
+		fmt.Fprintf(out, "  txs:        replayed %d, expected %d\n", len(replayed.Transactions()), len(expected.Transactions()))
+	} else {
+		parent := chain.GetHeader(replayed.ParentHash(), replayed.NumberU64()-1)
+		if otherState, err = chain.StateAt(parent.Root); err != nil {
+			return err
+		}
+		otherName = "parent"
+		fmt.Fprintf(out, "  expected block not found locally, listing the state changes of the replayed block\n")
+		fmt.Fprintf(out, "  state root: replayed %x, parent %x\n", replayed.Root(), parent.Root)
+	}
+	addrs := make([]common.Address, 0, len(touched))
+	for addr := range touched {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].Cmp(addrs[j]) < 0 })
+
+	for _, addr := range addrs {
+		if have, want := replayedState.GetBalance(addr), otherState.GetBalance(addr); !have.Eq(want) {
+			fmt.Fprintf(out, "  %x balance: replayed %v, %s %v\n", addr, have, otherName, want)
+		}
+		if have, want := replayedState.GetNonce(addr), otherState.GetNonce(addr); have != want {
+			fmt.Fprintf(out, "  %x nonce: replayed %d, %s %d\n", addr, have, otherName, want)
+		}
+		if have, want := replayedState.GetCodeHash(addr), otherState.GetCodeHash(addr); have != want {
+			fmt.Fprintf(out, "  %x code hash: replayed %x, %s %x\n", addr, have, otherName, want)
+		}
+		slots := make([]common.Hash, 0, len(touched[addr]))
+		for slot := range touched[addr] {
+			slots = append(slots, slot)
+		}
+		sort.Slice(slots, func(i, j int) bool { return slots[i].Cmp(slots[j]) < 0 })
+
+		for _, slot := range slots {
+			if have, want := replayedState.GetState(addr, slot), otherState.GetState(addr, slot); have != want {
+				fmt.Fprintf(out, "  %x storage %x: replayed %x, %s %x\n", addr, slot, have, otherName, want)
+			}
+		}
+	}
+	return nil
+}
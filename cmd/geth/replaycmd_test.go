@@ -0,0 +1,153 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	replayKey, _  = crypto.GenerateKey()
+	replayAddr    = crypto.PubkeyToAddress(replayKey.PublicKey)
+	replayGenesis = &core.Genesis{
+		Config:  params.MergedTestChainConfig,
+		Alloc:   types.GenesisAlloc{replayAddr: {Balance: big.NewInt(params.Ether)}},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+)
+
+func newReplayChain(t *testing.T) *core.BlockChain {
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, replayGenesis, nil, beacon.New(ethash.NewFaker()), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	t.Cleanup(chain.Stop)
+	return chain
+}
+
+// replayTestEntry creates a replay entry forcing a transfer of the given value.
+func replayTestEntry(t *testing.T, parent *types.Header, nonce uint64, value int64) *replayEntry {
+	tx := types.MustSignNewTx(replayKey, types.LatestSigner(params.MergedTestChainConfig), &types.DynamicFeeTx{
+		ChainID:   params.MergedTestChainConfig.ChainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2 * params.InitialBaseFee),
+		Gas:       params.TxGas,
+		To:        &common.Address{0x01},
+		Value:     big.NewInt(value),
+	})
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &replayEntry{
+		ForkchoiceState: engine.ForkchoiceStateV1{HeadBlockHash: parent.Hash()},
+		PayloadAttributes: engine.PayloadAttributes{
+			Timestamp:    parent.Time + 2,
+			Withdrawals:  types.Withdrawals{},
+			BeaconRoot:   &common.Hash{},
+			Transactions: [][]byte{data},
+		},
+	}
+}
+
+func replayInput(t *testing.T, entries ...*replayEntry) *bytes.Buffer {
+	input := new(bytes.Buffer)
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		input.Write(append(data, '\n'))
+	}
+	return input
+}
+
+func TestReplay(t *testing.T) {
+	// Build the expected blocks on a reference chain.
+	reference := newReplayChain(t)
+	m := miner.New(&replayBackend{reference}, miner.DefaultConfig, reference.Engine())
+
+	first := replayTestEntry(t, reference.CurrentBlock(), 0, 1)
+	block1, err := replayEntryBlock(m, first)
+	if err != nil {
+		t.Fatalf("failed to build block 1: %v", err)
+	}
+	if err := reference.InsertBlockWithoutSetHead(block1); err != nil {
+		t.Fatalf("failed to insert block 1: %v", err)
+	}
+	second := replayTestEntry(t, block1.Header(), 1, 2)
+	block2, err := replayEntryBlock(m, second)
+	if err != nil {
+		t.Fatalf("failed to build block 2: %v", err)
+	}
+	// Replaying attributes without the expected block hash is refused.
+	chain := newReplayChain(t)
+	out := new(bytes.Buffer)
+	if err := replay(chain, replayInput(t, first), out); !errors.Is(err, errReplayNoExpectedHash) {
+		t.Fatalf("wrong replay error: %v", err)
+	}
+	if chain.HasBlock(block1.Hash(), 1) {
+		t.Errorf("block without expected hash inserted")
+	}
+	first.ExpectedBlockHash, second.ExpectedBlockHash = block1.Hash(), block2.Hash()
+
+	// Replaying the same attributes on a fresh chain gives the same blocks.
+	if err := replay(chain, replayInput(t, first, second), out); err != nil {
+		t.Fatalf("replay failed: %v\n%s", err, out)
+	}
+	if !chain.HasBlock(block2.Hash(), 2) {
+		t.Errorf("replayed block not inserted")
+	}
+	// Replaying diverging attributes reports the state differences with the
+	// expected block.
+	if err := chain.InsertBlockWithoutSetHead(block2); err != nil {
+		t.Fatalf("failed to insert block 2: %v", err)
+	}
+	diverged := replayTestEntry(t, block1.Header(), 1, 3)
+	diverged.ExpectedBlockHash = block2.Hash()
+
+	out.Reset()
+	if err := replay(chain, replayInput(t, first, diverged), out); !errors.Is(err, errReplayMismatch) {
+		t.Fatalf("wrong replay error: %v", err)
+	}
+	for _, want := range []string{
+		"Line 2: block 2 mismatch",
+		"0100000000000000000000000000000000000000 balance: replayed 4, expected 3", // Transfers of 1 and 3 wei, instead of 1 and 2
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
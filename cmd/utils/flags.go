@@ -166,6 +166,12 @@ var (
 			" datadir is recommended), options: " + strings.Join(params.OPStackChainNames(), ", "),
 		Category: flags.EthCategory,
 	}
+	OPNetworkRegistryFlag = &cli.StringFlag{
+		Name: "op-network.registry",
+		Usage: "Superchain-registry directory or chain configuration file to load OP-Stack networks from, " +
+			"in addition to the pre-configured ones",
+		Category: flags.EthCategory,
+	}
 
 	// Dev mode
 	DeveloperFlag = &cli.BoolFlag{
@@ -397,6 +403,12 @@ var (
 		Usage:    "Includes remote transactions in the journal",
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolJournalSizeFlag = &cli.Uint64Flag{
+		Name:     "txpool.journalsize",
+		Usage:    "Maximum size of the transaction journal in bytes (0 = unlimited)",
+		Value:    ethconfig.Defaults.TxPool.JournalSize,
+		Category: flags.TxPoolCategory,
+	}
 	TxPoolRejournalFlag = &cli.DurationFlag{
 		Name:     "txpool.rejournal",
 		Usage:    "Time interval to regenerate the local transaction journal",
@@ -445,6 +457,40 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Lifetime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolSenderRateFlag = &cli.Float64Flag{
+		Name:     "txpool.senderrate",
+		Usage:    "Maximum number of transactions admitted per second and sender (0 = unlimited)",
+		Value:    ethconfig.Defaults.TxPool.SenderRate,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolSenderBurstFlag = &cli.Uint64Flag{
+		Name:     "txpool.senderburst",
+		Usage:    "Maximum number of transactions admitted at once per sender, above the sender rate",
+		Value:    ethconfig.Defaults.TxPool.SenderBurst,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolOriginRateFlag = &cli.Float64Flag{
+		Name:     "txpool.originrate",
+		Usage:    "Maximum number of transactions admitted per second and RPC client IP (0 = unlimited), the TCP peer unless it is one of --txpool.trustedproxies",
+		Value:    ethconfig.Defaults.TxPoolOriginRate,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolOriginBurstFlag = &cli.IntFlag{
+		Name:     "txpool.originburst",
+		Usage:    "Maximum number of transactions admitted at once per RPC client IP, above the origin rate",
+		Value:    ethconfig.Defaults.TxPoolOriginBurst,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolOriginHeaderFlag = &cli.StringFlag{
+		Name:     "txpool.originheader",
+		Usage:    "Request header holding the RPC client IP set by the trusted reverse proxies, e.g. X-Forwarded-For (the last address is used)",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolTrustedProxiesFlag = &cli.StringFlag{
+		Name:     "txpool.trustedproxies",
+		Usage:    "Comma separated list of IP addresses or CIDR ranges of the reverse proxies trusted to set --txpool.originheader",
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -932,7 +978,19 @@ var (
 	// Rollup Flags
 	RollupSequencerHTTPFlag = &cli.StringFlag{
 		Name:     "rollup.sequencerhttp",
-		Usage:    "HTTP endpoint for the sequencer mempool",
+		Usage:    "HTTP endpoint for the sequencer mempool, or comma separated list of endpoints to fail over between, in order of preference",
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerRetriesFlag = &cli.IntFlag{
+		Name:     "rollup.sequencerretries",
+		Usage:    "Number of times forwarding a transaction to the sequencer is retried, on the next endpoint if several are configured",
+		Value:    ethconfig.Defaults.RollupSequencerRetries,
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerHealthCheckIntervalFlag = &cli.DurationFlag{
+		Name:     "rollup.sequencerhealthcheckinterval",
+		Usage:    "Interval between health checks of the sequencer endpoints (0 = disabled)",
+		Value:    ethconfig.Defaults.RollupSequencerHealthCheckInterval,
 		Category: flags.RollupCategory,
 	}
 
@@ -976,6 +1034,27 @@ var (
 		Category: flags.RollupCategory,
 		Value:    true,
 	}
+	RollupInteropRPCFlag = &cli.StringFlag{
+		Name:     "rollup.interoprpc",
+		Usage:    "RPC endpoint of the interop supervisor, to check cross-chain executing messages of transactions",
+		Category: flags.RollupCategory,
+	}
+	RollupPayloadJournalFlag = &cli.BoolFlag{
+		Name:     "rollup.payloadjournal",
+		Usage:    "Opt-in option to journal the in-flight payloads to the datadir, so that the rollup node can still retrieve them after a restart",
+		Category: flags.RollupCategory,
+	}
+	RollupTxConditionalEnabledFlag = &cli.BoolFlag{
+		Name:     "rollup.txconditional",
+		Usage:    "Opt-in option to accept transactions with inclusion preconditions over RPC (eth_sendRawTransactionConditional)",
+		Category: flags.RollupCategory,
+	}
+	RollupTxConditionalMaxCostFlag = &cli.Uint64Flag{
+		Name:     "rollup.txconditionalmaxcost",
+		Usage:    "Maximum number of checks the preconditions of a transaction submitted over RPC may require",
+		Value:    ethconfig.Defaults.RollupTxConditionalMaxCost,
+		Category: flags.RollupCategory,
+	}
 
 	// Metrics flags
 	MetricsEnabledFlag = &cli.BoolFlag{
@@ -1075,7 +1154,7 @@ var (
 		HoleskyFlag,
 	}
 	// NetworkFlags is the flag group of all built-in supported networks.
-	NetworkFlags = append([]cli.Flag{MainnetFlag, OPNetworkFlag}, TestnetFlags...)
+	NetworkFlags = append([]cli.Flag{MainnetFlag, OPNetworkFlag, OPNetworkRegistryFlag}, TestnetFlags...)
 
 	// DatabaseFlags is the flag group of all database flags.
 	DatabaseFlags = []cli.Flag{
@@ -1306,6 +1385,13 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(BatchResponseMaxSize.Name) {
 		cfg.BatchResponseMaxSize = ctx.Int(BatchResponseMaxSize.Name)
 	}
+
+	if ctx.IsSet(TxPoolOriginHeaderFlag.Name) {
+		cfg.RPCOriginHeader = ctx.String(TxPoolOriginHeaderFlag.Name)
+	}
+	if ctx.IsSet(TxPoolTrustedProxiesFlag.Name) {
+		cfg.RPCTrustedProxies = SplitAndTrim(ctx.String(TxPoolTrustedProxiesFlag.Name))
+	}
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1648,6 +1734,9 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolJournalRemotesFlag.Name) {
 		cfg.JournalRemote = ctx.Bool(TxPoolJournalRemotesFlag.Name)
 	}
+	if ctx.IsSet(TxPoolJournalSizeFlag.Name) {
+		cfg.JournalSize = ctx.Uint64(TxPoolJournalSizeFlag.Name)
+	}
 	if ctx.IsSet(TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.Duration(TxPoolRejournalFlag.Name)
 	}
@@ -1672,6 +1761,12 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolSenderRateFlag.Name) {
+		cfg.SenderRate = ctx.Float64(TxPoolSenderRateFlag.Name)
+	}
+	if ctx.IsSet(TxPoolSenderBurstFlag.Name) {
+		cfg.SenderBurst = ctx.Uint64(TxPoolSenderBurstFlag.Name)
+	}
 	if ctx.IsSet(MinerEffectiveGasLimitFlag.Name) {
 		// While technically this is a miner config parameter, we also want the txpool to enforce
 		// it to avoid accepting transactions that can never be included in a block.
@@ -1794,11 +1889,19 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	CheckExclusive(ctx, MainnetFlag, DeveloperFlag, GoerliFlag, SepoliaFlag, HoleskyFlag, OPNetworkFlag)
 	CheckExclusive(ctx, DeveloperFlag, ExternalSignerFlag) // Can't use both ephemeral unlocked and external signer
 
+	cfg.SuperchainRegistry = MakeSuperchainRegistry(ctx)
+
 	// Set configurations from CLI flags
 	setEtherbase(ctx, cfg)
 	setGPO(ctx, &cfg.GPO)
 	setTxPool(ctx, &cfg.TxPool)
 	setBlobPool(ctx, &cfg.BlobPool)
+	if ctx.IsSet(TxPoolOriginRateFlag.Name) {
+		cfg.TxPoolOriginRate = ctx.Float64(TxPoolOriginRateFlag.Name)
+	}
+	if ctx.IsSet(TxPoolOriginBurstFlag.Name) {
+		cfg.TxPoolOriginBurst = ctx.Int(TxPoolOriginBurstFlag.Name)
+	}
 	setMiner(ctx, &cfg.Miner)
 	setRequiredBlocks(ctx, cfg)
 	setLes(ctx, cfg)
@@ -1945,6 +2048,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(RollupSequencerHTTPFlag.Name) && !ctx.IsSet(MiningEnabledFlag.Name) {
 		cfg.RollupSequencerHTTP = ctx.String(RollupSequencerHTTPFlag.Name)
 	}
+	if ctx.IsSet(RollupSequencerRetriesFlag.Name) {
+		cfg.RollupSequencerRetries = ctx.Int(RollupSequencerRetriesFlag.Name)
+	}
+	if ctx.IsSet(RollupSequencerHealthCheckIntervalFlag.Name) {
+		cfg.RollupSequencerHealthCheckInterval = ctx.Duration(RollupSequencerHealthCheckIntervalFlag.Name)
+	}
 	if ctx.IsSet(RollupHistoricalRPCFlag.Name) {
 		cfg.RollupHistoricalRPC = ctx.String(RollupHistoricalRPCFlag.Name)
 	}
@@ -1955,6 +2064,16 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	cfg.RollupDisableTxPoolAdmission = cfg.RollupSequencerHTTP != "" && !ctx.Bool(RollupEnableTxPoolAdmissionFlag.Name)
 	cfg.RollupHaltOnIncompatibleProtocolVersion = ctx.String(RollupHaltOnIncompatibleProtocolVersionFlag.Name)
 	cfg.ApplySuperchainUpgrades = ctx.Bool(RollupSuperchainUpgradesFlag.Name)
+	if ctx.IsSet(RollupInteropRPCFlag.Name) {
+		cfg.InteropMessageRPC = ctx.String(RollupInteropRPCFlag.Name)
+	}
+	if ctx.Bool(RollupPayloadJournalFlag.Name) {
+		cfg.RollupPayloadJournal = "payloads.json"
+	}
+	cfg.RollupTxConditionalEnabled = ctx.Bool(RollupTxConditionalEnabledFlag.Name)
+	if ctx.IsSet(RollupTxConditionalMaxCostFlag.Name) {
+		cfg.RollupTxConditionalMaxCost = ctx.Uint64(RollupTxConditionalMaxCostFlag.Name)
+	}
 	// Override any default configs for hard coded networks.
 	switch {
 	case ctx.Bool(MainnetFlag.Name):
@@ -2059,7 +2178,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			cfg.Miner.GasPrice = big.NewInt(1)
 		}
 	case ctx.IsSet(OPNetworkFlag.Name):
-		genesis := MakeGenesis(ctx)
+		genesis := makeOPStackGenesis(ctx, cfg.SuperchainRegistry)
 		if !ctx.IsSet(NetworkIdFlag.Name) {
 			cfg.NetworkId = genesis.Config.ChainID.Uint64()
 		}
@@ -2299,6 +2418,8 @@ func DialRPCWithHeaders(endpoint string, headers []string) (*rpc.Client, error)
 }
 
 func MakeGenesis(ctx *cli.Context) *core.Genesis {
+	registry := MakeSuperchainRegistry(ctx)
+
 	var genesis *core.Genesis
 	switch {
 	case ctx.Bool(MainnetFlag.Name):
@@ -2310,22 +2431,46 @@ func MakeGenesis(ctx *cli.Context) *core.Genesis {
 	case ctx.Bool(GoerliFlag.Name):
 		genesis = core.DefaultGoerliGenesisBlock()
 	case ctx.IsSet(OPNetworkFlag.Name):
-		name := ctx.String(OPNetworkFlag.Name)
-		ch, err := params.OPStackChainIDByName(name)
-		if err != nil {
-			Fatalf("failed to load OP-Stack chain %q: %v", name, err)
-		}
-		genesis, err := core.LoadOPStackGenesis(ch)
-		if err != nil {
-			Fatalf("failed to load genesis for OP-Stack chain %q (%d): %v", name, ch, err)
-		}
-		return genesis
+		genesis = makeOPStackGenesis(ctx, registry)
 	case ctx.Bool(DeveloperFlag.Name):
 		Fatalf("Developer chains are ephemeral")
 	}
 	return genesis
 }
 
+// MakeSuperchainRegistry loads the local superchain-registry set with the registry
+// flag, if any. The flag requires --op-network, the only user of the registry.
+func MakeSuperchainRegistry(ctx *cli.Context) *params.SuperchainRegistry {
+	if !ctx.IsSet(OPNetworkRegistryFlag.Name) {
+		return nil
+	}
+	if !ctx.IsSet(OPNetworkFlag.Name) {
+		Fatalf("--%s requires --%s", OPNetworkRegistryFlag.Name, OPNetworkFlag.Name)
+	}
+	path := ctx.String(OPNetworkRegistryFlag.Name)
+	registry, err := params.LoadSuperchainRegistry(path)
+	if err != nil {
+		Fatalf("failed to load superchain registry %q: %v", path, err)
+	}
+	log.Info("Loaded local superchain registry", "path", path, "chains", registry.ChainIDs())
+	return registry
+}
+
+// makeOPStackGenesis loads the genesis of the OP-Stack network selected with
+// --op-network, from the given local superchain-registry or the embedded one.
+func makeOPStackGenesis(ctx *cli.Context, registry *params.SuperchainRegistry) *core.Genesis {
+	name := ctx.String(OPNetworkFlag.Name)
+	ch, err := registry.ChainIDByName(name)
+	if err != nil {
+		Fatalf("failed to load OP-Stack chain %q: %v", name, err)
+	}
+	genesis, err := core.LoadOPStackGenesisFromRegistry(registry, ch)
+	if err != nil {
+		Fatalf("failed to load genesis for OP-Stack chain %q (%d): %v", name, ch, err)
+	}
+	return genesis
+}
+
 // MakeChain creates a chain manager from set command line flags.
 func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*core.BlockChain, ethdb.Database) {
 	var (
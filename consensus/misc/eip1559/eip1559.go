@@ -31,6 +31,7 @@ import (
 // VerifyEIP1559Header verifies some header attributes which were changed in EIP-1559,
 // - gas limit check
 // - basefee check
+// - holocene extra-data check (optimism)
 func VerifyEIP1559Header(config *params.ChainConfig, parent, header *types.Header) error {
 	// Verify that the gas limit remains within allowed bounds
 	parentGasLimit := parent.GasLimit
@@ -46,6 +47,12 @@ func VerifyEIP1559Header(config *params.ChainConfig, parent, header *types.Heade
 	if header.BaseFee == nil {
 		return errors.New("header is missing baseFee")
 	}
+	// Holocene blocks commit to the EIP-1559 parameters of the next block in the extra-data
+	if config.IsOptimismHolocene(header.Time) {
+		if err := ValidateHoloceneExtraData(header.Extra); err != nil {
+			return err
+		}
+	}
 	// Verify the baseFee is correct based on the parent header.
 	expectedBaseFee := CalcBaseFee(config, parent, header.Time)
 	if header.BaseFee.Cmp(expectedBaseFee) != 0 {
@@ -63,7 +70,20 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, time uint64)
 		return new(big.Int).SetUint64(params.InitialBaseFee)
 	}
 
-	parentGasTarget := parent.GasLimit / config.ElasticityMultiplier()
+	var (
+		elasticity  = config.ElasticityMultiplier()
+		denominator = config.BaseFeeChangeDenominator(time)
+	)
+	// Post-Holocene, the parameters are taken from the parent extra-data. The
+	// genesis block has no such commitment and falls back to the chain config.
+	if config.IsOptimismHolocene(parent.Time) && !isOptimismGenesis(config, parent) {
+		denominator, elasticity = DecodeHoloceneExtraData(parent.Extra)
+		if denominator == 0 || elasticity == 0 {
+			// This can't happen as the extra-data is validated on header verification
+			panic("invalid holocene eip-1559 params in parent extra-data")
+		}
+	}
+	parentGasTarget := parent.GasLimit / elasticity
 	// If the parent gasUsed is the same as the target, the baseFee remains unchanged.
 	if parent.GasUsed == parentGasTarget {
 		return new(big.Int).Set(parent.BaseFee)
@@ -80,7 +100,7 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, time uint64)
 		num.SetUint64(parent.GasUsed - parentGasTarget)
 		num.Mul(num, parent.BaseFee)
 		num.Div(num, denom.SetUint64(parentGasTarget))
-		num.Div(num, denom.SetUint64(config.BaseFeeChangeDenominator(time)))
+		num.Div(num, denom.SetUint64(denominator))
 		baseFeeDelta := math.BigMax(num, common.Big1)
 
 		return num.Add(parent.BaseFee, baseFeeDelta)
@@ -90,9 +110,15 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, time uint64)
 		num.SetUint64(parentGasTarget - parent.GasUsed)
 		num.Mul(num, parent.BaseFee)
 		num.Div(num, denom.SetUint64(parentGasTarget))
-		num.Div(num, denom.SetUint64(config.BaseFeeChangeDenominator(time)))
+		num.Div(num, denom.SetUint64(denominator))
 		baseFee := num.Sub(parent.BaseFee, num)
 
 		return math.BigMax(baseFee, common.Big0)
 	}
 }
+
+// isOptimismGenesis reports whether the header is the genesis block of an OP Stack
+// chain: block 0, or the Bedrock block of the chains migrated from a legacy one.
+func isOptimismGenesis(config *params.ChainConfig, header *types.Header) bool {
+	return header.Number.Sign() == 0 || (config.BedrockBlock != nil && header.Number.Cmp(config.BedrockBlock) == 0)
+}
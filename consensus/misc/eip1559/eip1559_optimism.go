@@ -0,0 +1,108 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eip1559
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// HoloceneParamsLength is the length of the EIP-1559 parameters in the
+	// Holocene payload attributes: denominator (uint32) || elasticity (uint32).
+	HoloceneParamsLength = 8
+
+	// HoloceneExtraDataLength is the length of the Holocene header extra-data:
+	// version (uint8) || denominator (uint32) || elasticity (uint32).
+	HoloceneExtraDataLength = 1 + HoloceneParamsLength
+
+	// HoloceneExtraDataVersion is the only supported version byte of the
+	// Holocene header extra-data.
+	HoloceneExtraDataVersion = 0
+)
+
+// DecodeHolocene1559Params extracts the Holocene EIP-1559 parameters from the
+// encoded form used in the payload attributes. It returns 0, 0 if the encoding
+// is malformed; ValidateHolocene1559Params should be used for validity checks.
+func DecodeHolocene1559Params(params []byte) (uint64, uint64) {
+	if len(params) != HoloceneParamsLength {
+		return 0, 0
+	}
+	denominator := binary.BigEndian.Uint32(params[:4])
+	elasticity := binary.BigEndian.Uint32(params[4:])
+	return uint64(denominator), uint64(elasticity)
+}
+
+// EncodeHolocene1559Params encodes the EIP-1559 parameters into the form used
+// in the payload attributes.
+func EncodeHolocene1559Params(denominator, elasticity uint32) []byte {
+	params := make([]byte, HoloceneParamsLength)
+	binary.BigEndian.PutUint32(params[:4], denominator)
+	binary.BigEndian.PutUint32(params[4:], elasticity)
+	return params
+}
+
+// ValidateHolocene1559Params checks that the encoded payload-attribute
+// parameters are well formed. Both values being zero is valid and signals
+// that the chain-config defaults should be used instead.
+func ValidateHolocene1559Params(params []byte) error {
+	if len(params) != HoloceneParamsLength {
+		return fmt.Errorf("holocene eip-1559 params should be %d bytes, got %d", HoloceneParamsLength, len(params))
+	}
+	denominator, elasticity := DecodeHolocene1559Params(params)
+	if (denominator == 0) != (elasticity == 0) {
+		return errors.New("holocene eip-1559 params must be both zero or both non-zero")
+	}
+	return nil
+}
+
+// DecodeHoloceneExtraData extracts the EIP-1559 parameters from the header
+// extra-data of a Holocene block. It returns 0, 0 if the encoding is malformed;
+// ValidateHoloceneExtraData should be used for validity checks.
+func DecodeHoloceneExtraData(extra []byte) (uint64, uint64) {
+	if len(extra) != HoloceneExtraDataLength {
+		return 0, 0
+	}
+	return DecodeHolocene1559Params(extra[1:])
+}
+
+// EncodeHoloceneExtraData encodes the EIP-1559 parameters into the header
+// extra-data format of a Holocene block.
+func EncodeHoloceneExtraData(denominator, elasticity uint32) []byte {
+	extra := make([]byte, HoloceneExtraDataLength)
+	extra[0] = HoloceneExtraDataVersion
+	copy(extra[1:], EncodeHolocene1559Params(denominator, elasticity))
+	return extra
+}
+
+// ValidateHoloceneExtraData checks that the header extra-data of a Holocene
+// block is well formed. Contrary to the payload attributes, the parameters
+// committed to in the header must be non-zero.
+func ValidateHoloceneExtraData(extra []byte) error {
+	if len(extra) != HoloceneExtraDataLength {
+		return fmt.Errorf("holocene extra-data should be %d bytes, got %d", HoloceneExtraDataLength, len(extra))
+	}
+	if extra[0] != HoloceneExtraDataVersion {
+		return fmt.Errorf("holocene extra-data has unsupported version %d", extra[0])
+	}
+	denominator, elasticity := DecodeHoloceneExtraData(extra)
+	if denominator == 0 || elasticity == 0 {
+		return fmt.Errorf("holocene extra-data has zero eip-1559 params: denominator %d, elasticity %d", denominator, elasticity)
+	}
+	return nil
+}
@@ -176,3 +176,93 @@ func TestCalcBaseFeeOptimism(t *testing.T) {
 		}
 	}
 }
+
+// TestCalcBaseFeeOptimismHolocene tests that the EIP-1559 parameters are taken
+// from the parent extra-data once Holocene is active.
+func TestCalcBaseFeeOptimismHolocene(t *testing.T) {
+	holoceneConfig := opConfig()
+	holoceneTime := uint64(12)
+	holoceneConfig.HoloceneTime = &holoceneTime
+
+	tests := []struct {
+		parentTime      uint64
+		parentExtra     []byte
+		parentGasUsed   uint64
+		expectedBaseFee int64
+	}{
+		{10, nil, 10_000_000, 1004000000},                                        // pre-Holocene parent, config params
+		{12, EncodeHoloceneExtraData(250, 6), 10_000_000, 1004000000},            // same params as config
+		{12, EncodeHoloceneExtraData(50, 6), 10_000_000, 1020000000},             // lower denominator
+		{12, EncodeHoloceneExtraData(250, 3), 10_000_000, params.InitialBaseFee}, // usage == target with new elasticity
+		{12, EncodeHoloceneExtraData(250, 3), 5_000_000, 998000000},              // usage below new target
+	}
+	for i, test := range tests {
+		parent := &types.Header{
+			Number:   common.Big32,
+			GasLimit: 30_000_000,
+			GasUsed:  test.parentGasUsed,
+			BaseFee:  big.NewInt(params.InitialBaseFee),
+			Time:     test.parentTime,
+			Extra:    test.parentExtra,
+		}
+		if have, want := CalcBaseFee(holoceneConfig, parent, parent.Time+2), big.NewInt(test.expectedBaseFee); have.Cmp(want) != 0 {
+			t.Errorf("test %d: have %d  want %d, ", i, have, want)
+		}
+	}
+}
+
+// TestCalcBaseFeeOptimismHoloceneGenesis tests that the EIP-1559 parameters of
+// a Holocene genesis, which has no extra-data commitment, are taken from the
+// chain config, also if the genesis is not block 0.
+func TestCalcBaseFeeOptimismHoloceneGenesis(t *testing.T) {
+	holoceneConfig := opConfig()
+	holoceneTime := uint64(0)
+	holoceneConfig.HoloceneTime = &holoceneTime
+	holoceneConfig.LondonBlock = common.Big0
+	holoceneConfig.BedrockBlock = common.Big32
+
+	for _, number := range []*big.Int{common.Big0, common.Big32} {
+		parent := &types.Header{
+			Number:   number,
+			GasLimit: 30_000_000,
+			GasUsed:  10_000_000,
+			BaseFee:  big.NewInt(params.InitialBaseFee),
+			Time:     12,
+		}
+		if have, want := CalcBaseFee(holoceneConfig, parent, parent.Time+2), big.NewInt(1004000000); have.Cmp(want) != 0 {
+			t.Errorf("genesis %d: have %d  want %d, ", number, have, want)
+		}
+	}
+}
+
+// TestHoloceneExtraData tests the encoding and validation of the Holocene
+// EIP-1559 parameters and header extra-data.
+func TestHoloceneExtraData(t *testing.T) {
+	extra := EncodeHoloceneExtraData(250, 6)
+	if err := ValidateHoloceneExtraData(extra); err != nil {
+		t.Fatalf("valid extra-data rejected: %v", err)
+	}
+	if d, e := DecodeHoloceneExtraData(extra); d != 250 || e != 6 {
+		t.Fatalf("decoded params mismatch: have %d/%d, want 250/6", d, e)
+	}
+	for i, bad := range [][]byte{
+		nil,
+		extra[:8],
+		append([]byte{1}, extra[1:]...),
+		EncodeHoloceneExtraData(0, 0),
+		EncodeHoloceneExtraData(250, 0),
+	} {
+		if err := ValidateHoloceneExtraData(bad); err == nil {
+			t.Errorf("test %d: invalid extra-data accepted", i)
+		}
+	}
+	if err := ValidateHolocene1559Params(EncodeHolocene1559Params(0, 0)); err != nil {
+		t.Errorf("zero params rejected: %v", err)
+	}
+	if err := ValidateHolocene1559Params(EncodeHolocene1559Params(0, 6)); err == nil {
+		t.Error("zero denominator accepted")
+	}
+	if err := ValidateHolocene1559Params(EncodeHolocene1559Params(250, 6)[:7]); err == nil {
+		t.Error("short params accepted")
+	}
+}
@@ -536,6 +536,9 @@ func (cm *chainMaker) makeHeader(parent *types.Block, state *state.StateDB, engi
 		Number:     new(big.Int).Add(parent.Number(), common.Big1),
 		Time:       time,
 	}
+	if cm.config.IsOptimismHolocene(header.Time) {
+		header.Extra = eip1559.EncodeHoloceneExtraData(uint32(cm.config.BaseFeeChangeDenominator(header.Time)), uint32(cm.config.ElasticityMultiplier()))
+	}
 
 	if cm.config.IsLondon(header.Number) {
 		header.BaseFee = eip1559.CalcBaseFee(cm.config, parent.Header(), header.Time)
@@ -24,8 +24,6 @@ import (
 	"math/big"
 	"strings"
 
-	"github.com/ethereum-optimism/superchain-registry/superchain"
-
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -265,6 +263,7 @@ type ChainOverrides struct {
 	OverrideOptimismHolocene *uint64
 	OverrideOptimismInterop  *uint64
 	ApplySuperchainUpgrades  bool
+	SuperchainRegistry       *params.SuperchainRegistry // Local superchain-registry to apply the upgrades of, on top of the embedded one
 }
 
 // SetupGenesisBlock writes or updates the genesis block in db.
@@ -293,8 +292,8 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, triedb *triedb.Database, g
 			// If applying the superchain-registry to a known OP-Stack chain,
 			// then override the local chain-config with that from the registry.
 			if overrides != nil && overrides.ApplySuperchainUpgrades && config.IsOptimism() && config.ChainID != nil && config.ChainID.IsUint64() {
-				if _, ok := superchain.OPChains[config.ChainID.Uint64()]; ok {
-					conf, err := params.LoadOPStackChainConfig(config.ChainID.Uint64())
+				if _, ok := overrides.SuperchainRegistry.OPChain(config.ChainID.Uint64()); ok {
+					conf, err := overrides.SuperchainRegistry.ChainConfig(config.ChainID.Uint64())
 					if err != nil {
 						log.Warn("failed to load chain config from superchain-registry, skipping override", "err", err, "chain_id", config.ChainID)
 					} else {
@@ -402,9 +401,11 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, triedb *triedb.Database, g
 	// mainnet hash in the database), we must not apply the `configOrDefault`
 	// chain config as that would be AllProtocolChanges (applying any new fork
 	// on top of an existing private network genesis block). In that case, only
-	// apply the overrides.
+	// apply the overrides. These are applied to a copy, so that the configs
+	// taken from a superchain-registry are still checked for compatibility.
 	if genesis == nil && stored != params.MainnetGenesisHash {
-		newcfg = storedcfg
+		cpy := *storedcfg
+		newcfg = &cpy
 		applyOverrides(newcfg)
 	}
 	// Check config compatibility and write the config. Compatibility errors
@@ -42,6 +42,8 @@ type journalEntry interface {
 type journal struct {
 	entries []journalEntry         // Current changes tracked by the journal
 	dirties map[common.Address]int // Dirty accounts and the number of changes
+
+	onDirty func(addr common.Address) // Optional hook invoked before an account is changed
 }
 
 // newJournal creates a new initialized journal.
@@ -53,10 +55,13 @@ func newJournal() *journal {
 
 // append inserts a new modification entry to the end of the change journal.
 func (j *journal) append(entry journalEntry) {
-	j.entries = append(j.entries, entry)
 	if addr := entry.dirtied(); addr != nil {
+		if j.onDirty != nil {
+			j.onDirty(*addr)
+		}
 		j.dirties[*addr]++
 	}
+	j.entries = append(j.entries, entry)
 }
 
 // revert undoes a batch of journalled modifications along with any reverted
@@ -80,6 +85,9 @@ func (j *journal) revert(statedb *StateDB, snapshot int) {
 // otherwise suggest it as clean. This method is an ugly hack to handle the RIPEMD
 // precompile consensus exception.
 func (j *journal) dirty(addr common.Address) {
+	if j.onDirty != nil {
+		j.onDirty(addr)
+	}
 	j.dirties[addr]++
 }
 
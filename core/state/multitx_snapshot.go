@@ -0,0 +1,141 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// multiTxSnapshot tracks the state of the accounts changed by a sequence of
+// transactions, as it was before their first change, to allow reverting all of
+// the transactions together. Unlike the journal, the tracked state survives the
+// finalisation of the transactions, so only the touched accounts are copied
+// instead of the whole state.
+type multiTxSnapshot struct {
+	accounts map[common.Address]*multiTxAccount // State of the changed accounts before the snapshot
+	logs     map[common.Hash]int                // Number of logs of the transactions before the snapshot
+	logSize  uint                               // Number of logs in the block before the snapshot
+
+	preimages []common.Hash // Preimages added since the snapshot
+}
+
+// multiTxAccount is the state of an account before a multi-transaction snapshot.
+type multiTxAccount struct {
+	object   *stateObject // Deep copy of the live object, nil if it wasn't live
+	destruct *stateObject // Original account if destructed earlier in the block
+	mutation *mutation    // Pending mutation of the account, nil if there wasn't one
+
+	destructed bool // Whether the account was destructed earlier in the block
+}
+
+// trackAccount stores the state of an account about to be changed, unless it
+// was stored already.
+func (ms *multiTxSnapshot) trackAccount(s *StateDB, addr common.Address) {
+	if _, ok := ms.accounts[addr]; ok {
+		return
+	}
+	account := new(multiTxAccount)
+	if obj := s.stateObjects[addr]; obj != nil {
+		account.object = obj.deepCopy(s)
+	}
+	account.destruct, account.destructed = s.stateObjectsDestruct[addr]
+	if m := s.mutations[addr]; m != nil {
+		account.mutation = m.copy()
+	}
+	ms.accounts[addr] = account
+}
+
+// trackLogs stores the number of logs of a transaction about to emit one,
+// unless it was stored already.
+func (ms *multiTxSnapshot) trackLogs(s *StateDB, thash common.Hash) {
+	if _, ok := ms.logs[thash]; !ok {
+		ms.logs[thash] = len(s.logs[thash])
+	}
+}
+
+// MultiTxSnapshot starts tracking the changes of the following transactions, to
+// allow reverting all of them together with RevertMultiTxSnapshot. It must be
+// taken between transactions, and ended by either RevertMultiTxSnapshot or
+// DiscardMultiTxSnapshot.
+//
+// Unlike Snapshot, the revision survives the finalisation of the transactions,
+// but not the computation of an intermediate root: it is meant for blocks past
+// the Byzantium fork, where the state is only finalised between transactions.
+func (s *StateDB) MultiTxSnapshot() {
+	if s.multiTxSnapshot != nil {
+		panic("multi-transaction snapshot already taken")
+	}
+	ms := &multiTxSnapshot{
+		accounts: make(map[common.Address]*multiTxAccount),
+		logs:     make(map[common.Hash]int),
+		logSize:  s.logSize,
+	}
+	s.multiTxSnapshot = ms
+	s.journal.onDirty = func(addr common.Address) {
+		ms.trackAccount(s, addr)
+	}
+}
+
+// RevertMultiTxSnapshot reverts all state changes made since the last call to
+// MultiTxSnapshot, and ends the snapshot.
+func (s *StateDB) RevertMultiTxSnapshot() {
+	ms := s.multiTxSnapshot
+	if ms == nil {
+		panic("no multi-transaction snapshot taken")
+	}
+	s.DiscardMultiTxSnapshot()
+
+	// Drop the changes of the transaction in progress, if any
+	s.clearJournalAndRefund()
+
+	for addr, account := range ms.accounts {
+		if account.object != nil {
+			s.stateObjects[addr] = account.object
+		} else {
+			delete(s.stateObjects, addr)
+		}
+		if account.destructed {
+			s.stateObjectsDestruct[addr] = account.destruct
+		} else {
+			delete(s.stateObjectsDestruct, addr)
+		}
+		if account.mutation != nil {
+			s.mutations[addr] = account.mutation
+		} else {
+			delete(s.mutations, addr)
+		}
+	}
+	for thash, n := range ms.logs {
+		if n == 0 {
+			delete(s.logs, thash)
+		} else {
+			s.logs[thash] = s.logs[thash][:n]
+		}
+	}
+	s.logSize = ms.logSize
+
+	for _, hash := range ms.preimages {
+		delete(s.preimages, hash)
+	}
+}
+
+// DiscardMultiTxSnapshot ends the last multi-transaction snapshot, keeping the
+// changes made since.
+func (s *StateDB) DiscardMultiTxSnapshot() {
+	s.multiTxSnapshot = nil
+	s.journal.onDirty = nil
+}
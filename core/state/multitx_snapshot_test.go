@@ -0,0 +1,131 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+// Tests that reverting a multi-transaction snapshot undoes the changes of all
+// the finalised transactions since, and keeps the earlier ones.
+func TestMultiTxSnapshotRevert(t *testing.T) {
+	var (
+		kept      = common.Address{0x01}
+		changed   = common.Address{0x02}
+		destroyed = common.Address{0x03}
+		created   = common.Address{0x04}
+		gone      = common.Address{0x05}
+	)
+	state, _ := New(types.EmptyRootHash, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	for _, addr := range []common.Address{kept, changed, destroyed, gone} {
+		state.SetBalance(addr, uint256.NewInt(100), tracing.BalanceChangeUnspecified)
+		state.SetState(addr, common.Hash{0x01}, common.Hash{0x01})
+	}
+	state.SetCode(destroyed, []byte{0x01})
+	root, _ := state.Commit(0, false)
+	state, _ = New(root, state.db, nil)
+
+	// Apply a transaction before the snapshot, destructing an account
+	state.SetTxContext(common.Hash{0x01}, 0)
+	state.AddBalance(kept, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+	state.SelfDestruct(gone)
+	state.AddLog(&types.Log{Address: kept})
+	state.Finalise(true)
+
+	want := state.Copy().IntermediateRoot(true)
+
+	// Apply two transactions on top of the snapshot, and revert them
+	state.MultiTxSnapshot()
+
+	state.SetTxContext(common.Hash{0x02}, 1)
+	state.SubBalance(changed, uint256.NewInt(50), tracing.BalanceChangeUnspecified)
+	state.SetState(changed, common.Hash{0x01}, common.Hash{0x02})
+	state.SetState(changed, common.Hash{0x02}, common.Hash{0x02})
+	state.CreateAccount(created)
+	state.CreateContract(created)
+	state.SetCode(created, []byte{0x02})
+	state.AddLog(&types.Log{Address: changed})
+	state.AddPreimage(common.Hash{0x02}, []byte{0x02})
+	state.Finalise(true)
+
+	state.SetTxContext(common.Hash{0x03}, 2)
+	state.SelfDestruct(destroyed)
+	state.AddBalance(gone, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+	state.SetState(changed, common.Hash{0x01}, common.Hash{0x03})
+	state.AddLog(&types.Log{Address: destroyed})
+	state.Finalise(true)
+
+	// Leave a transaction in progress too
+	state.SetTxContext(common.Hash{0x04}, 3)
+	state.AddBalance(kept, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+
+	state.RevertMultiTxSnapshot()
+
+	if have := state.IntermediateRoot(true); have != want {
+		t.Errorf("wrong root after revert: have %x, want %x", have, want)
+	}
+	if have := state.GetState(changed, common.Hash{0x01}); have != (common.Hash{0x01}) {
+		t.Errorf("wrong storage after revert: have %x, want %x", have, common.Hash{0x01})
+	}
+	if state.Exist(created) {
+		t.Errorf("created account exists after revert")
+	}
+	if state.Exist(gone) {
+		t.Errorf("account destructed before the snapshot exists after revert")
+	}
+	if have := len(state.Logs()); have != 1 {
+		t.Errorf("wrong number of logs after revert: have %d, want 1", have)
+	}
+	if _, ok := state.Preimages()[common.Hash{0x02}]; ok {
+		t.Errorf("preimage kept after revert")
+	}
+	// The state remains usable after the revert
+	state.SetTxContext(common.Hash{0x05}, 1)
+	state.AddLog(&types.Log{Address: kept})
+	if have := state.GetLogs(common.Hash{0x05}, 0, common.Hash{})[0].Index; have != 1 {
+		t.Errorf("wrong log index after revert: have %d, want 1", have)
+	}
+}
+
+// Tests that discarding a multi-transaction snapshot keeps the changes since.
+func TestMultiTxSnapshotDiscard(t *testing.T) {
+	addr := common.Address{0x01}
+	state, _ := New(types.EmptyRootHash, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	state.MultiTxSnapshot()
+	state.AddBalance(addr, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+	state.Finalise(true)
+	state.DiscardMultiTxSnapshot()
+
+	if have := state.GetBalance(addr); !have.Eq(uint256.NewInt(1)) {
+		t.Errorf("wrong balance after discard: have %v, want 1", have)
+	}
+	// A new snapshot may be taken after the previous one ended
+	state.MultiTxSnapshot()
+	state.AddBalance(addr, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+	state.RevertMultiTxSnapshot()
+
+	if have := state.GetBalance(addr); !have.Eq(uint256.NewInt(1)) {
+		t.Errorf("wrong balance after revert: have %v, want 1", have)
+	}
+}
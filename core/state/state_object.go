@@ -402,6 +402,43 @@ func (s *stateObject) updateTrie() (Trie, error) {
 	return tr, nil
 }
 
+// intermediateRoot computes the storage root of the object including its pending
+// and dirty storage changes. Unlike updateRoot, the changes are applied to a copy
+// of the storage trie, leaving the object and the prefetcher untouched.
+func (s *stateObject) intermediateRoot() (common.Hash, error) {
+	if len(s.uncommittedStorage) == 0 && len(s.dirtyStorage) == 0 {
+		return s.data.Root, nil
+	}
+	var tr Trie
+	if s.trie != nil {
+		tr = s.db.db.CopyTrie(s.trie)
+	} else {
+		var err error
+		if tr, err = s.db.db.OpenStorageTrie(s.db.originalRoot, s.address, s.data.Root, s.db.trie); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	changes := make(Storage, len(s.uncommittedStorage)+len(s.dirtyStorage))
+	for key := range s.uncommittedStorage {
+		changes[key] = s.pendingStorage[key]
+	}
+	for key, value := range s.dirtyStorage {
+		changes[key] = value
+	}
+	for key, value := range changes {
+		var err error
+		if (value != common.Hash{}) {
+			err = tr.UpdateStorage(s.address, key[:], common.TrimLeftZeroes(value[:]))
+		} else {
+			err = tr.DeleteStorage(s.address, key[:])
+		}
+		if err != nil {
+			return common.Hash{}, err
+		}
+	}
+	return tr.Hash(), nil
+}
+
 // updateRoot flushes all cached storage mutations to trie, recalculating the
 // new storage trie root.
 func (s *stateObject) updateRoot() {
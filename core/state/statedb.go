@@ -146,6 +146,9 @@ type StateDB struct {
 	validRevisions []revision
 	nextRevisionId int
 
+	// Changes of multiple transactions, tracked to be reverted together.
+	multiTxSnapshot *multiTxSnapshot
+
 	// State witness if cross validation is needed
 	witness *stateless.Witness
 
@@ -262,6 +265,9 @@ func (s *StateDB) Error() error {
 }
 
 func (s *StateDB) AddLog(log *types.Log) {
+	if s.multiTxSnapshot != nil {
+		s.multiTxSnapshot.trackLogs(s, s.thash)
+	}
 	s.journal.append(addLogChange{txhash: s.thash})
 
 	log.TxHash = s.thash
@@ -296,6 +302,9 @@ func (s *StateDB) Logs() []*types.Log {
 // AddPreimage records a SHA3 preimage seen by the VM.
 func (s *StateDB) AddPreimage(hash common.Hash, preimage []byte) {
 	if _, ok := s.preimages[hash]; !ok {
+		if s.multiTxSnapshot != nil {
+			s.multiTxSnapshot.preimages = append(s.multiTxSnapshot.preimages, hash)
+		}
 		s.journal.append(addPreimageChange{hash: hash})
 		s.preimages[hash] = slices.Clone(preimage)
 	}
@@ -690,6 +699,9 @@ func (s *StateDB) CreateAccount(addr common.Address) {
 func (s *StateDB) CreateContract(addr common.Address) {
 	obj := s.getStateObject(addr)
 	if !obj.newContract {
+		if s.multiTxSnapshot != nil {
+			s.multiTxSnapshot.trackAccount(s, addr)
+		}
 		obj.newContract = true
 		s.journal.append(createContractChange{account: addr})
 	}
@@ -996,7 +1008,9 @@ func (s *StateDB) SetTxContext(thash common.Hash, ti int) {
 
 func (s *StateDB) clearJournalAndRefund() {
 	if len(s.journal.entries) > 0 {
+		onDirty := s.journal.onDirty
 		s.journal = newJournal()
+		s.journal.onDirty = onDirty
 		s.refund = 0
 	}
 	s.validRevisions = s.validRevisions[:0] // Snapshots can be created without journal entries
@@ -1490,3 +1504,31 @@ func (s *StateDB) PointCache() *utils.PointCache {
 func (s *StateDB) Witness() *stateless.Witness {
 	return s.witness
 }
+
+// CheckTransactionConditional checks the known accounts of a transaction
+// conditional against the state. The storage roots are computed including the
+// changes made since the last intermediate root, and missing accounts have the
+// root of an empty storage.
+func (s *StateDB) CheckTransactionConditional(cond *types.TransactionConditional) error {
+	for addr, account := range cond.KnownAccounts {
+		if account.StorageRoot != nil {
+			root := types.EmptyRootHash
+			if obj := s.getStateObject(addr); obj != nil {
+				var err error
+				if root, err = obj.intermediateRoot(); err != nil {
+					return fmt.Errorf("failed to compute storage root for %s: %w", addr, err)
+				}
+			}
+			if root != *account.StorageRoot {
+				return fmt.Errorf("storage root mismatch for %s: have %s, want %s", addr, root, *account.StorageRoot)
+			}
+			continue
+		}
+		for slot, want := range account.StorageSlots {
+			if have := s.GetState(addr, slot); have != want {
+				return fmt.Errorf("storage slot %s mismatch for %s: have %s, want %s", slot, addr, have, want)
+			}
+		}
+	}
+	return nil
+}
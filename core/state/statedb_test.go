@@ -1373,3 +1373,53 @@ func TestStorageDirtiness(t *testing.T) {
 	state.RevertToSnapshot(snap)
 	checkDirty(common.Hash{0x1}, common.Hash{0x1}, true)
 }
+
+// Tests that the storage roots of a transaction conditional are checked against
+// the state including the changes since the last intermediate root.
+func TestCheckTransactionConditional(t *testing.T) {
+	var (
+		addr    = common.Address{0x01}
+		missing = common.Address{0x02}
+		sdb     = NewDatabase(rawdb.NewMemoryDatabase())
+	)
+	state, _ := New(types.EmptyRootHash, sdb, nil)
+	state.SetNonce(addr, 1)
+	state.SetState(addr, common.Hash{0x01}, common.Hash{0x01})
+	root, _ := state.Commit(0, false)
+	state, _ = New(root, sdb, nil)
+
+	check := func(addr common.Address, root common.Hash) error {
+		return state.CheckTransactionConditional(&types.TransactionConditional{
+			KnownAccounts: types.KnownAccounts{addr: {StorageRoot: &root}},
+		})
+	}
+	committed := state.GetStorageRoot(addr)
+	if err := check(addr, committed); err != nil {
+		t.Fatalf("committed root rejected: %v", err)
+	}
+	if err := check(missing, types.EmptyRootHash); err != nil {
+		t.Fatalf("empty root of missing account rejected: %v", err)
+	}
+	// Modify the storage in a finalised and then a dirty transaction
+	for i, value := range []common.Hash{{0x02}, {0x03}} {
+		state.SetState(addr, common.Hash{0x02}, value)
+		if i == 0 {
+			state.Finalise(true)
+		}
+		cpy := state.Copy()
+		cpy.IntermediateRoot(true)
+		want := cpy.GetStorageRoot(addr)
+
+		if err := check(addr, want); err != nil {
+			t.Errorf("change %d: intermediate root rejected: %v", i, err)
+		}
+		if err := check(addr, committed); err == nil {
+			t.Errorf("change %d: stale root accepted", i)
+		}
+	}
+	// The checks leave the state untouched
+	want := state.Copy().IntermediateRoot(true)
+	if have := state.IntermediateRoot(true); have != want {
+		t.Fatalf("state root mismatch after the checks: have %x, want %x", have, want)
+	}
+}
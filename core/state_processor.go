@@ -109,6 +109,10 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 // and uses the input parameters for its environment similar to ApplyTransaction. However,
 // this method takes an already created EVM instance as input.
 func ApplyTransactionWithEVM(msg *Message, config *params.ChainConfig, gp *GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64, evm *vm.EVM) (receipt *types.Receipt, err error) {
+	return applyTransactionWithEVM(msg, config, gp, statedb, blockNumber, blockHash, tx, usedGas, evm, nil)
+}
+
+func applyTransactionWithEVM(msg *Message, config *params.ChainConfig, gp *GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64, evm *vm.EVM, opts *ApplyTransactionOpts) (receipt *types.Receipt, err error) {
 	if evm.Config.Tracer != nil && evm.Config.Tracer.OnTxStart != nil {
 		evm.Config.Tracer.OnTxStart(evm.GetVMContext(), tx, msg.From)
 		if evm.Config.Tracer.OnTxEnd != nil {
@@ -131,6 +135,11 @@ func ApplyTransactionWithEVM(msg *Message, config *params.ChainConfig, gp *GasPo
 	if err != nil {
 		return nil, err
 	}
+	if opts != nil && opts.PostValidation != nil {
+		if err := opts.PostValidation(evm, result); err != nil {
+			return nil, err
+		}
+	}
 
 	// Update the state with pending changes.
 	var root []byte
@@ -182,6 +191,28 @@ func ApplyTransactionWithEVM(msg *Message, config *params.ChainConfig, gp *GasPo
 	return receipt, err
 }
 
+// ApplyTransactionOpts are optional extensions of the transaction application,
+// used during block building.
+type ApplyTransactionOpts struct {
+	// PostValidation is called after the message has been applied, but before the
+	// state is finalised. Returning an error rejects the transaction, in which case
+	// the caller is expected to revert the state changes.
+	PostValidation func(evm *vm.EVM, result *ExecutionResult) error
+}
+
+// ApplyTransactionExtended is ApplyTransaction with the given optional extensions.
+func ApplyTransactionExtended(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config, opts *ApplyTransactionOpts) (*types.Receipt, error) {
+	msg, err := TransactionToMessage(tx, types.MakeSigner(config, header.Number, header.Time), header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	// Create a new context to be used in the EVM environment
+	blockContext := NewEVMBlockContext(header, bc, author, config, statedb)
+	txContext := NewEVMTxContext(msg)
+	vmenv := vm.NewEVM(blockContext, txContext, statedb, config, cfg)
+	return applyTransactionWithEVM(msg, config, gp, statedb, header.Number, header.Hash(), tx, usedGas, vmenv, opts)
+}
+
 // ApplyTransaction attempts to apply a transaction to the given state database
 // and uses the input parameters for its environment. It returns the receipt
 // for the transaction, gas used and an error if the transaction failed,
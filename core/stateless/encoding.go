@@ -114,6 +114,52 @@ func (w *Witness) sanitize() error {
 	return nil
 }
 
+// ExecutionWitness is the JSON encoding of a witness served over RPC. It leaves
+// out the block, which the requester is expected to have already.
+type ExecutionWitness struct {
+	Headers []*types.Header `json:"headers"`
+	Codes   []hexutil.Bytes `json:"codes"`
+	State   []hexutil.Bytes `json:"state"`
+}
+
+// ToExecutionWitness converts the witness into its RPC representation.
+func (w *Witness) ToExecutionWitness() *ExecutionWitness {
+	ext := w.toExtWitness()
+	enc := &ExecutionWitness{
+		Headers: ext.Headers,
+		Codes:   make([]hexutil.Bytes, len(ext.Codes)),
+		State:   make([]hexutil.Bytes, len(ext.State)),
+	}
+	for i, code := range ext.Codes {
+		enc.Codes[i] = code
+	}
+	for i, node := range ext.State {
+		enc.State[i] = node
+	}
+	return enc
+}
+
+// ToWitness reassembles the witness of the given block from its RPC representation.
+func (ew *ExecutionWitness) ToWitness(block *types.Block) (*Witness, error) {
+	ext := &extWitness{
+		Block:   block,
+		Headers: ew.Headers,
+		Codes:   make([][]byte, len(ew.Codes)),
+		State:   make([][]byte, len(ew.State)),
+	}
+	for i, code := range ew.Codes {
+		ext.Codes[i] = code
+	}
+	for i, node := range ew.State {
+		ext.State[i] = node
+	}
+	w := new(Witness)
+	if err := w.fromExtWitness(ext); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
 // extWitness is a witness RLP encoding for transferring across clients.
 type extWitness struct {
 	Block   *types.Block    `json:"block"       gencodec:"required"`
@@ -4,24 +4,28 @@ import (
 	"fmt"
 	"math/big"
 
-	"github.com/ethereum-optimism/superchain-registry/superchain"
-
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
 )
 
 func LoadOPStackGenesis(chainID uint64) (*Genesis, error) {
-	chConfig, ok := superchain.OPChains[chainID]
+	return LoadOPStackGenesisFromRegistry(nil, chainID)
+}
+
+// LoadOPStackGenesisFromRegistry loads the genesis of an OP-Stack chain as defined
+// by the given local superchain-registry, or else by the embedded one.
+func LoadOPStackGenesisFromRegistry(registry *params.SuperchainRegistry, chainID uint64) (*Genesis, error) {
+	chConfig, ok := registry.OPChain(chainID)
 	if !ok {
 		return nil, fmt.Errorf("unknown chain ID: %d", chainID)
 	}
 
-	cfg, err := params.LoadOPStackChainConfig(chainID)
+	cfg, err := registry.ChainConfig(chainID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load params.ChainConfig for chain %d: %w", chainID, err)
 	}
 
-	gen, err := superchain.LoadGenesis(chainID)
+	gen, err := registry.GenesisDefinition(chainID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load genesis definition for chain %d: %w", chainID, err)
 	}
@@ -47,7 +51,7 @@ func LoadOPStackGenesis(chainID uint64) (*Genesis, error) {
 	for addr, acc := range gen.Alloc {
 		var code []byte
 		if acc.CodeHash != ([32]byte{}) {
-			dat, err := superchain.LoadContractBytecode(acc.CodeHash)
+			dat, err := registry.ContractBytecode(acc.CodeHash)
 			if err != nil {
 				return nil, fmt.Errorf("failed to load bytecode %s of address %s in chain %d: %w", acc.CodeHash, addr, chainID, err)
 			}
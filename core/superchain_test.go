@@ -1,11 +1,20 @@
 package core
 
 import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ethereum-optimism/superchain-registry/superchain"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/triedb"
 )
 
@@ -102,3 +111,104 @@ func TestRegistryChainConfigOverride(t *testing.T) {
 		})
 	}
 }
+
+func TestLocalOPStackGenesis(t *testing.T) {
+	const (
+		chainID = 901902
+		chain   = `name = "Local Devnet"
+chain_id = 901902
+superchain_level = 0
+canyon_time = 0
+delta_time = 0
+ecotone_time = 0
+fjord_time = 0
+granite_time = %d
+block_time = 2
+
+[optimism]
+  eip1559_elasticity = 6
+  eip1559_denominator = 50
+  eip1559_denominator_canyon = 250
+
+[genesis]
+  [genesis.l2]
+    hash = "%s"
+    number = 0
+`
+		definition = `{"timestamp": 0, "gasLimit": 30000000, "difficulty": "0x0", "baseFeePerGas": "0x3b9aca00",
+	"alloc": {"0x4200000000000000000000000000000000000015": {"codeHash": "%s", "balance": "0x1"}}}`
+	)
+	var (
+		dir      = t.TempDir()
+		code     = []byte{byte(vm.PUSH1), 0x01, byte(vm.STOP)}
+		codeHash = crypto.Keccak256Hash(code)
+	)
+	write := func(path string, data string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	load := func(graniteTime uint64, hash common.Hash) (*params.SuperchainRegistry, *Genesis, error) {
+		write(filepath.Join(dir, "configs", "devnet", "local.toml"), fmt.Sprintf(chain, graniteTime, hash))
+		registry, err := params.LoadSuperchainRegistry(dir)
+		if err != nil {
+			t.Fatalf("failed to load registry: %v", err)
+		}
+		genesis, err := LoadOPStackGenesisFromRegistry(registry, chainID)
+		return registry, genesis, err
+	}
+	write(filepath.Join(dir, "extra", "genesis", "devnet", "local.json"), fmt.Sprintf(definition, codeHash))
+	write(filepath.Join(dir, "extra", "bytecodes", codeHash.Hex()+".bin"), string(code))
+
+	// The genesis is checked against the hash of the chain config.
+	registry, _, err := load(50, common.Hash{0x1})
+	if err == nil {
+		t.Fatal("genesis with wrong hash loaded")
+	}
+	config, err := registry.ChainConfig(chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Genesis{
+		Config:     config,
+		GasLimit:   30_000_000,
+		Difficulty: common.Big0,
+		BaseFee:    big.NewInt(params.GWei),
+		Alloc: types.GenesisAlloc{
+			common.HexToAddress("0x4200000000000000000000000000000000000015"): {Code: code, Balance: common.Big1},
+		},
+	}
+	hash := expected.ToBlock().Hash()
+	_, genesis, err := load(50, hash)
+	if err != nil {
+		t.Fatalf("failed to load local genesis: %v", err)
+	}
+	db := rawdb.NewMemoryDatabase()
+	tdb := triedb.NewDatabase(db, newDbConfig(rawdb.HashScheme))
+	if _, stored, err := SetupGenesisBlock(db, tdb, genesis); err != nil || stored != hash {
+		t.Fatalf("failed to setup genesis: %x, %v", stored, err)
+	}
+	head := &types.Header{ParentHash: hash, Number: common.Big1, Time: 200}
+	rawdb.WriteHeader(db, head)
+	rawdb.WriteHeadHeaderHash(db, head.Hash())
+
+	// Moving an activated hardfork in the local registry is reported as an
+	// incompatible config change.
+	registry, genesis, err = load(100, hash)
+	if err != nil {
+		t.Fatalf("failed to reload local genesis: %v", err)
+	}
+	_, _, err = SetupGenesisBlock(db, tdb, genesis)
+	if compatErr, ok := err.(*params.ConfigCompatError); !ok || compatErr.What != "Granite fork timestamp" {
+		t.Fatalf("expected granite compatibility error, got %v", err)
+	}
+	// The same holds for the superchain upgrades of the stored config, which are
+	// taken from the registry.
+	_, _, err = SetupGenesisBlockWithOverride(db, tdb, nil, &ChainOverrides{ApplySuperchainUpgrades: true, SuperchainRegistry: registry})
+	if compatErr, ok := err.(*params.ConfigCompatError); !ok || compatErr.What != "Granite fork timestamp" {
+		t.Fatalf("expected granite compatibility error with superchain upgrades, got %v", err)
+	}
+}
@@ -94,12 +94,15 @@ type blobTxMeta struct {
 
 	nonce      uint64       // Needed to prioritize inclusion order within an account
 	costCap    *uint256.Int // Needed to validate cumulative balance sufficiency
+	l1Cost     *uint256.Int // L1 data fee included in the cost cap (zero outside rollups)
 	execTipCap *uint256.Int // Needed to prioritize inclusion order across accounts and validate replacement price bump
 	execFeeCap *uint256.Int // Needed to validate replacement price bump
 	blobFeeCap *uint256.Int // Needed to validate replacement price bump
 	execGas    uint64       // Needed to check inclusion validity before reading the blob
 	blobGas    uint64       // Needed to check inclusion validity before reading the blob
 
+	rollupCostData types.RollupCostData // Needed to reprice the L1 data fee on rollups
+
 	basefeeJumps float64 // Absolute number of 1559 fee adjustments needed to reach the tx's fee cap
 	blobfeeJumps float64 // Absolute number of 4844 fee adjustments needed to reach the tx's blob fee cap
 
@@ -109,8 +112,9 @@ type blobTxMeta struct {
 }
 
 // newBlobTxMeta retrieves the indexed metadata fields from a blob transaction
-// and assembles a helper struct to track in memory.
-func newBlobTxMeta(id uint64, size uint32, tx *types.Transaction) *blobTxMeta {
+// and assembles a helper struct to track in memory. On rollups, the L1 data fee
+// of the transaction is added to its cost cap.
+func newBlobTxMeta(id uint64, size uint32, tx *types.Transaction, l1CostFn txpool.L1CostFunc) *blobTxMeta {
 	meta := &blobTxMeta{
 		hash:       tx.Hash(),
 		id:         id,
@@ -123,12 +127,38 @@ func newBlobTxMeta(id uint64, size uint32, tx *types.Transaction) *blobTxMeta {
 		execGas:    tx.Gas(),
 		blobGas:    tx.BlobGas(),
 	}
+	if l1CostFn != nil {
+		meta.rollupCostData = rollupCostData(tx)
+	}
+	meta.l1Cost = rollupCost(meta.rollupCostData, l1CostFn)
+	meta.costCap.Add(meta.costCap, meta.l1Cost)
+
 	meta.basefeeJumps = dynamicFeeJumps(meta.execFeeCap)
 	meta.blobfeeJumps = dynamicFeeJumps(meta.blobFeeCap)
 
 	return meta
 }
 
+// rollupCostData retrieves the data a rollup charges the L1 data fee of a blob
+// transaction for. The blobs are not posted to L1 along with the transaction, so
+// they are left out.
+func rollupCostData(tx *types.Transaction) types.RollupCostData {
+	return tx.WithoutBlobTxSidecar().RollupCostData()
+}
+
+// rollupCost returns the L1 data fee of a transaction, or zero if there's no
+// rollup cost function.
+func rollupCost(data types.RollupCostData, l1CostFn txpool.L1CostFunc) *uint256.Int {
+	if l1CostFn == nil {
+		return new(uint256.Int)
+	}
+	l1Cost := l1CostFn(data)
+	if l1Cost == nil {
+		return new(uint256.Int)
+	}
+	return uint256.MustFromBig(l1Cost)
+}
+
 // BlobPool is the transaction pool dedicated to EIP-4844 blob transactions.
 //
 // Blob transactions are special snowflakes that are designed for a very specific
@@ -302,17 +332,19 @@ type BlobPool struct {
 	signer types.Signer // Transaction signer to use for sender recovery
 	chain  BlockChain   // Chain object to access the state through
 
-	head   *types.Header  // Current head of the chain
-	state  *state.StateDB // Current state at the head of the chain
-	gasTip *uint256.Int   // Currently accepted minimum gas tip
+	head     *types.Header     // Current head of the chain
+	state    *state.StateDB    // Current state at the head of the chain
+	gasTip   *uint256.Int      // Currently accepted minimum gas tip
+	l1CostFn txpool.L1CostFunc // L1 data fee of the transactions at the current head, nil outside rollups
 
 	lookup map[common.Hash]uint64           // Lookup table mapping hashes to tx billy entries
 	index  map[common.Address][]*blobTxMeta // Blob transactions grouped by accounts, sorted by nonce
 	spent  map[common.Address]*uint256.Int  // Expenditure tracking for individual accounts
 	evict  *evictHeap                       // Heap of cheapest accounts for eviction when full
 
-	discoverFeed event.Feed // Event feed to send out new tx events on pool discovery (reorg excluded)
-	insertFeed   event.Feed // Event feed to send out new tx events on pool inclusion (reorg included)
+	discoverFeed event.Feed         // Event feed to send out new tx events on pool discovery (reorg excluded)
+	insertFeed   event.Feed         // Event feed to send out new tx events on pool inclusion (reorg included)
+	txEvents     txpool.TxEventFeed // Event feed to send out transaction lifecycle events
 
 	lock sync.RWMutex // Mutex protecting the pool during reorg handling
 }
@@ -370,6 +402,7 @@ func (p *BlobPool) Init(gasTip uint64, head *types.Header, reserve txpool.Addres
 		return err
 	}
 	p.head, p.state = head, state
+	p.updateL1Costs()
 
 	// Index all transactions on disk and delete anything unprocessable
 	var fails []uint64
@@ -435,6 +468,8 @@ func (p *BlobPool) Init(gasTip uint64, head *types.Header, reserve txpool.Addres
 
 // Close closes down the underlying persistent store.
 func (p *BlobPool) Close() error {
+	p.txEvents.Close()
+
 	var errs []error
 	if p.limbo != nil { // Close might be invoked due to error in constructor, before p,limbo is set
 		if err := p.limbo.Close(); err != nil {
@@ -470,7 +505,7 @@ func (p *BlobPool) parseTransaction(id uint64, size uint32, blob []byte) error {
 		return errors.New("missing blob sidecar")
 	}
 
-	meta := newBlobTxMeta(id, size, tx)
+	meta := newBlobTxMeta(id, size, tx, p.l1CostFn)
 	if _, exists := p.lookup[meta.hash]; exists {
 		// This path is only possible after a crash, where deleted items are not
 		// removed via the normal shutdown-startup procedure and thus may get
@@ -525,13 +560,20 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 		var (
 			ids    []uint64
 			nonces []uint64
+			reason = txpool.TxDropNonceTooLow
 		)
+		if gapped {
+			reason = txpool.TxDropNonceGap
+		}
 		for i := 0; i < len(txs); i++ {
 			ids = append(ids, txs[i].id)
 			nonces = append(nonces, txs[i].nonce)
 
 			p.stored -= uint64(txs[i].size)
 			delete(p.lookup, txs[i].hash)
+			if _, ok := inclusions[txs[i].hash]; !ok {
+				p.txEvents.RecordDrop(txs[i].hash, reason)
+			}
 
 			// Included transactions blobs need to be moved to the limbo
 			if filled && inclusions != nil {
@@ -573,6 +615,9 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[0].costCap)
 			p.stored -= uint64(txs[0].size)
 			delete(p.lookup, txs[0].hash)
+			if _, ok := inclusions[txs[0].hash]; !ok {
+				p.txEvents.RecordDrop(txs[0].hash, txpool.TxDropNonceTooLow)
+			}
 
 			// Included transactions blobs need to be moved to the limbo
 			if inclusions != nil {
@@ -651,6 +696,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[j].costCap)
 			p.stored -= uint64(txs[j].size)
 			delete(p.lookup, txs[j].hash)
+			p.txEvents.RecordDrop(txs[j].hash, txpool.TxDropNonceGap)
 		}
 		txs = txs[:i]
 
@@ -689,6 +735,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], last.costCap)
 			p.stored -= uint64(last.size)
 			delete(p.lookup, last.hash)
+			p.txEvents.RecordDrop(last.hash, txpool.TxDropNoFunds)
 		}
 		if len(txs) == 0 {
 			delete(p.index, addr)
@@ -729,6 +776,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], last.costCap)
 			p.stored -= uint64(last.size)
 			delete(p.lookup, last.hash)
+			p.txEvents.RecordDrop(last.hash, txpool.TxDropAccountLimit)
 		}
 		p.index[addr] = txs
 
@@ -780,6 +828,8 @@ func (p *BlobPool) offload(addr common.Address, nonce uint64, id uint64, inclusi
 // Reset implements txpool.SubPool, allowing the blob pool's internal state to be
 // kept in sync with the main transaction pool's internal state.
 func (p *BlobPool) Reset(oldHead, newHead *types.Header) {
+	defer p.txEvents.Flush()
+
 	waitStart := time.Now()
 	p.lock.Lock()
 	resetwaitHist.Update(time.Since(waitStart).Nanoseconds())
@@ -797,9 +847,14 @@ func (p *BlobPool) Reset(oldHead, newHead *types.Header) {
 	p.head = newHead
 	p.state = statedb
 
+	// Reprice the L1 data fees of the pooled transactions, the accounts whose
+	// cost grew need to be rechecked for overdrafts
+	repriced := p.updateL1Costs()
+
 	// Run the reorg between the old and new head and figure out which accounts
 	// need to be rechecked and which transactions need to be readded
-	if reinject, inclusions := p.reorg(oldHead, newHead); reinject != nil {
+	reinject, inclusions := p.reorg(oldHead, newHead)
+	if reinject != nil {
 		var adds []*types.Transaction
 		for addr, txs := range reinject {
 			// Blindly push all the lost transactions back into the pool
@@ -816,6 +871,11 @@ func (p *BlobPool) Reset(oldHead, newHead *types.Header) {
 			p.insertFeed.Send(core.NewTxsEvent{Txs: adds})
 		}
 	}
+	for _, addr := range repriced {
+		if _, ok := reinject[addr]; !ok {
+			p.recheck(addr, make(map[common.Hash]uint64)) // non-nil, the eviction heap is initialized
+		}
+	}
 	// Flush out any blobs from limbo that are older than the latest finality
 	if p.chain.Config().IsCancun(p.head.Number, p.head.Time) {
 		p.limbo.finalize(p.chain.CurrentFinalBlock())
@@ -993,7 +1053,7 @@ func (p *BlobPool) reinject(addr common.Address, txhash common.Hash) error {
 	}
 
 	// Update the indices and metrics
-	meta := newBlobTxMeta(id, p.store.Size(id), tx)
+	meta := newBlobTxMeta(id, p.store.Size(id), tx, p.l1CostFn)
 	if _, ok := p.index[addr]; !ok {
 		if err := p.reserve(addr, true); err != nil {
 			log.Warn("Failed to reserve account for blob pool", "tx", tx.Hash(), "from", addr, "err", err)
@@ -1014,6 +1074,8 @@ func (p *BlobPool) reinject(addr common.Address, txhash common.Hash) error {
 // SetGasTip implements txpool.SubPool, allowing the blob pool's gas requirements
 // to be kept in sync with the main transaction pool's gas requirements.
 func (p *BlobPool) SetGasTip(tip *big.Int) {
+	defer p.txEvents.Flush()
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -1034,6 +1096,7 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 					p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[i].costCap)
 					p.stored -= uint64(tx.size)
 					delete(p.lookup, tx.hash)
+					p.txEvents.RecordDrop(tx.hash, txpool.TxDropTipTooLow)
 					txs[i] = nil
 
 					// Drop everything afterwards, no gaps allowed
@@ -1044,6 +1107,7 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 						p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], tx.costCap)
 						p.stored -= uint64(tx.size)
 						delete(p.lookup, tx.hash)
+						p.txEvents.RecordDrop(tx.hash, txpool.TxDropNonceGap)
 						txs[i+1+j] = nil
 					}
 					// Clear out the dropped transactions from the index
@@ -1076,6 +1140,40 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 	p.updateStorageMetrics()
 }
 
+// updateL1Costs sets the L1 cost function of the pool to the one in effect on top
+// of the current head, and reprices the L1 data fees of the pooled transactions.
+// The accounts whose expenditure grew are returned.
+func (p *BlobPool) updateL1Costs() []common.Address {
+	costFn := types.NewL1CostFunc(p.chain.Config(), p.state)
+	if costFn == nil {
+		p.l1CostFn = nil
+		return nil
+	}
+	head := p.head
+	p.l1CostFn = func(rollupCostData types.RollupCostData) *big.Int {
+		return costFn(rollupCostData, head.Time)
+	}
+	var grown []common.Address
+	for addr, txs := range p.index {
+		var grew bool
+		for _, meta := range txs {
+			l1Cost := rollupCost(meta.rollupCostData, p.l1CostFn)
+			if l1Cost.Eq(meta.l1Cost) {
+				continue
+			}
+			grew = grew || l1Cost.Gt(meta.l1Cost)
+
+			meta.costCap = new(uint256.Int).Add(new(uint256.Int).Sub(meta.costCap, meta.l1Cost), l1Cost)
+			p.spent[addr] = new(uint256.Int).Add(new(uint256.Int).Sub(p.spent[addr], meta.l1Cost), l1Cost)
+			meta.l1Cost = l1Cost
+		}
+		if grew {
+			grown = append(grown, addr)
+		}
+	}
+	return grown
+}
+
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (p *BlobPool) validateTx(tx *types.Transaction) error {
@@ -1121,6 +1219,13 @@ func (p *BlobPool) validateTx(tx *types.Transaction) error {
 			return nil
 		},
 	}
+	if p.l1CostFn != nil {
+		// Charge the L1 data fee of the transaction without its blobs
+		l1Cost := rollupCost(rollupCostData(tx), p.l1CostFn).ToBig()
+		stateOpts.L1CostFn = func(types.RollupCostData) *big.Int {
+			return l1Cost
+		}
+	}
 	if err := txpool.ValidateTransactionWithState(tx, p.signer, stateOpts); err != nil {
 		return err
 	}
@@ -1175,6 +1280,15 @@ func (p *BlobPool) Has(hash common.Hash) bool {
 	return ok
 }
 
+// ValidateTxBasics implements txpool.SubPool, checking whether a transaction
+// passes the validation of the pool without adding it.
+func (p *BlobPool) ValidateTxBasics(tx *types.Transaction, local bool) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.validateTx(tx)
+}
+
 // Get returns a transaction if it is contained in the pool, or nil otherwise.
 func (p *BlobPool) Get(hash common.Hash) *types.Transaction {
 	// Track the amount of time waiting to retrieve a fully resolved blob tx from
@@ -1219,6 +1333,8 @@ func (p *BlobPool) Add(txs []*types.Transaction, local bool, sync bool) []error
 			adds = append(adds, tx.WithoutBlobTxSidecar())
 		}
 	}
+	p.txEvents.Flush()
+
 	if len(adds) > 0 {
 		p.discoverFeed.Send(core.NewTxsEvent{Txs: adds})
 		p.insertFeed.Send(core.NewTxsEvent{Txs: adds})
@@ -1293,7 +1409,7 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 	if err != nil {
 		return err
 	}
-	meta := newBlobTxMeta(id, p.store.Size(id), tx)
+	meta := newBlobTxMeta(id, p.store.Size(id), tx, p.l1CostFn)
 
 	var (
 		next   = p.state.GetNonce(from)
@@ -1322,6 +1438,8 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 		delete(p.lookup, prev.hash)
 		p.lookup[meta.hash] = meta.id
 		p.stored += uint64(meta.size) - uint64(prev.size)
+
+		p.txEvents.RecordReplace(prev.hash, meta.hash)
 	} else {
 		// Transaction extends previously scheduled ones
 		p.index[from] = append(p.index[from], meta)
@@ -1380,6 +1498,8 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 			heap.Fix(p.evict, p.evict.index[from])
 		}
 	}
+	p.txEvents.Record(meta.hash, txpool.TxEventAdded)
+
 	// If the pool went over the allowed data limit, evict transactions until
 	// we're again below the threshold
 	for p.stored > p.config.Datacap {
@@ -1420,6 +1540,7 @@ func (p *BlobPool) drop() {
 	}
 	p.stored -= uint64(drop.size)
 	delete(p.lookup, drop.hash)
+	p.txEvents.RecordDrop(drop.hash, txpool.TxDropUnderpriced)
 
 	// Remove the transaction from the pool's eviction heap:
 	//   - If the entire account was dropped, pop off the address
@@ -1595,6 +1716,12 @@ func (p *BlobPool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool
 	}
 }
 
+// SubscribeTxEvents registers a subscription for the lifecycle events of the
+// transactions in the pool.
+func (p *BlobPool) SubscribeTxEvents(ch chan<- txpool.TxEvent) event.Subscription {
+	return p.txEvents.Subscribe(ch)
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 func (p *BlobPool) Nonce(addr common.Address) uint64 {
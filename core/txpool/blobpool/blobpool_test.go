@@ -1313,6 +1313,61 @@ func TestAdd(t *testing.T) {
 	}
 }
 
+// Tests that on rollups, the L1 data fee of the blob transactions is charged to
+// their senders, both on admission and when repriced on new heads.
+func TestAddL1Cost(t *testing.T) {
+	config := *testChainConfig
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 2, EIP1559Denominator: 8, BlobTxs: true}
+
+	var (
+		key, _     = crypto.GenerateKey()
+		addr       = crypto.PubkeyToAddress(key.PublicKey)
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewDatabase(memorydb.New())), nil)
+		chain      = &testBlockChain{
+			config:  &config,
+			basefee: uint256.NewInt(1050),
+			blobfee: uint256.NewInt(105),
+			statedb: statedb,
+		}
+	)
+	statedb.SetState(types.L1BlockAddr, types.L1BaseFeeSlot, common.BigToHash(big.NewInt(1000)))
+	statedb.SetState(types.L1BlockAddr, types.ScalarSlot, common.BigToHash(big.NewInt(1_000_000)))
+
+	pool := New(Config{Datadir: t.TempDir()}, chain)
+	if err := pool.Init(1, chain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("failed to create blob pool: %v", err)
+	}
+	defer pool.Close()
+
+	var (
+		tx     = types.MustSignNewTx(key, types.LatestSigner(&config), makeUnsignedTx(0, 10, 1100, 105))
+		head   = chain.CurrentBlock()
+		l1Cost = types.NewL1CostFunc(&config, statedb)(tx.WithoutBlobTxSidecar().RollupCostData(), head.Time)
+	)
+	if l1Cost.Sign() == 0 {
+		t.Fatalf("no L1 data fee charged")
+	}
+	// Funding the execution cost only is not enough, the L1 data fee is needed too
+	statedb.AddBalance(addr, uint256.MustFromBig(tx.Cost()), tracing.BalanceChangeUnspecified)
+	if err := pool.add(tx); !errors.Is(err, core.ErrInsufficientFunds) {
+		t.Fatalf("adding transaction error mismatch: have %v, want %v", err, core.ErrInsufficientFunds)
+	}
+	statedb.AddBalance(addr, uint256.MustFromBig(l1Cost), tracing.BalanceChangeUnspecified)
+	if err := pool.add(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	verifyPoolInternals(t, pool)
+
+	// Raise the L1 base fee, the transaction can't be afforded anymore
+	statedb.SetState(types.L1BlockAddr, types.L1BaseFeeSlot, common.BigToHash(big.NewInt(2000)))
+	pool.Reset(head, head)
+
+	if pool.Has(tx.Hash()) {
+		t.Fatalf("overdrafting transaction not evicted")
+	}
+	verifyPoolInternals(t, pool)
+}
+
 // Benchmarks the time it takes to assemble the lazy pending transaction list
 // from the pool contents.
 func BenchmarkPoolPending100Mb(b *testing.B) { benchmarkPoolPending(b, 100_000_000) }
@@ -0,0 +1,105 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bundlepool
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Bundle is an ordered set of signed transactions, which the block builder either
+// includes all together, in order and without other transactions in between, or
+// not at all.
+type Bundle struct {
+	Txs               types.Transactions // Transactions to include, in order
+	RevertingTxHashes []common.Hash      // Transactions allowed to revert without failing the bundle
+	MinBlock          uint64             // First block the bundle may be included in, 0 for no bound
+	MaxBlock          uint64             // Last block the bundle may be included in, 0 for no bound
+}
+
+// Hash returns the identifier of the bundle, the hash of its transaction hashes.
+func (b *Bundle) Hash() common.Hash {
+	hashes := make([]byte, 0, len(b.Txs)*common.HashLength)
+	for _, tx := range b.Txs {
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	return crypto.Keccak256Hash(hashes)
+}
+
+// AllowsRevert reports whether the transaction with the given hash may revert
+// without failing the bundle.
+func (b *Bundle) AllowsRevert(hash common.Hash) bool {
+	return slices.Contains(b.RevertingTxHashes, hash)
+}
+
+// Targets reports whether the bundle may be included in the block with the
+// given number.
+func (b *Bundle) Targets(number uint64) bool {
+	return number >= b.MinBlock && (b.MaxBlock == 0 || number <= b.MaxBlock)
+}
+
+// bundleJSON is the RPC representation of a bundle, with the transactions in
+// their binary encoding.
+type bundleJSON struct {
+	Txs               []hexutil.Bytes `json:"txs"`
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes,omitempty"`
+	MinBlockNumber    hexutil.Uint64  `json:"minBlockNumber,omitempty"`
+	MaxBlockNumber    hexutil.Uint64  `json:"maxBlockNumber,omitempty"`
+}
+
+// MarshalJSON encodes the bundle in its RPC representation.
+func (b Bundle) MarshalJSON() ([]byte, error) {
+	enc := bundleJSON{
+		Txs:               make([]hexutil.Bytes, len(b.Txs)),
+		RevertingTxHashes: b.RevertingTxHashes,
+		MinBlockNumber:    hexutil.Uint64(b.MinBlock),
+		MaxBlockNumber:    hexutil.Uint64(b.MaxBlock),
+	}
+	for i, tx := range b.Txs {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		enc.Txs[i] = data
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON decodes a bundle from its RPC representation.
+func (b *Bundle) UnmarshalJSON(input []byte) error {
+	var dec bundleJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	txs := make(types.Transactions, len(dec.Txs))
+	for i, data := range dec.Txs {
+		txs[i] = new(types.Transaction)
+		if err := txs[i].UnmarshalBinary(data); err != nil {
+			return err
+		}
+	}
+	b.Txs = txs
+	b.RevertingTxHashes = dec.RevertingTxHashes
+	b.MinBlock = uint64(dec.MinBlockNumber)
+	b.MaxBlock = uint64(dec.MaxBlockNumber)
+	return nil
+}
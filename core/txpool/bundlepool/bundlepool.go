@@ -0,0 +1,593 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package bundlepool implements the pool of transaction bundles waiting for
+// atomic inclusion by the block builder.
+package bundlepool
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// txMaxSize is the maximum size a single bundled transaction can have, the
+	// same as in the legacy pool.
+	txMaxSize = 128 * 1024
+
+	// maxIncludedDepth is the maximum number of new blocks searched for included
+	// bundled transactions on reset.
+	maxIncludedDepth = 64
+)
+
+var (
+	// ErrBundlePoolFull is returned if the pool holds the maximum number of
+	// bundles already.
+	ErrBundlePoolFull = errors.New("bundle pool full")
+
+	// ErrEmptyBundle is returned if a bundle has no transactions.
+	ErrEmptyBundle = errors.New("empty bundle")
+
+	// ErrBundleTooLarge is returned if a bundle has more transactions than allowed.
+	ErrBundleTooLarge = errors.New("bundle too large")
+
+	// ErrBundleExpired is returned if the last block a bundle targets is already
+	// part of the chain.
+	ErrBundleExpired = errors.New("bundle expired")
+
+	// ErrInvalidBlockRange is returned if the first block a bundle targets comes
+	// after the last one.
+	ErrInvalidBlockRange = errors.New("invalid bundle block range")
+
+	// ErrUnknownRevertingTx is returned if a bundle allows a transaction not part
+	// of the bundle to revert.
+	ErrUnknownRevertingTx = errors.New("reverting transaction not in bundle")
+
+	// ErrSenderLimit is returned if a sender of a bundle has the maximum number
+	// of bundles waiting for inclusion already.
+	ErrSenderLimit = errors.New("sender bundle limit reached")
+
+	// ErrBundleFailed is returned if a bundle failed to be included in too many
+	// blocks.
+	ErrBundleFailed = errors.New("bundle failed too often")
+
+	// errBundledTxsOnly is returned if transactions are added to the pool one by
+	// one, instead of in bundles.
+	errBundledTxsOnly = errors.New("bundle pool only accepts bundles")
+)
+
+var (
+	bundlesGauge  = metrics.NewRegisteredGauge("bundlepool/bundles", nil)
+	addedMeter    = metrics.NewRegisteredMeter("bundlepool/added", nil)
+	rejectedMeter = metrics.NewRegisteredMeter("bundlepool/rejected", nil)
+	droppedMeter  = metrics.NewRegisteredMeter("bundlepool/dropped", nil)
+)
+
+// BlockChain defines the minimal set of methods needed to back a bundle pool with
+// a chain. Exists to allow mocking the live chain out of tests.
+type BlockChain interface {
+	// Config retrieves the chain's fork configuration.
+	Config() *params.ChainConfig
+
+	// GetBlock retrieves a specific block, used to find the bundled transactions
+	// included by new chain heads.
+	GetBlock(hash common.Hash, number uint64) *types.Block
+
+	// StateAt returns a state database for a given root hash (generally the head).
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// bundleMeta is the bookkeeping of a bundle waiting for inclusion.
+type bundleMeta struct {
+	senders  []common.Address // Distinct senders of the bundled transactions
+	expiry   uint64           // Last block the bundle is kept for, enforced even without a MaxBlock
+	failures uint64           // Number of blocks the bundle failed to be included in
+	failedAt uint64           // Number of the last block the bundle failed to be included in
+}
+
+// BundlePool is the pool of transaction bundles waiting for inclusion. It plugs
+// into the primary transaction pool as a subpool, to follow the chain head in
+// lockstep with the other subpools, but doesn't accept individual transactions:
+// bundles are added through AddBundle, and retrieved by the block builder through
+// Bundles.
+type BundlePool struct {
+	config Config
+	chain  BlockChain
+	signer types.Signer
+
+	head     *types.Header     // Current head of the chain
+	state    *state.StateDB    // Current state at the head of the chain
+	l1CostFn txpool.L1CostFunc // L1 data fee of the bundled transactions, nil outside of rollups
+	gasTip   *big.Int          // Minimum gas tip of bundled transactions
+
+	bundles []*Bundle                          // Bundles waiting for inclusion, in arrival order
+	known   map[common.Hash]*bundleMeta        // Bookkeeping of the bundles waiting for inclusion, by hash
+	senders map[common.Address]uint64          // Number of bundles waiting for inclusion per sender
+	lookup  map[common.Hash]*types.Transaction // Bundled transactions by hash
+
+	txFeed   event.Feed
+	txEvents txpool.TxEventFeed
+	lock     sync.RWMutex
+}
+
+// New creates a new bundle pool. The pool must be initialized by the primary
+// transaction pool before use.
+func New(config Config, chain BlockChain) *BundlePool {
+	config = (&config).sanitize()
+
+	return &BundlePool{
+		config:  config,
+		chain:   chain,
+		signer:  types.LatestSigner(chain.Config()),
+		known:   make(map[common.Hash]*bundleMeta),
+		senders: make(map[common.Address]uint64),
+		lookup:  make(map[common.Hash]*types.Transaction),
+	}
+}
+
+// Filter returns whether the given transaction can be added to the pool, which
+// is never the case as the pool only accepts whole bundles.
+func (p *BundlePool) Filter(tx *types.Transaction) bool {
+	return false
+}
+
+// Init sets the gas price needed to keep a bundle in the pool and the chain
+// head to validate bundles against.
+func (p *BundlePool) Init(gasTip uint64, head *types.Header, reserve txpool.AddressReserver) error {
+	statedb, err := p.chain.StateAt(head.Root)
+	if err != nil {
+		statedb, err = p.chain.StateAt(types.EmptyRootHash)
+	}
+	if err != nil {
+		return err
+	}
+	p.setHead(head, statedb)
+	p.gasTip = new(big.Int).SetUint64(gasTip)
+	return nil
+}
+
+// setHead switches the pool to validate bundles on top of a new chain head.
+func (p *BundlePool) setHead(head *types.Header, statedb *state.StateDB) {
+	p.head, p.state = head, statedb
+
+	p.l1CostFn = nil
+	if costFn := types.NewL1CostFunc(p.chain.Config(), statedb); costFn != nil {
+		p.l1CostFn = func(rcd types.RollupCostData) *big.Int {
+			return costFn(rcd, head.Time)
+		}
+	}
+}
+
+// Close terminates the bundle pool.
+func (p *BundlePool) Close() error {
+	p.txEvents.Close()
+	return nil
+}
+
+// Reset implements txpool.SubPool, dropping the bundles which can't be included
+// anymore on top of the new head: the ones past their block range or lifetime,
+// the ones with transactions already included or replaced, and the ones whose
+// senders can't afford them anymore. The bundles included by the new blocks are
+// removed without reporting their transactions as dropped, as the primary pool
+// reports them as included.
+func (p *BundlePool) Reset(oldHead, newHead *types.Header) {
+	statedb, err := p.chain.StateAt(newHead.Root)
+	if err != nil {
+		log.Error("Failed to reset bundlepool state", "err", err)
+		return
+	}
+	included := p.included(oldHead, newHead)
+
+	defer p.txEvents.Flush()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.setHead(newHead, statedb)
+
+	p.drop(func(bundle *Bundle, meta *bundleMeta) error {
+		if meta.expiry <= newHead.Number.Uint64() {
+			return fmt.Errorf("%w: lifetime ended at block %d", ErrBundleExpired, meta.expiry)
+		}
+		return p.validateState(bundle)
+	}, included)
+}
+
+// included returns the hashes of the transactions included by the blocks added
+// on top of the old head, up to the new head.
+func (p *BundlePool) included(oldHead, newHead *types.Header) map[common.Hash]struct{} {
+	included := make(map[common.Hash]struct{})
+	if oldHead == nil {
+		return included
+	}
+	var (
+		hash   = newHead.Hash()
+		number = newHead.Number.Uint64()
+	)
+	for depth := 0; depth < maxIncludedDepth && number > oldHead.Number.Uint64(); depth++ {
+		block := p.chain.GetBlock(hash, number)
+		if block == nil {
+			break
+		}
+		for _, tx := range block.Transactions() {
+			included[tx.Hash()] = struct{}{}
+		}
+		hash, number = block.ParentHash(), number-1
+	}
+	return included
+}
+
+// ReportFailure records that a bundle failed to be included in the block with
+// the given number, dropping the bundle once it failed in too many blocks. The
+// block builder may attempt a bundle multiple times per block, only the first
+// failure counts.
+func (p *BundlePool) ReportFailure(hash common.Hash, number uint64) {
+	defer p.txEvents.Flush()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	meta, ok := p.known[hash]
+	if !ok || meta.failedAt == number {
+		return
+	}
+	meta.failures++
+	meta.failedAt = number
+	if meta.failures < p.config.MaxFailures {
+		return
+	}
+	p.drop(func(bundle *Bundle, meta *bundleMeta) error {
+		if bundle.Hash() == hash {
+			return fmt.Errorf("%w: %d blocks", ErrBundleFailed, meta.failures)
+		}
+		return nil
+	}, nil)
+}
+
+// drop removes the bundles for which the given check fails, emitting the drop
+// events of the transactions not shared with any remaining bundle, except for
+// the given included ones.
+func (p *BundlePool) drop(check func(bundle *Bundle, meta *bundleMeta) error, included map[common.Hash]struct{}) {
+	bundles := p.bundles[:0]
+	for _, bundle := range p.bundles {
+		err := check(bundle, p.known[bundle.Hash()])
+		if err == nil {
+			bundles = append(bundles, bundle)
+			continue
+		}
+		p.remove(bundle)
+		if slices.ContainsFunc(bundle.Txs, func(tx *types.Transaction) bool {
+			_, ok := included[tx.Hash()]
+			return ok
+		}) {
+			log.Trace("Removing included bundle", "hash", bundle.Hash())
+		} else {
+			log.Trace("Dropping bundle", "hash", bundle.Hash(), "err", err)
+			droppedMeter.Mark(1)
+		}
+		reason := txpool.TxDropNonceTooLow
+		switch {
+		case errors.Is(err, ErrBundleExpired):
+			reason = txpool.TxDropBundleExpired
+		case errors.Is(err, ErrBundleFailed):
+			reason = txpool.TxDropBundleFailed
+		case errors.Is(err, core.ErrInsufficientFunds):
+			reason = txpool.TxDropNoFunds
+		}
+		for _, tx := range bundle.Txs {
+			if _, ok := included[tx.Hash()]; ok {
+				continue
+			}
+			if _, ok := p.lookup[tx.Hash()]; !ok {
+				p.txEvents.RecordDrop(tx.Hash(), reason)
+			}
+		}
+	}
+	clear(p.bundles[len(bundles):])
+	p.bundles = bundles
+	bundlesGauge.Update(int64(len(p.bundles)))
+}
+
+// SetGasTip updates the minimum gas tip required by the pool for new bundled
+// transactions. The bundles already in the pool are kept.
+func (p *BundlePool) SetGasTip(tip *big.Int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.gasTip = new(big.Int).Set(tip)
+}
+
+// Has returns an indicator whether the pool has a bundled transaction with the
+// given hash.
+func (p *BundlePool) Has(hash common.Hash) bool {
+	return p.Get(hash) != nil
+}
+
+// Get returns a bundled transaction if it is contained in the pool, or nil
+// otherwise.
+func (p *BundlePool) Get(hash common.Hash) *types.Transaction {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.lookup[hash]
+}
+
+// ValidateTxBasics rejects the given transaction, as the pool only accepts whole
+// bundles.
+func (p *BundlePool) ValidateTxBasics(tx *types.Transaction, local bool) error {
+	return errBundledTxsOnly
+}
+
+// Add rejects the given transactions, as the pool only accepts whole bundles.
+func (p *BundlePool) Add(txs []*types.Transaction, local bool, sync bool) []error {
+	errs := make([]error, len(txs))
+	for i := range txs {
+		errs[i] = errBundledTxsOnly
+	}
+	return errs
+}
+
+// AddBundle validates a bundle against the current head of the chain, and adds
+// it to the pool.
+func (p *BundlePool) AddBundle(bundle *Bundle) error {
+	defer p.txEvents.Flush()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	senders, err := p.validate(bundle)
+	if err != nil {
+		log.Trace("Rejected bundle", "hash", bundle.Hash(), "err", err)
+		rejectedMeter.Mark(1)
+		return err
+	}
+	expiry := p.head.Number.Uint64() + p.config.Lifetime
+	if bundle.MaxBlock != 0 && bundle.MaxBlock < expiry {
+		expiry = bundle.MaxBlock
+	}
+	p.bundles = append(p.bundles, bundle)
+	p.known[bundle.Hash()] = &bundleMeta{senders: senders, expiry: expiry}
+	for _, sender := range senders {
+		p.senders[sender]++
+	}
+	for _, tx := range bundle.Txs {
+		if _, ok := p.lookup[tx.Hash()]; !ok {
+			p.txEvents.Record(tx.Hash(), txpool.TxEventAdded)
+		}
+		p.lookup[tx.Hash()] = tx
+	}
+	addedMeter.Mark(1)
+	bundlesGauge.Update(int64(len(p.bundles)))
+	return nil
+}
+
+// validate checks whether a bundle may be added to the pool, returning the
+// distinct senders of its transactions.
+func (p *BundlePool) validate(bundle *Bundle) ([]common.Address, error) {
+	if _, ok := p.known[bundle.Hash()]; ok {
+		return nil, txpool.ErrAlreadyKnown
+	}
+	if uint64(len(p.bundles)) >= p.config.MaxBundles {
+		return nil, ErrBundlePoolFull
+	}
+	if len(bundle.Txs) == 0 {
+		return nil, ErrEmptyBundle
+	}
+	if uint64(len(bundle.Txs)) > p.config.MaxTxs {
+		return nil, fmt.Errorf("%w: %d transactions, maximum %d", ErrBundleTooLarge, len(bundle.Txs), p.config.MaxTxs)
+	}
+	if bundle.MaxBlock != 0 && bundle.MinBlock > bundle.MaxBlock {
+		return nil, fmt.Errorf("%w: from %d to %d", ErrInvalidBlockRange, bundle.MinBlock, bundle.MaxBlock)
+	}
+	if bundle.MinBlock > p.head.Number.Uint64()+p.config.Lifetime {
+		return nil, fmt.Errorf("%w: first block %d past the lifetime of %d blocks", ErrInvalidBlockRange, bundle.MinBlock, p.config.Lifetime)
+	}
+	for _, hash := range bundle.RevertingTxHashes {
+		if !slicesContainsTx(bundle.Txs, hash) {
+			return nil, fmt.Errorf("%w: %x", ErrUnknownRevertingTx, hash)
+		}
+	}
+	opts := &txpool.ValidationOptions{
+		Config: p.chain.Config(),
+		Accept: 0 |
+			1<<types.LegacyTxType |
+			1<<types.AccessListTxType |
+			1<<types.DynamicFeeTxType,
+		MaxSize: txMaxSize,
+		MinTip:  p.gasTip,
+	}
+	for i, tx := range bundle.Txs {
+		if err := txpool.ValidateTransaction(tx, p.head, p.signer, opts); err != nil {
+			return nil, fmt.Errorf("invalid transaction %d: %w", i, err)
+		}
+	}
+	if err := p.validateState(bundle); err != nil {
+		return nil, err
+	}
+	senders := p.bundleSenders(bundle)
+	for _, sender := range senders {
+		if p.senders[sender] >= p.config.MaxBundlesPerSender {
+			return nil, fmt.Errorf("%w: %v has %d bundles", ErrSenderLimit, sender, p.senders[sender])
+		}
+	}
+	return senders, nil
+}
+
+// validateState checks whether a bundle may still be included on top of the
+// current head of the chain: within its block range, with none of its nonces
+// used and with the senders able to pay for all of their bundled transactions.
+func (p *BundlePool) validateState(bundle *Bundle) error {
+	if bundle.MaxBlock != 0 && bundle.MaxBlock <= p.head.Number.Uint64() {
+		return fmt.Errorf("%w: last block %d, head %d", ErrBundleExpired, bundle.MaxBlock, p.head.Number)
+	}
+	costs := make(map[common.Address]*big.Int)
+	for i, tx := range bundle.Txs {
+		from, err := types.Sender(p.signer, tx)
+		if err != nil {
+			return fmt.Errorf("invalid transaction %d: %w", i, err)
+		}
+		if next := p.state.GetNonce(from); tx.Nonce() < next {
+			return fmt.Errorf("invalid transaction %d: %w: next nonce %v, tx nonce %v", i, core.ErrNonceTooLow, next, tx.Nonce())
+		}
+		cost, ok := costs[from]
+		if !ok {
+			cost = new(big.Int)
+			costs[from] = cost
+		}
+		cost.Add(cost, tx.Cost())
+		if p.l1CostFn != nil {
+			if l1Cost := p.l1CostFn(tx.RollupCostData()); l1Cost != nil {
+				cost.Add(cost, l1Cost)
+			}
+		}
+		if balance := p.state.GetBalance(from).ToBig(); balance.Cmp(cost) < 0 {
+			return fmt.Errorf("invalid transaction %d: %w: balance %v, bundle cost %v", i, core.ErrInsufficientFunds, balance, cost)
+		}
+	}
+	return nil
+}
+
+// bundleSenders returns the distinct senders of the transactions of a bundle,
+// which passed validation already.
+func (p *BundlePool) bundleSenders(bundle *Bundle) []common.Address {
+	var senders []common.Address
+	for _, tx := range bundle.Txs {
+		from, _ := types.Sender(p.signer, tx)
+		if !slices.Contains(senders, from) {
+			senders = append(senders, from)
+		}
+	}
+	return senders
+}
+
+// remove drops the lookup entries of a bundle.
+func (p *BundlePool) remove(bundle *Bundle) {
+	for _, sender := range p.known[bundle.Hash()].senders {
+		if p.senders[sender]--; p.senders[sender] == 0 {
+			delete(p.senders, sender)
+		}
+	}
+	delete(p.known, bundle.Hash())
+	for _, tx := range bundle.Txs {
+		delete(p.lookup, tx.Hash())
+	}
+	// Transactions may be shared by other bundles, restore their entries.
+	for _, other := range p.bundles {
+		if _, ok := p.known[other.Hash()]; !ok {
+			continue
+		}
+		for _, tx := range other.Txs {
+			p.lookup[tx.Hash()] = tx
+		}
+	}
+}
+
+// Bundles returns the bundles which may be included in the block with the given
+// number, in the order they were added.
+func (p *BundlePool) Bundles(number uint64) []*Bundle {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	var bundles []*Bundle
+	for _, bundle := range p.bundles {
+		if bundle.Targets(number) {
+			bundles = append(bundles, bundle)
+		}
+	}
+	return bundles
+}
+
+// Pending returns no transactions, as bundled transactions are not processable
+// one by one: the block builder retrieves whole bundles through Bundles.
+func (p *BundlePool) Pending(filter txpool.PendingFilter) map[common.Address][]*txpool.LazyTransaction {
+	return map[common.Address][]*txpool.LazyTransaction{}
+}
+
+// SubscribeTransactions subscribes to new transaction events. No events are
+// sent, as bundled transactions are not announced.
+func (p *BundlePool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool) event.Subscription {
+	return p.txFeed.Subscribe(ch)
+}
+
+// SubscribeTxEvents registers a subscription for the lifecycle events of the
+// bundled transactions.
+func (p *BundlePool) SubscribeTxEvents(ch chan<- txpool.TxEvent) event.Subscription {
+	return p.txEvents.Subscribe(ch)
+}
+
+// Nonce returns zero, leaving it to the other subpools to report the next nonce
+// of an account: bundled transactions may be included or not at all.
+func (p *BundlePool) Nonce(addr common.Address) uint64 {
+	return 0
+}
+
+// Stats returns the number of bundled transactions, all considered pending.
+func (p *BundlePool) Stats() (int, int) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return len(p.lookup), 0
+}
+
+// Content returns no transactions, as bundled transactions are not processable
+// one by one.
+func (p *BundlePool) Content() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	return make(map[common.Address][]*types.Transaction), make(map[common.Address][]*types.Transaction)
+}
+
+// ContentFrom returns no transactions, as bundled transactions are not
+// processable one by one.
+func (p *BundlePool) ContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
+	return []*types.Transaction{}, []*types.Transaction{}
+}
+
+// Locals returns no accounts, as bundles are never local.
+func (p *BundlePool) Locals() []common.Address {
+	return []common.Address{}
+}
+
+// Status returns the known status of a bundled transaction: pending, as long
+// as the bundle is waiting for inclusion.
+func (p *BundlePool) Status(hash common.Hash) txpool.TxStatus {
+	if p.Has(hash) {
+		return txpool.TxStatusPending
+	}
+	return txpool.TxStatusUnknown
+}
+
+// slicesContainsTx reports whether the transaction with the given hash is part
+// of the list.
+func slicesContainsTx(txs types.Transactions, hash common.Hash) bool {
+	for _, tx := range txs {
+		if tx.Hash() == hash {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,332 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bundlepool
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// testBlockChain is a mock of the live chain for testing the pool.
+type testBlockChain struct {
+	config  *params.ChainConfig
+	statedb *state.StateDB
+}
+
+func (bc *testBlockChain) Config() *params.ChainConfig {
+	return bc.config
+}
+
+func (bc *testBlockChain) GetBlock(common.Hash, uint64) *types.Block {
+	return nil
+}
+
+func (bc *testBlockChain) StateAt(common.Hash) (*state.StateDB, error) {
+	return bc.statedb, nil
+}
+
+func testHeader(number uint64) *types.Header {
+	return &types.Header{
+		Number:   new(big.Int).SetUint64(number),
+		GasLimit: 30_000_000,
+		BaseFee:  big.NewInt(params.InitialBaseFee),
+	}
+}
+
+func testTransaction(nonce uint64, key *ecdsa.PrivateKey) *types.Transaction {
+	return types.MustSignNewTx(key, types.LatestSigner(params.TestChainConfig), &types.DynamicFeeTx{
+		ChainID:   params.TestChainConfig.ChainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(params.InitialBaseFee),
+		Gas:       params.TxGas,
+		To:        &common.Address{},
+	})
+}
+
+// newTestPool creates a bundle pool on top of a state where the account of the
+// given key has sent a transaction already, and can pay for a hundred more.
+func newTestPool(t *testing.T, config Config, key *ecdsa.PrivateKey) *BundlePool {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetNonce(crypto.PubkeyToAddress(key.PublicKey), 1)
+	fund(statedb, key, 100)
+
+	pool := New(config, &testBlockChain{config: params.TestChainConfig, statedb: statedb})
+	if err := pool.Init(1, testHeader(1), nil); err != nil {
+		t.Fatalf("failed to init pool: %v", err)
+	}
+	return pool
+}
+
+// fund credits the account of the given key with the cost of the given number
+// of test transactions.
+func fund(statedb *state.StateDB, key *ecdsa.PrivateKey, txs uint64) {
+	cost := new(uint256.Int).Mul(uint256.NewInt(params.TxGas*params.InitialBaseFee), uint256.NewInt(txs))
+	statedb.SetBalance(crypto.PubkeyToAddress(key.PublicKey), cost, tracing.BalanceChangeUnspecified)
+}
+
+func TestAddBundle(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	pool := newTestPool(t, Config{MaxBundles: 2, MaxTxs: 2}, key)
+	fund(pool.state, other, 1)
+
+	tests := []struct {
+		bundle *Bundle
+		err    error
+	}{
+		{&Bundle{}, ErrEmptyBundle},
+		{&Bundle{Txs: types.Transactions{testTransaction(1, key), testTransaction(2, key), testTransaction(3, key)}}, ErrBundleTooLarge},
+		{&Bundle{Txs: types.Transactions{testTransaction(0, key)}}, core.ErrNonceTooLow},
+		{&Bundle{Txs: types.Transactions{testTransaction(1, key)}, MaxBlock: 1}, ErrBundleExpired},
+		{&Bundle{Txs: types.Transactions{testTransaction(1, key)}, MinBlock: 5, MaxBlock: 4}, ErrInvalidBlockRange},
+		{&Bundle{Txs: types.Transactions{testTransaction(1, key)}, RevertingTxHashes: []common.Hash{{0x01}}}, ErrUnknownRevertingTx},
+		{&Bundle{Txs: types.Transactions{testTransaction(1, key), testTransaction(0, other)}}, nil},
+		{&Bundle{Txs: types.Transactions{testTransaction(1, key), testTransaction(0, other)}}, txpool.ErrAlreadyKnown},
+		{&Bundle{Txs: types.Transactions{testTransaction(1, key)}, MinBlock: 3}, nil},
+		{&Bundle{Txs: types.Transactions{testTransaction(2, key)}}, ErrBundlePoolFull},
+	}
+	for i, tt := range tests {
+		if err := pool.AddBundle(tt.bundle); !errors.Is(err, tt.err) {
+			t.Errorf("bundle %d: wrong error: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Errorf("wrong number of bundled transactions: have %d, want 2", pending)
+	}
+	if !pool.Has(testTransaction(0, other).Hash()) {
+		t.Errorf("bundled transaction not found")
+	}
+	if bundles := pool.Bundles(2); len(bundles) != 1 {
+		t.Errorf("wrong number of bundles for block 2: have %d, want 1", len(bundles))
+	}
+	if bundles := pool.Bundles(3); len(bundles) != 2 {
+		t.Errorf("wrong number of bundles for block 3: have %d, want 2", len(bundles))
+	}
+	// Transactions can't be added outside of bundles.
+	if errs := pool.Add([]*types.Transaction{testTransaction(2, key)}, false, false); errs[0] == nil {
+		t.Errorf("transaction added outside of a bundle")
+	}
+}
+
+func TestResetDropsBundles(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	pool := newTestPool(t, DefaultConfig, key)
+
+	expiring := &Bundle{Txs: types.Transactions{testTransaction(1, key)}, MaxBlock: 2}
+	stale := &Bundle{Txs: types.Transactions{testTransaction(1, key), testTransaction(2, key)}}
+	valid := &Bundle{Txs: types.Transactions{testTransaction(2, key)}}
+
+	for _, bundle := range []*Bundle{expiring, stale, valid} {
+		if err := pool.AddBundle(bundle); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	// Include the first transaction in the new head: the expired bundle and the
+	// one starting with the included transaction are dropped.
+	pool.state.SetNonce(crypto.PubkeyToAddress(key.PublicKey), 2)
+	pool.Reset(testHeader(1), testHeader(2))
+
+	bundles := pool.Bundles(3)
+	if len(bundles) != 1 || bundles[0].Hash() != valid.Hash() {
+		t.Fatalf("wrong bundles left: %v", bundles)
+	}
+	if pool.Has(testTransaction(1, key).Hash()) {
+		t.Errorf("dropped transaction still known")
+	}
+	if !pool.Has(testTransaction(2, key).Hash()) {
+		t.Errorf("transaction shared with a valid bundle dropped")
+	}
+}
+
+// Tests that the transactions of a bundle included in a block are reported as
+// included, instead of dropped once the bundle is removed from the pool.
+func TestIncludedBundle(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	var (
+		gspec = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{crypto.PubkeyToAddress(key.PublicKey): {Balance: big.NewInt(params.Ether)}},
+		}
+		engine = ethash.NewFaker()
+		bundle = &Bundle{Txs: types.Transactions{testTransaction(0, key), testTransaction(1, key)}}
+	)
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	pool := New(DefaultConfig, chain)
+	txs, err := txpool.New(1, chain, []txpool.SubPool{pool})
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer txs.Close()
+
+	if err := pool.AddBundle(bundle); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, 1, func(i int, b *core.BlockGen) {
+		for _, tx := range bundle.Txs {
+			b.AddTx(tx)
+		}
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	// The history is recorded in the background, wait for the inclusion events.
+	last := func(hash common.Hash) txpool.TxEvent {
+		events := txs.TxHistory(hash)
+		if len(events) == 0 {
+			return txpool.TxEvent{}
+		}
+		return events[len(events)-1]
+	}
+	for _, tx := range bundle.Txs {
+		for start := time.Now(); last(tx.Hash()).Type != txpool.TxEventIncluded; time.Sleep(10 * time.Millisecond) {
+			if time.Since(start) > 5*time.Second {
+				t.Fatalf("transaction %x not reported as included: %v", tx.Hash(), txs.TxHistory(tx.Hash()))
+			}
+		}
+	}
+	if err := txs.Sync(); err != nil {
+		t.Fatalf("failed to sync pool: %v", err)
+	}
+	if len(pool.Bundles(2)) != 0 {
+		t.Errorf("included bundle kept in the pool")
+	}
+	// The bundle removal must not be reported after the inclusion either.
+	time.Sleep(50 * time.Millisecond)
+	for _, tx := range bundle.Txs {
+		if ev := last(tx.Hash()); ev.Type != txpool.TxEventIncluded {
+			t.Errorf("transaction %x: wrong last event: have %v, want included", tx.Hash(), ev)
+		}
+	}
+}
+
+func TestBundleLimits(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	poor, _ := crypto.GenerateKey()
+	pool := newTestPool(t, Config{MaxBundlesPerSender: 2, Lifetime: 10}, key)
+	fund(pool.state, poor, 1)
+
+	tests := []struct {
+		bundle *Bundle
+		err    error
+	}{
+		// The sender can pay for a single transaction, in any number of bundles
+		{&Bundle{Txs: types.Transactions{testTransaction(0, poor), testTransaction(1, poor)}}, core.ErrInsufficientFunds},
+		{&Bundle{Txs: types.Transactions{testTransaction(0, poor)}}, nil},
+		// Bundles can't target blocks past their lifetime
+		{&Bundle{Txs: types.Transactions{testTransaction(1, key)}, MinBlock: 12}, ErrInvalidBlockRange},
+		// Each sender of a bundle counts towards its bundle limit
+		{&Bundle{Txs: types.Transactions{testTransaction(1, key)}}, nil},
+		{&Bundle{Txs: types.Transactions{testTransaction(2, key), testTransaction(1, poor)}}, nil},
+		{&Bundle{Txs: types.Transactions{testTransaction(3, key)}}, ErrSenderLimit},
+		{&Bundle{Txs: types.Transactions{testTransaction(2, poor)}}, ErrSenderLimit},
+	}
+	for i, tt := range tests {
+		if err := pool.AddBundle(tt.bundle); !errors.Is(err, tt.err) {
+			t.Errorf("bundle %d: wrong error: have %v, want %v", i, err, tt.err)
+		}
+	}
+	// Spending the funds drops the bundles the sender can't pay for anymore
+	fund(pool.state, poor, 0)
+	pool.Reset(testHeader(1), testHeader(2))
+
+	if bundles := pool.Bundles(3); len(bundles) != 1 || bundles[0].Hash() != tests[3].bundle.Hash() {
+		t.Fatalf("wrong bundles left: %v", bundles)
+	}
+	if err := pool.AddBundle(&Bundle{Txs: types.Transactions{testTransaction(3, key)}}); err != nil {
+		t.Errorf("failed to add bundle after dropping others: %v", err)
+	}
+}
+
+func TestBundleEviction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	pool := newTestPool(t, Config{Lifetime: 5, MaxFailures: 2}, key)
+
+	failing := &Bundle{Txs: types.Transactions{testTransaction(1, key)}}
+	unbounded := &Bundle{Txs: types.Transactions{testTransaction(2, key)}}
+	bounded := &Bundle{Txs: types.Transactions{testTransaction(3, key)}, MaxBlock: 4}
+	for _, bundle := range []*Bundle{failing, unbounded, bounded} {
+		if err := pool.AddBundle(bundle); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	// Failures are only counted once per block
+	pool.ReportFailure(failing.Hash(), 2)
+	pool.ReportFailure(failing.Hash(), 2)
+	if !pool.Has(failing.Txs[0].Hash()) {
+		t.Fatalf("bundle dropped after failing in a single block")
+	}
+	pool.ReportFailure(failing.Hash(), 3)
+	if pool.Has(failing.Txs[0].Hash()) {
+		t.Fatalf("bundle kept after failing in too many blocks")
+	}
+	// Bundles expire at their last block, or after the lifetime of the pool
+	// without one
+	pool.Reset(testHeader(3), testHeader(4))
+	if bundles := pool.Bundles(5); len(bundles) != 1 || bundles[0].Hash() != unbounded.Hash() {
+		t.Fatalf("wrong bundles left: %v", bundles)
+	}
+	pool.Reset(testHeader(5), testHeader(6))
+	if len(pool.Bundles(7)) != 0 {
+		t.Fatalf("bundle kept after the end of its lifetime")
+	}
+}
+
+func TestBundleJSON(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	bundle := &Bundle{
+		Txs:               types.Transactions{testTransaction(0, key), testTransaction(1, key)},
+		RevertingTxHashes: []common.Hash{testTransaction(1, key).Hash()},
+		MaxBlock:          10,
+	}
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("failed to encode bundle: %v", err)
+	}
+	var dec Bundle
+	if err := json.Unmarshal(data, &dec); err != nil {
+		t.Fatalf("failed to decode bundle: %v", err)
+	}
+	if dec.Hash() != bundle.Hash() || dec.MinBlock != 0 || dec.MaxBlock != 10 {
+		t.Errorf("bundle mismatch after roundtrip: %s", data)
+	}
+	if !dec.AllowsRevert(bundle.Txs[1].Hash()) || dec.AllowsRevert(bundle.Txs[0].Hash()) {
+		t.Errorf("reverting transactions mismatch after roundtrip: %s", data)
+	}
+}
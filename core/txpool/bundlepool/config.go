@@ -0,0 +1,66 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bundlepool
+
+import (
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Config are the configuration parameters of the bundle pool.
+type Config struct {
+	MaxBundles          uint64 // Maximum number of bundles waiting for inclusion
+	MaxBundlesPerSender uint64 // Maximum number of bundles waiting for inclusion per sender
+	MaxTxs              uint64 // Maximum number of transactions in a bundle
+	Lifetime            uint64 // Maximum number of blocks a bundle waits for inclusion
+	MaxFailures         uint64 // Number of blocks a bundle may fail to be included in before being dropped
+}
+
+// DefaultConfig contains the default configurations for the bundle pool.
+var DefaultConfig = Config{
+	MaxBundles:          1024,
+	MaxBundlesPerSender: 16,
+	MaxTxs:              16,
+	Lifetime:            300,
+	MaxFailures:         25,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) sanitize() Config {
+	conf := *config
+	if conf.MaxBundles < 1 {
+		log.Warn("Sanitizing invalid bundlepool bundle limit", "provided", conf.MaxBundles, "updated", DefaultConfig.MaxBundles)
+		conf.MaxBundles = DefaultConfig.MaxBundles
+	}
+	if conf.MaxBundlesPerSender < 1 {
+		log.Warn("Sanitizing invalid bundlepool per-sender bundle limit", "provided", conf.MaxBundlesPerSender, "updated", DefaultConfig.MaxBundlesPerSender)
+		conf.MaxBundlesPerSender = DefaultConfig.MaxBundlesPerSender
+	}
+	if conf.MaxTxs < 1 {
+		log.Warn("Sanitizing invalid bundlepool transaction limit", "provided", conf.MaxTxs, "updated", DefaultConfig.MaxTxs)
+		conf.MaxTxs = DefaultConfig.MaxTxs
+	}
+	if conf.Lifetime < 1 {
+		log.Warn("Sanitizing invalid bundlepool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
+		conf.Lifetime = DefaultConfig.Lifetime
+	}
+	if conf.MaxFailures < 1 {
+		log.Warn("Sanitizing invalid bundlepool failure limit", "provided", conf.MaxFailures, "updated", DefaultConfig.MaxFailures)
+		conf.MaxFailures = DefaultConfig.MaxFailures
+	}
+	return conf
+}
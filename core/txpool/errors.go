@@ -60,4 +60,16 @@ var (
 	// input transaction of non-blob type when a blob transaction from this sender
 	// remains pending (and vice-versa).
 	ErrAlreadyReserved = errors.New("address already reserved")
+
+	// ErrInvalidExecutingMessage is returned if a transaction emits a cross-chain
+	// executing message that the interop supervisor could not verify.
+	ErrInvalidExecutingMessage = errors.New("invalid executing message")
+
+	// ErrSenderRateLimited is returned if a transaction is submitted by a sender
+	// above the transaction admission rate allowed per account.
+	ErrSenderRateLimited = errors.New("sender rate limit exceeded")
+
+	// ErrOriginRateLimited is returned if a transaction is submitted by an RPC
+	// client above the transaction admission rate allowed per origin.
+	ErrOriginRateLimited = errors.New("origin rate limit exceeded")
 )
@@ -0,0 +1,223 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// TxEventType is the kind of a transaction lifecycle event.
+type TxEventType string
+
+const (
+	TxEventAdded    TxEventType = "added"    // Transaction accepted into the pool
+	TxEventPromoted TxEventType = "promoted" // Transaction moved from the queue to the pending set
+	TxEventDemoted  TxEventType = "demoted"  // Transaction moved from the pending set back to the queue
+	TxEventReplaced TxEventType = "replaced" // Transaction replaced by another one with the same nonce
+	TxEventDropped  TxEventType = "dropped"  // Transaction removed from the pool without being included
+	TxEventIncluded TxEventType = "included" // Transaction included in a block
+)
+
+// TxDropReason is the reason why a transaction was dropped from the pool.
+type TxDropReason string
+
+const (
+	TxDropUnderpriced   TxDropReason = "underpriced"    // Evicted from a full pool by better paying transactions
+	TxDropTipTooLow     TxDropReason = "tip-too-low"    // Below a raised minimum gas tip
+	TxDropNonceTooLow   TxDropReason = "nonce-too-low"  // Nonce used on chain, possibly by the transaction itself
+	TxDropNonceGap      TxDropReason = "nonce-gap"      // Preceding nonces are missing from the pool
+	TxDropNoFunds       TxDropReason = "nofunds"        // Balance can't cover the transaction cost
+	TxDropGasLimit      TxDropReason = "gas-limit"      // Gas above the block gas limit
+	TxDropLifetime      TxDropReason = "lifetime"       // Queued for longer than the pool lifetime
+	TxDropAccountLimit  TxDropReason = "account-limit"  // Above the per-account transaction limit
+	TxDropPoolLimit     TxDropReason = "pool-limit"     // Above the global transaction limits of the pool
+	TxDropBundleExpired TxDropReason = "bundle-expired" // Bundle past its last target block
+	TxDropBundleFailed  TxDropReason = "bundle-failed"  // Bundle failed to be included in too many blocks
+	TxDropSuperseded    TxDropReason = "superseded"     // A better paying transaction with the same nonce is pending
+	TxDropConditional   TxDropReason = "conditional"    // Preconditions failed on top of a block being built
+)
+
+// TxEvent is a lifecycle event of a transaction in the pool.
+type TxEvent struct {
+	Hash   common.Hash  `json:"hash"`
+	Type   TxEventType  `json:"type"`
+	Reason TxDropReason `json:"reason,omitempty"` // Set for dropped transactions
+	Time   time.Time    `json:"time"`
+
+	ReplacedBy  *common.Hash    `json:"replacedBy,omitempty"`  // Set for replaced transactions
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"` // Set for included transactions
+	BlockHash   *common.Hash    `json:"blockHash,omitempty"`   // Set for included transactions
+}
+
+// txEventQueueLimit is the maximum number of events waiting to be delivered to a
+// subscriber. Events beyond it are discarded, to not let a slow subscriber hold
+// up the pool or grow its queue without bounds.
+const txEventQueueLimit = 4096
+
+// txEventDroppedMeter counts the lifecycle events discarded due to a full queue.
+var txEventDroppedMeter = metrics.NewRegisteredMeter("txpool/events/dropped", nil)
+
+// TxEventFeed publishes the lifecycle events of the transactions of a subpool.
+//
+// Events are recorded while the subpool holds its lock, and handed over to the
+// subscribers once it has released it. Each subscriber is fed from its own
+// bounded queue by a dedicated goroutine, so that slow subscribers can't stall
+// the pool nor each other. If a subscriber falls too far behind, it loses events.
+type TxEventFeed struct {
+	queued []TxEvent                // Events recorded since the last flush
+	subs   map[*txEventSub]struct{} // Active subscribers
+	closed bool
+	lock   sync.Mutex
+
+	start sync.Once
+	quit  chan struct{} // Termination signal of the subscriber goroutines
+}
+
+// txEventSub is a subscriber of a TxEventFeed, with the events flushed but not
+// yet delivered to it.
+type txEventSub struct {
+	pending []TxEvent     // Events waiting to be delivered, guarded by the feed lock
+	wake    chan struct{} // Notification of newly flushed events
+}
+
+// Subscribe registers a subscription for the transaction lifecycle events.
+func (f *TxEventFeed) Subscribe(ch chan<- TxEvent) event.Subscription {
+	f.start.Do(f.init)
+
+	sub := &txEventSub{wake: make(chan struct{}, 1)}
+
+	f.lock.Lock()
+	if !f.closed {
+		f.subs[sub] = struct{}{}
+	}
+	f.lock.Unlock()
+
+	return event.NewSubscription(func(unsubbed <-chan struct{}) error {
+		defer func() {
+			f.lock.Lock()
+			delete(f.subs, sub)
+			f.lock.Unlock()
+		}()
+		for {
+			select {
+			case <-sub.wake:
+				f.lock.Lock()
+				events := sub.pending
+				sub.pending = nil
+				f.lock.Unlock()
+
+				for _, ev := range events {
+					select {
+					case ch <- ev:
+					case <-unsubbed:
+						return nil
+					case <-f.quit:
+						return nil
+					}
+				}
+			case <-unsubbed:
+				return nil
+			case <-f.quit:
+				return nil
+			}
+		}
+	})
+}
+
+// Record queues an event of the given type until the next Flush.
+func (f *TxEventFeed) Record(hash common.Hash, typ TxEventType) {
+	f.record(TxEvent{Hash: hash, Type: typ})
+}
+
+// RecordDrop queues a drop event with the given reason until the next Flush.
+func (f *TxEventFeed) RecordDrop(hash common.Hash, reason TxDropReason) {
+	f.record(TxEvent{Hash: hash, Type: TxEventDropped, Reason: reason})
+}
+
+// RecordReplace queues a replacement event until the next Flush.
+func (f *TxEventFeed) RecordReplace(hash common.Hash, by common.Hash) {
+	f.record(TxEvent{Hash: hash, Type: TxEventReplaced, ReplacedBy: &by})
+}
+
+// RecordInclusion queues an inclusion event in the given block until the next
+// Flush.
+func (f *TxEventFeed) RecordInclusion(hash common.Hash, number uint64, block common.Hash) {
+	f.record(TxEvent{Hash: hash, Type: TxEventIncluded, BlockNumber: (*hexutil.Uint64)(&number), BlockHash: &block})
+}
+
+func (f *TxEventFeed) record(ev TxEvent) {
+	ev.Time = time.Now()
+
+	f.lock.Lock()
+	f.queued = append(f.queued, ev)
+	f.lock.Unlock()
+}
+
+// Flush hands the queued events over to the subscribers, without waiting for
+// them to be delivered. It must not be called with the lock of the subpool held.
+func (f *TxEventFeed) Flush() {
+	f.start.Do(f.init)
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	events := f.queued
+	f.queued = nil
+	if f.closed || len(events) == 0 {
+		return
+	}
+	for sub := range f.subs {
+		batch := events
+		if room := txEventQueueLimit - len(sub.pending); len(batch) > room {
+			txEventDroppedMeter.Mark(int64(len(batch) - room))
+			batch = batch[:room]
+		}
+		sub.pending = append(sub.pending, batch...)
+
+		select {
+		case sub.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Close stops the subscriber goroutines, discarding the events not yet sent.
+func (f *TxEventFeed) Close() {
+	f.start.Do(f.init)
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if !f.closed {
+		f.closed = true
+		f.queued = nil
+		clear(f.subs)
+		close(f.quit)
+	}
+}
+
+// init sets up the subscriber bookkeeping.
+func (f *TxEventFeed) init() {
+	f.subs = make(map[*txEventSub]struct{})
+	f.quit = make(chan struct{})
+}
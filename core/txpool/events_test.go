@@ -0,0 +1,70 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that a subscriber never reading its events neither blocks the feed nor
+// the other subscribers, and is terminated when the feed is closed.
+func TestTxEventFeedStalledSubscriber(t *testing.T) {
+	var feed TxEventFeed
+
+	stalled := feed.Subscribe(make(chan TxEvent))
+	defer stalled.Unsubscribe()
+
+	events := make(chan TxEvent, 16)
+	sub := feed.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	// Flush more events than a subscriber may queue, without reading them.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 2*txEventQueueLimit; i++ {
+			feed.RecordInclusion(common.Hash{byte(i)}, uint64(i), common.Hash{})
+			feed.Flush()
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("feed stalled by the subscriber")
+	}
+	// The other subscriber still receives the events, up to its queue limit.
+	for i := 0; i < txEventQueueLimit; i++ {
+		select {
+		case ev := <-events:
+			if ev.Hash != (common.Hash{byte(i)}) || ev.Type != TxEventIncluded || uint64(*ev.BlockNumber) != uint64(i) {
+				t.Fatalf("event %d: wrong event %+v", i, ev)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event %d not delivered", i)
+		}
+	}
+	// Closing the feed terminates the delivery to the stalled subscriber.
+	feed.Close()
+	select {
+	case <-stalled.Err():
+	case <-time.After(5 * time.Second):
+		t.Fatal("stalled subscription not terminated")
+	}
+}
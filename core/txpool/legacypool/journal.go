@@ -21,8 +21,8 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"sync"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -32,6 +32,10 @@ import (
 // into the journal, but no such file is currently open.
 var errNoActiveJournal = errors.New("no active journal")
 
+// errJournalFull is returned if a transaction is attempted to be inserted into
+// the journal, but it would grow above its size limit.
+var errJournalFull = errors.New("journal size limit reached")
+
 // devNull is a WriteCloser that just discards anything written into it. Its
 // goal is to allow the transaction journal to write into a fake journal when
 // loading transactions on startup without printing warnings due to no file
@@ -43,15 +47,28 @@ func (*devNull) Close() error                      { return nil }
 
 // journal is a rotating log of transactions with the aim of storing locally
 // created transactions to allow non-executed ones to survive node restarts.
+//
+// Insertions may run concurrently with a rotation, in which case they are held
+// in memory and appended to the regenerated journal.
 type journal struct {
 	path   string         // Filesystem path to store the transactions at
 	writer io.WriteCloser // Output stream to write new transactions into
+
+	limit     uint64 // Maximum size of the journal in bytes (0 = unlimited)
+	size      uint64 // Current size of the journal in bytes
+	truncated bool   // Whether the last rotation dropped transactions above the limit
+
+	rotating bool                 // Whether a rotation is in progress
+	backlog  []*types.Transaction // Transactions inserted during the rotation
+	lock     sync.Mutex
 }
 
-// newTxJournal creates a new transaction journal to
-func newTxJournal(path string) *journal {
+// newTxJournal creates a new transaction journal to store transactions at the
+// given path, up to the given size limit.
+func newTxJournal(path string, limit uint64) *journal {
 	return &journal{
-		path: path,
+		path:  path,
+		limit: limit,
 	}
 }
 
@@ -70,8 +87,9 @@ func (journal *journal) load(add func([]*types.Transaction) []error) error {
 	defer input.Close()
 
 	// Temporarily discard any journal additions (don't double add on load)
-	journal.writer = new(devNull)
-	defer func() { journal.writer = nil }()
+	limit := journal.limit
+	journal.writer, journal.limit = new(devNull), 0
+	defer func() { journal.writer, journal.limit = nil, limit }()
 
 	// Inject all transactions from the journal into the pool
 	stream := rlp.NewStream(input, 0)
@@ -96,7 +114,12 @@ func (journal *journal) load(add func([]*types.Transaction) []error) error {
 		// Parse the next transaction and terminate on error
 		tx := new(types.Transaction)
 		if err = stream.Decode(tx); err != nil {
-			if err != io.EOF {
+			switch {
+			case errors.Is(err, io.ErrUnexpectedEOF):
+				// The node most probably crashed midway writing a transaction,
+				// keep the ones before it. The next rotation drops the leftover.
+				log.Warn("Discarding truncated transaction journal entry", "path", journal.path, "loaded", total)
+			case err != io.EOF:
 				failure = err
 			}
 			if batch.Len() > 0 {
@@ -119,39 +142,104 @@ func (journal *journal) load(add func([]*types.Transaction) []error) error {
 
 // insert adds the specified transaction to the local disk journal.
 func (journal *journal) insert(tx *types.Transaction) error {
+	journal.lock.Lock()
+	defer journal.lock.Unlock()
+
+	if journal.rotating {
+		journal.backlog = append(journal.backlog, tx)
+		return nil
+	}
 	if journal.writer == nil {
 		return errNoActiveJournal
 	}
-	if err := rlp.Encode(journal.writer, tx); err != nil {
+	blob, err := rlp.EncodeToBytes(tx)
+	if err != nil {
 		return err
 	}
-	return nil
+	if journal.limit > 0 && journal.size+uint64(len(blob)) > journal.limit {
+		return errJournalFull
+	}
+	n, err := journal.writer.Write(blob)
+	journal.size += uint64(n)
+	return err
+}
+
+// isTruncated returns whether the last rotation dropped transactions above the
+// size limit of the journal.
+func (journal *journal) isTruncated() bool {
+	journal.lock.Lock()
+	defer journal.lock.Unlock()
+
+	return journal.truncated
+}
+
+// suspend holds the insertions in memory until the end of the next rotation, so
+// that the transactions added while the pool contents are gathered for it are
+// not lost.
+func (journal *journal) suspend() {
+	journal.lock.Lock()
+	defer journal.lock.Unlock()
+
+	journal.rotating = true
 }
 
 // rotate regenerates the transaction journal based on the current contents of
-// the transaction pool.
-func (journal *journal) rotate(all map[common.Address]types.Transactions) error {
+// the transaction pool, given per account in the order to keep them in. If the
+// journal has a size limit, the transactions of the accounts above it are dropped,
+// from their first one not fitting onwards.
+//
+// The new journal is synced to disk before replacing the live one, so that a
+// crash midway leaves the previous journal intact. The journal file is written
+// without holding the journal lock, insertions meanwhile are appended after.
+func (journal *journal) rotate(all []types.Transactions) error {
+	journal.lock.Lock()
+	journal.rotating = true
+	writer := journal.writer
+	journal.writer = nil
+	journal.lock.Unlock()
+
+	defer func() {
+		journal.lock.Lock()
+		journal.rotating, journal.backlog = false, nil
+		journal.lock.Unlock()
+	}()
 	// Close the current journal (if any is open)
-	if journal.writer != nil {
-		if err := journal.writer.Close(); err != nil {
+	if writer != nil {
+		if err := writer.Close(); err != nil {
 			return err
 		}
-		journal.writer = nil
 	}
 	// Generate a new journal with the contents of the current pool
 	replacement, err := os.OpenFile(journal.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
 		return err
 	}
-	journaled := 0
+	var (
+		journaled, dropped int
+		size               uint64
+	)
 	for _, txs := range all {
-		for _, tx := range txs {
-			if err = rlp.Encode(replacement, tx); err != nil {
+		for i, tx := range txs {
+			blob, err := rlp.EncodeToBytes(tx)
+			if err != nil {
+				replacement.Close()
+				return err
+			}
+			if journal.limit > 0 && size+uint64(len(blob)) > journal.limit {
+				dropped += len(txs) - i
+				break
+			}
+			if _, err = replacement.Write(blob); err != nil {
 				replacement.Close()
 				return err
 			}
+			size += uint64(len(blob))
+			journaled++
 		}
-		journaled += len(txs)
+	}
+	if err = replacement.Sync(); err != nil {
+		replacement.Close()
+		return err
 	}
 	replacement.Close()
 
@@ -163,19 +251,48 @@ func (journal *journal) rotate(all map[common.Address]types.Transactions) error
 	if err != nil {
 		return err
 	}
+	journal.lock.Lock()
+	defer journal.lock.Unlock()
+
+	// Append the transactions inserted during the rotation
+	for _, tx := range journal.backlog {
+		blob, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			sink.Close()
+			return err
+		}
+		if journal.limit > 0 && size+uint64(len(blob)) > journal.limit {
+			dropped++
+			continue
+		}
+		if _, err = sink.Write(blob); err != nil {
+			sink.Close()
+			return err
+		}
+		size += uint64(len(blob))
+		journaled++
+	}
 	journal.writer = sink
+	journal.size = size
+	journal.truncated = dropped > 0
 
 	logger := log.Info
 	if len(all) == 0 {
 		logger = log.Debug
 	}
 	logger("Regenerated local transaction journal", "transactions", journaled, "accounts", len(all))
+	if dropped > 0 {
+		log.Warn("Transaction journal size limit reached", "limit", journal.limit, "dropped", dropped)
+	}
 
 	return nil
 }
 
 // close flushes the transaction journal contents to disk and closes the file.
 func (journal *journal) close() error {
+	journal.lock.Lock()
+	defer journal.lock.Unlock()
+
 	var err error
 
 	if journal.writer != nil {
@@ -0,0 +1,228 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// loadJournal loads the transactions of a journal, without validating them.
+func loadJournal(t *testing.T, journal *journal) types.Transactions {
+	t.Helper()
+
+	var loaded types.Transactions
+	err := journal.load(func(txs []*types.Transaction) []error {
+		loaded = append(loaded, txs...)
+		return make([]error, len(txs))
+	})
+	if err != nil {
+		t.Fatalf("failed to load journal: %v", err)
+	}
+	return loaded
+}
+
+// Tests that a journal with a transaction partially written, e.g. due to a crash
+// midway, still loads the transactions before it, and that the next rotation
+// drops the leftover.
+func TestJournalTruncatedEntry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _ = crypto.GenerateKey()
+		path   = filepath.Join(t.TempDir(), "transactions.rlp")
+		txs    = types.Transactions{transaction(0, 100000, key), transaction(1, 100000, key), transaction(2, 100000, key)}
+	)
+	journal := newTxJournal(path, 0)
+	if err := journal.rotate([]types.Transactions{txs[:2]}); err != nil {
+		t.Fatalf("failed to rotate journal: %v", err)
+	}
+	// Write only the first half of the last transaction
+	blob, _ := rlp.EncodeToBytes(txs[2])
+	if _, err := journal.writer.Write(blob[:len(blob)/2]); err != nil {
+		t.Fatalf("failed to write journal: %v", err)
+	}
+	journal.close()
+
+	journal = newTxJournal(path, 0)
+	loaded := loadJournal(t, journal)
+	if len(loaded) != 2 || loaded[0].Hash() != txs[0].Hash() || loaded[1].Hash() != txs[1].Hash() {
+		t.Fatalf("loaded transactions mismatch: have %d, want %d", len(loaded), 2)
+	}
+	if err := journal.rotate([]types.Transactions{loaded}); err != nil {
+		t.Fatalf("failed to rotate journal: %v", err)
+	}
+	journal.close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat journal: %v", err)
+	}
+	if have, want := uint64(info.Size()), journal.size; have != want {
+		t.Fatalf("journal size mismatch: have %d, want %d", have, want)
+	}
+	if len(loadJournal(t, newTxJournal(path, 0))) != 2 {
+		t.Fatalf("rotated journal not loaded")
+	}
+}
+
+// Tests that the journal doesn't grow above its size limit, neither on insertion
+// nor on rotation.
+func TestJournalSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _ = crypto.GenerateKey()
+		path   = filepath.Join(t.TempDir(), "transactions.rlp")
+		txs    = types.Transactions{transaction(0, 100000, key), transaction(1, 100000, key), transaction(2, 100000, key)}
+	)
+	blob, _ := rlp.EncodeToBytes(txs[0])
+	journal := newTxJournal(path, uint64(2*len(blob)))
+
+	if err := journal.rotate(nil); err != nil {
+		t.Fatalf("failed to rotate journal: %v", err)
+	}
+	for i, tx := range txs[:2] {
+		if err := journal.insert(tx); err != nil {
+			t.Fatalf("failed to insert transaction %d: %v", i, err)
+		}
+	}
+	if err := journal.insert(txs[2]); !errors.Is(err, errJournalFull) {
+		t.Fatalf("insertion above the limit error mismatch: have %v, want %v", err, errJournalFull)
+	}
+	// Rotating with too many transactions drops the ones above the limit
+	if err := journal.rotate([]types.Transactions{txs}); err != nil {
+		t.Fatalf("failed to rotate journal: %v", err)
+	}
+	if !journal.truncated {
+		t.Fatalf("journal rotation not marked truncated")
+	}
+	journal.close()
+
+	loaded := loadJournal(t, newTxJournal(path, uint64(2*len(blob))))
+	if len(loaded) != 2 || loaded[0].Hash() != txs[0].Hash() || loaded[1].Hash() != txs[1].Hash() {
+		t.Fatalf("loaded transactions mismatch: have %d, want %d", len(loaded), 2)
+	}
+}
+
+// Tests that the transactions inserted during a rotation are appended to the
+// regenerated journal.
+func TestJournalInsertDuringRotation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _ = crypto.GenerateKey()
+		path   = filepath.Join(t.TempDir(), "transactions.rlp")
+		txs    = types.Transactions{transaction(0, 100000, key), transaction(1, 100000, key)}
+	)
+	journal := newTxJournal(path, 0)
+	if err := journal.rotate(nil); err != nil {
+		t.Fatalf("failed to rotate journal: %v", err)
+	}
+	journal.suspend()
+	if err := journal.insert(txs[1]); err != nil {
+		t.Fatalf("failed to insert transaction: %v", err)
+	}
+	if err := journal.rotate([]types.Transactions{txs[:1]}); err != nil {
+		t.Fatalf("failed to rotate journal: %v", err)
+	}
+	journal.close()
+
+	loaded := loadJournal(t, newTxJournal(path, 0))
+	if len(loaded) != 2 || loaded[0].Hash() != txs[0].Hash() || loaded[1].Hash() != txs[1].Hash() {
+		t.Fatalf("loaded transactions mismatch: have %d, want %d", len(loaded), 2)
+	}
+}
+
+// Tests that a size limited journal keeps the transactions of the local accounts
+// first, then the ones of the best paying accounts.
+func TestJournalRotationOrder(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.JournalRemote = true
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	local, _ := crypto.GenerateKey()
+	cheap, _ := crypto.GenerateKey()
+	pricey, _ := crypto.GenerateKey()
+	for _, key := range []*ecdsa.PrivateKey{local, cheap, pricey} {
+		testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	}
+	var (
+		localTxs  = types.Transactions{pricedTransaction(0, 100000, big.NewInt(1), local), pricedTransaction(1, 100000, big.NewInt(1), local)}
+		cheapTxs  = types.Transactions{pricedTransaction(0, 100000, big.NewInt(2), cheap), pricedTransaction(1, 100000, big.NewInt(2), cheap)}
+		priceyTxs = types.Transactions{pricedTransaction(0, 100000, big.NewInt(3), pricey)}
+	)
+	for _, err := range pool.addLocals(localTxs) {
+		if err != nil {
+			t.Fatalf("failed to add local transaction: %v", err)
+		}
+	}
+	for _, err := range pool.addRemotesSync(append(cheapTxs, priceyTxs...)) {
+		if err != nil {
+			t.Fatalf("failed to add remote transaction: %v", err)
+		}
+	}
+	// Limit the journal to three transactions, leaving out the cheap account
+	want := types.Transactions{localTxs[0], localTxs[1], priceyTxs[0]}
+
+	var limit uint64
+	for _, tx := range want {
+		blob, _ := rlp.EncodeToBytes(tx)
+		limit += uint64(len(blob))
+	}
+	path := filepath.Join(t.TempDir(), "transactions.rlp")
+	journal := newTxJournal(path, limit)
+
+	pool.mu.Lock()
+	txs := pool.toJournal()
+	pool.mu.Unlock()
+
+	if err := journal.rotate(txs); err != nil {
+		t.Fatalf("failed to rotate journal: %v", err)
+	}
+	journal.close()
+
+	loaded := loadJournal(t, newTxJournal(path, limit))
+	if len(loaded) != len(want) {
+		t.Fatalf("loaded transactions mismatch: have %d, want %d", len(loaded), len(want))
+	}
+	for i, tx := range loaded {
+		if tx.Hash() != want[i].Hash() {
+			t.Errorf("loaded transaction %d mismatch: have %x, want %x", i, tx.Hash(), want[i].Hash())
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"container/heap"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// l1FeeLevelData is the rollup cost data of the reference transaction the L1 fee
+// level is measured with, large enough for the rounding of its cost to only cost
+// the margins a negligible share of their precision.
+var l1FeeLevelData = types.RollupCostData{Ones: 1 << 20, FastLzSize: 1 << 20}
+
+// l1FeeParams are the inputs of the L1 data fees of the transactions: the fee
+// parameters stored in the L1Block contract, and the active L1 cost function.
+// As long as they don't change, the L1 costs cached by the lists remain valid.
+type l1FeeParams struct {
+	baseFee     common.Hash
+	overhead    common.Hash // pre-ecotone
+	scalar      common.Hash // pre-ecotone
+	blobBaseFee common.Hash // post-ecotone
+	scalars     common.Hash // post-ecotone
+
+	regolith, ecotone, fjord bool
+}
+
+// readL1FeeParams retrieves the L1 fee parameters in effect on top of the given
+// state, for a block with the given timestamp.
+func readL1FeeParams(config *params.ChainConfig, statedb types.StateGetter, time uint64) l1FeeParams {
+	return l1FeeParams{
+		baseFee:     statedb.GetState(types.L1BlockAddr, types.L1BaseFeeSlot),
+		overhead:    statedb.GetState(types.L1BlockAddr, types.OverheadSlot),
+		scalar:      statedb.GetState(types.L1BlockAddr, types.ScalarSlot),
+		blobBaseFee: statedb.GetState(types.L1BlockAddr, types.L1BlobBaseFeeSlot),
+		scalars:     statedb.GetState(types.L1BlockAddr, types.L1FeeScalarsSlot),
+		regolith:    config.IsOptimismRegolith(time),
+		ecotone:     config.IsOptimismEcotone(time),
+		fjord:       config.IsOptimismFjord(time),
+	}
+}
+
+// feesOnly reports whether the parameters differ from the given ones in the L1
+// base fees only. These scale the L1 costs of all transactions alike, whereas a
+// change of the scalars, the overhead or the cost function reshuffles them.
+func (p l1FeeParams) feesOnly(old l1FeeParams) bool {
+	p.baseFee, p.blobBaseFee = old.baseFee, old.blobBaseFee
+	return p == old
+}
+
+// updateL1Costs sets the L1 cost function of the pool to the one in effect on top
+// of the given head. If the L1 fees rose, the pending accounts whose L1 margin
+// was crossed are marked for the demotion pass of the reorg to check them. The
+// L1 costs cached by the lists are only repriced as the lists get checked.
+//
+// Note, the method assumes the pool lock is held!
+func (pool *LegacyPool) updateL1Costs(statedb *state.StateDB, head *types.Header) {
+	costFn := types.NewL1CostFunc(pool.chainconfig, statedb)
+	if costFn == nil {
+		return
+	}
+	pool.l1CostFn = func(rollupCostData types.RollupCostData) *big.Int {
+		return costFn(rollupCostData, head.Time)
+	}
+	params := readL1FeeParams(pool.chainconfig, statedb, head.Time)
+	if params == pool.l1FeeParams {
+		return
+	}
+	level := uint256.MustFromBig(pool.l1CostFn(l1FeeLevelData))
+
+	if params.feesOnly(pool.l1FeeParams) {
+		if level.Gt(pool.l1FeeLevel) {
+			for _, addr := range pool.l1Margins.cross(level) {
+				pool.l1Crossed[addr] = struct{}{}
+			}
+		}
+	} else {
+		// The margins can't tell the affected accounts apart, check all of them
+		for addr := range pool.pending {
+			pool.l1Crossed[addr] = struct{}{}
+		}
+		pool.l1Margins = newL1Margins()
+	}
+	pool.l1FeeParams = params
+	pool.l1FeeLevel = level
+	pool.l1Epoch++
+}
+
+// priceL1Costs recalculates the L1 data fees cached by a list, unless they were
+// already calculated with the current L1 fee parameters.
+//
+// Note, the method assumes the pool lock is held!
+func (pool *LegacyPool) priceL1Costs(list *list) {
+	if list.l1Epoch != pool.l1Epoch {
+		list.Reprice(pool.l1CostFn)
+		list.l1Epoch = pool.l1Epoch
+	}
+}
+
+// indexL1Margin prices the pending transactions of an account and indexes the
+// highest L1 fee level the account can afford them up to with its balance.
+//
+// The L1 costs scale with the fee level, but both are rounded down: an L1 cost c
+// priced at level l is below c+1 at level l, and thus below (c+1)*(l'+1)/l at any
+// level l' it may be priced at later. The margin of a transaction is the highest
+// l'+1 keeping that bound within the balance left after its execution cost.
+//
+// Note, the method assumes the pool lock is held!
+func (pool *LegacyPool) indexL1Margin(addr common.Address, list *list, balance *uint256.Int) {
+	if pool.l1CostFn == nil {
+		return
+	}
+	pool.priceL1Costs(list)
+
+	var (
+		level   = new(uint256.Int).SetAllOne()
+		divisor = new(uint256.Int)
+	)
+	for nonce, tx := range list.txs.items {
+		l1Cost := list.l1costs[nonce]
+		if l1Cost == nil {
+			continue
+		}
+		headroom := new(uint256.Int)
+		if cost := uint256.MustFromBig(tx.Cost()); cost.Lt(balance) {
+			headroom.Sub(balance, cost)
+		}
+		divisor.AddUint64(l1Cost, 1)
+		if margin, overflow := new(uint256.Int).MulDivOverflow(headroom, pool.l1FeeLevel, divisor); !overflow && margin.Lt(level) {
+			level = margin
+		}
+	}
+	pool.l1Margins.set(addr, level, balance)
+}
+
+// checkL1Costs reports whether the pending transactions of an account need their
+// L1 costs checked against its balance: if its L1 margin was crossed by a rise of
+// the L1 fees, or if its balance fell since the margin was indexed.
+//
+// Note, the method assumes the pool lock is held!
+func (pool *LegacyPool) checkL1Costs(addr common.Address, balance *uint256.Int) bool {
+	if pool.l1CostFn == nil {
+		return false
+	}
+	if _, ok := pool.l1Crossed[addr]; ok {
+		return true
+	}
+	indexed, ok := pool.l1Margins.balances[addr]
+	return !ok || balance.Lt(indexed)
+}
+
+// l1Margins is a min-heap of the pending accounts, ordered by the highest L1 fee
+// level they can afford the L1 data fees of their transactions up to. When the L1
+// fees rise, only the accounts whose margin was crossed are popped to be checked,
+// instead of repricing all the pooled transactions.
+type l1Margins struct {
+	addrs    []common.Address                // Heap of addresses, lowest margin first
+	index    map[common.Address]int          // Indices into the heap for replacements
+	levels   map[common.Address]*uint256.Int // Highest affordable L1 fee level per account, plus one
+	balances map[common.Address]*uint256.Int // Balances the margins were computed with
+}
+
+// newL1Margins creates an empty heap of L1 margins.
+func newL1Margins() *l1Margins {
+	return &l1Margins{
+		index:    make(map[common.Address]int),
+		levels:   make(map[common.Address]*uint256.Int),
+		balances: make(map[common.Address]*uint256.Int),
+	}
+}
+
+// set indexes the L1 margin of an account, replacing any previous one.
+func (m *l1Margins) set(addr common.Address, level *uint256.Int, balance *uint256.Int) {
+	m.levels[addr], m.balances[addr] = level, balance
+	if i, ok := m.index[addr]; ok {
+		heap.Fix(m, i)
+		return
+	}
+	heap.Push(m, addr)
+}
+
+// remove drops the L1 margin of an account, if indexed.
+func (m *l1Margins) remove(addr common.Address) {
+	if i, ok := m.index[addr]; ok {
+		heap.Remove(m, i)
+	}
+}
+
+// cross pops and returns the accounts with an L1 margin not above the given level.
+func (m *l1Margins) cross(level *uint256.Int) []common.Address {
+	var crossed []common.Address
+	for len(m.addrs) > 0 && !m.levels[m.addrs[0]].Gt(level) {
+		crossed = append(crossed, heap.Pop(m).(common.Address))
+	}
+	return crossed
+}
+
+// Len implements sort.Interface as part of heap.Interface, returning the number
+// of accounts tracked by the heap.
+func (m *l1Margins) Len() int {
+	return len(m.addrs)
+}
+
+// Less implements sort.Interface as part of heap.Interface, ordering the accounts
+// by their L1 margins, lowest first.
+func (m *l1Margins) Less(i, j int) bool {
+	return m.levels[m.addrs[i]].Lt(m.levels[m.addrs[j]])
+}
+
+// Swap implements sort.Interface as part of heap.Interface, maintaining both the
+// order of the accounts according to the heap, and the account->item slot mapping
+// for replacements.
+func (m *l1Margins) Swap(i, j int) {
+	m.index[m.addrs[i]], m.index[m.addrs[j]] = j, i
+	m.addrs[i], m.addrs[j] = m.addrs[j], m.addrs[i]
+}
+
+// Push implements heap.Interface, appending an item to the end of the account
+// ordering as well as the address to item slot mapping.
+func (m *l1Margins) Push(x any) {
+	m.index[x.(common.Address)] = len(m.addrs)
+	m.addrs = append(m.addrs, x.(common.Address))
+}
+
+// Pop implements heap.Interface, removing and returning the last element of the
+// heap along with its margin.
+//
+// Note, use `heap.Pop`, not `l1Margins.Pop`. This method is used by Go's heap,
+// to provide the functionality, it does not embed it.
+func (m *l1Margins) Pop() any {
+	size := len(m.addrs)
+	addr := m.addrs[size-1]
+	m.addrs = m.addrs[:size-1]
+
+	delete(m.index, addr)
+	delete(m.levels, addr)
+	delete(m.balances, addr)
+	return addr
+}
@@ -72,6 +72,7 @@ var (
 	pendingReplaceMeter   = metrics.NewRegisteredMeter("txpool/pending/replace", nil)
 	pendingRateLimitMeter = metrics.NewRegisteredMeter("txpool/pending/ratelimit", nil) // Dropped due to rate limiting
 	pendingNofundsMeter   = metrics.NewRegisteredMeter("txpool/pending/nofunds", nil)   // Dropped due to out-of-funds
+	pendingL1CostMeter    = metrics.NewRegisteredMeter("txpool/pending/l1cost", nil)    // Demoted due to unaffordable L1 costs
 
 	// Metrics for the queued pool
 	queuedDiscardMeter   = metrics.NewRegisteredMeter("txpool/queued/discard", nil)
@@ -90,6 +91,10 @@ var (
 	// throttleTxMeter counts how many transactions are rejected due to too-many-changes between
 	// txpool reorgs.
 	throttleTxMeter = metrics.NewRegisteredMeter("txpool/throttle", nil)
+
+	// senderLimitedMeter counts the transactions rejected due to the rate limit
+	// of their sender.
+	senderLimitedMeter = metrics.NewRegisteredMeter("txpool/sender/ratelimited", nil)
 	// reorgDurationTimer measures how long time a txpool reorg takes.
 	reorgDurationTimer = metrics.NewRegisteredTimer("txpool/reorgtime", nil)
 	// dropBetweenReorgHistogram counts how many drops we experience between two reorg runs. It is expected
@@ -131,6 +136,10 @@ type Config struct {
 	// When true, all transactions loaded from the journal are treated as remote.
 	JournalRemote bool
 
+	// JournalSize is the maximum size of the journal in bytes (0 = unlimited). The
+	// journal is regenerated from the pool contents early when growing above it.
+	JournalSize uint64
+
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
 
@@ -141,13 +150,17 @@ type Config struct {
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
 
+	SenderRate  float64 // Maximum number of transactions admitted per second and sender (0 = unlimited)
+	SenderBurst uint64  // Maximum number of transactions admitted at once per sender, above the rate
+
 	EffectiveGasCeil uint64 // if non-zero, a gas ceiling to enforce independent of the header's gaslimit value
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
 var DefaultConfig = Config{
-	Journal:   "transactions.rlp",
-	Rejournal: time.Hour,
+	Journal:     "transactions.rlp",
+	Rejournal:   time.Hour,
+	JournalSize: 64 * 1024 * 1024,
 
 	PriceLimit: 1,
 	PriceBump:  10,
@@ -212,6 +225,7 @@ type LegacyPool struct {
 	chain       BlockChain
 	gasTip      atomic.Pointer[uint256.Int]
 	txFeed      event.Feed
+	txEvents    txpool.TxEventFeed
 	signer      types.Signer
 	mu          sync.RWMutex
 
@@ -222,6 +236,8 @@ type LegacyPool struct {
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *journal    // Journal of local transaction to back up to disk
 
+	rejournalCh chan struct{} // Requests an early journal rotation, when it's full
+
 	reserve txpool.AddressReserver       // Address reserver to ensure exclusivity across subpools
 	pending map[common.Address]*list     // All currently processable transactions
 	queue   map[common.Address]*list     // Queued but non-processable transactions
@@ -239,7 +255,16 @@ type LegacyPool struct {
 
 	changesSinceReorg int // A counter for how many drops we've performed in-between reorg.
 
-	l1CostFn txpool.L1CostFunc // To apply L1 costs as rollup, optional field, may be nil.
+	included map[common.Hash]struct{} // Transactions included by the blocks of the reset in progress
+
+	senderLimiter *txpool.RateLimiter[common.Address] // Admission rate limit per sender, nil if unlimited
+
+	l1CostFn    txpool.L1CostFunc           // To apply L1 costs as rollup, optional field, may be nil.
+	l1FeeParams l1FeeParams                 // L1 fee parameters in effect on top of the current head
+	l1FeeLevel  *uint256.Int                // L1 fee level of the current parameters, see l1FeeLevelData
+	l1Epoch     uint64                      // Number of L1 fee parameter changes, to tell stale list prices apart
+	l1Margins   *l1Margins                  // Pending accounts by the highest L1 fee level they can afford
+	l1Crossed   map[common.Address]struct{} // Pending accounts to check the L1 costs of in the demotion pass
 }
 
 type txpoolResetRequest struct {
@@ -268,6 +293,11 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		reorgDoneCh:     make(chan chan struct{}),
 		reorgShutdownCh: make(chan struct{}),
 		initDoneCh:      make(chan struct{}),
+		rejournalCh:     make(chan struct{}, 1),
+		senderLimiter:   txpool.NewRateLimiter[common.Address](config.SenderRate, int(config.SenderBurst)),
+		l1FeeLevel:      new(uint256.Int),
+		l1Margins:       newL1Margins(),
+		l1Crossed:       make(map[common.Address]struct{}),
 	}
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
@@ -277,7 +307,7 @@ func New(config Config, chain BlockChain) *LegacyPool {
 	pool.priced = newPricedList(pool.all)
 
 	if (!config.NoLocals || config.JournalRemote) && config.Journal != "" {
-		pool.journal = newTxJournal(config.Journal)
+		pool.journal = newTxJournal(config.Journal, config.JournalSize)
 	}
 	return pool
 }
@@ -317,17 +347,23 @@ func (pool *LegacyPool) Init(gasTip uint64, head *types.Header, reserve txpool.A
 	pool.currentHead.Store(head)
 	pool.currentState = statedb
 	pool.pendingNonces = newNoncer(statedb)
+	pool.updateL1Costs(statedb, head)
 
 	// Start the reorg loop early, so it can handle requests generated during
 	// journal loading.
 	pool.wg.Add(1)
 	go pool.scheduleReorgLoop()
 
-	// If local transactions and journaling is enabled, load from disk
+	// If local transactions and journaling is enabled, load from disk. The loaded
+	// transactions were admitted before, don't rate limit their senders.
 	if pool.journal != nil {
-		add := pool.addLocals
+		add := func(txs []*types.Transaction) []error {
+			return pool.addTxs(txs, !pool.config.NoLocals, true, false)
+		}
 		if pool.config.JournalRemote {
-			add = pool.addRemotesSync // Use sync version to match pool.AddLocals
+			add = func(txs []*types.Transaction) []error {
+				return pool.addTxs(txs, false, true, false) // Use sync version to match local loading
+			}
 		}
 		if err := pool.journal.load(add); err != nil {
 			log.Warn("Failed to load transaction journal", "err", err)
@@ -392,21 +428,20 @@ func (pool *LegacyPool) loop() {
 					list := pool.queue[addr].Flatten()
 					for _, tx := range list {
 						pool.removeTx(tx.Hash(), true, true)
+						pool.txEvents.RecordDrop(tx.Hash(), txpool.TxDropLifetime)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
 			}
 			pool.mu.Unlock()
+			pool.txEvents.Flush()
 
 		// Handle local transaction journal rotation
 		case <-journal.C:
-			if pool.journal != nil {
-				pool.mu.Lock()
-				if err := pool.journal.rotate(pool.toJournal()); err != nil {
-					log.Warn("Failed to rotate local tx journal", "err", err)
-				}
-				pool.mu.Unlock()
-			}
+			pool.rotateJournal()
+
+		case <-pool.rejournalCh:
+			pool.rotateJournal()
 		}
 	}
 }
@@ -418,8 +453,17 @@ func (pool *LegacyPool) Close() error {
 	pool.wg.Wait()
 
 	if pool.journal != nil {
+		// Regenerate the journal on shutdown, so that the pool is restarted with
+		// its latest contents, without any since replaced or dropped transaction.
+		pool.mu.Lock()
+		if err := pool.journal.rotate(pool.toJournal()); err != nil {
+			log.Warn("Failed to rotate transaction journal", "err", err)
+		}
+		pool.mu.Unlock()
 		pool.journal.close()
 	}
+	pool.txEvents.Close()
+
 	log.Info("Transaction pool stopped")
 	return nil
 }
@@ -441,9 +485,17 @@ func (pool *LegacyPool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs
 	return pool.txFeed.Subscribe(ch)
 }
 
+// SubscribeTxEvents registers a subscription for the lifecycle events of the
+// transactions in the pool.
+func (pool *LegacyPool) SubscribeTxEvents(ch chan<- txpool.TxEvent) event.Subscription {
+	return pool.txEvents.Subscribe(ch)
+}
+
 // SetGasTip updates the minimum gas tip required by the transaction pool for a
 // new transaction, and drops all transactions below this threshold.
 func (pool *LegacyPool) SetGasTip(tip *big.Int) {
+	defer pool.txEvents.Flush()
+
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
@@ -458,6 +510,7 @@ func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 		drop := pool.all.RemotesBelowTip(tip)
 		for _, tx := range drop {
 			pool.removeTx(tx.Hash(), false, true)
+			pool.txEvents.RecordDrop(tx.Hash(), txpool.TxDropTipTooLow)
 		}
 		pool.priced.Removed(len(drop))
 	}
@@ -559,6 +612,14 @@ func (pool *LegacyPool) Pending(filter txpool.PendingFilter) map[common.Address]
 	for addr, list := range pool.pending {
 		txs := list.Flatten()
 
+		// Cap the lists at the first transaction rejected by the block builder,
+		// it is dropped on the next pool reset
+		for i, tx := range txs {
+			if tx.Rejected() {
+				txs = txs[:i]
+				break
+			}
+		}
 		// If the miner requests tip enforcement, cap the lists now
 		if minTipBig != nil && !pool.locals.contains(addr) {
 			for i, tx := range txs {
@@ -598,19 +659,48 @@ func (pool *LegacyPool) Locals() []common.Address {
 
 // toJournal retrieves all transactions that should be included in the journal,
 // grouped by origin account and sorted by nonce.
+//
+// The accounts are ordered by their priority to be kept in a size limited journal:
+// local accounts first, then the ones with the better paying lowest nonce
+// transaction, ties broken by address.
 // The returned transaction set is a copy and can be freely modified by calling code.
-func (pool *LegacyPool) toJournal() map[common.Address]types.Transactions {
+func (pool *LegacyPool) toJournal() []types.Transactions {
+	var txs map[common.Address]types.Transactions
 	if !pool.config.JournalRemote {
-		return pool.local()
+		txs = pool.local()
+	} else {
+		txs = make(map[common.Address]types.Transactions)
+		for addr, pending := range pool.pending {
+			txs[addr] = append(txs[addr], pending.Flatten()...)
+		}
+		for addr, queued := range pool.queue {
+			txs[addr] = append(txs[addr], queued.Flatten()...)
+		}
 	}
-	txs := make(map[common.Address]types.Transactions)
-	for addr, pending := range pool.pending {
-		txs[addr] = append(txs[addr], pending.Flatten()...)
+	addrs := make([]common.Address, 0, len(txs))
+	for addr, list := range txs {
+		if len(list) > 0 {
+			addrs = append(addrs, addr)
+		}
 	}
-	for addr, queued := range pool.queue {
-		txs[addr] = append(txs[addr], queued.Flatten()...)
+	sort.Slice(addrs, func(i, j int) bool {
+		if local := pool.locals.contains(addrs[i]); local != pool.locals.contains(addrs[j]) {
+			return local
+		}
+		a, b := txs[addrs[i]][0], txs[addrs[j]][0]
+		if cmp := a.GasFeeCapCmp(b); cmp != 0 {
+			return cmp > 0
+		}
+		if cmp := a.GasTipCapCmp(b); cmp != 0 {
+			return cmp > 0
+		}
+		return addrs[i].Cmp(addrs[j]) < 0
+	})
+	journal := make([]types.Transactions, len(addrs))
+	for i, addr := range addrs {
+		journal[i] = txs[addr]
 	}
-	return txs
+	return journal
 }
 
 // local retrieves all currently known local transactions, grouped by origin
@@ -672,6 +762,7 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 		},
 		ExistingExpenditure: func(addr common.Address) *big.Int {
 			if list := pool.pending[addr]; list != nil {
+				pool.priceL1Costs(list)
 				return list.totalcost.ToBig()
 			}
 			return new(big.Int)
@@ -679,13 +770,8 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 		ExistingCost: func(addr common.Address, nonce uint64) *big.Int {
 			if list := pool.pending[addr]; list != nil {
 				if tx := list.txs.Get(nonce); tx != nil {
-					cost := tx.Cost()
-					if pool.l1CostFn != nil {
-						if l1Cost := pool.l1CostFn(tx.RollupCostData()); l1Cost != nil { // add rollup cost
-							cost = cost.Add(cost, l1Cost)
-						}
-					}
-					return cost
+					pool.priceL1Costs(list)
+					return list.cost(tx).ToBig() // includes the rollup cost
 				}
 			}
 			return nil
@@ -705,7 +791,11 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 // If a newly added transaction is marked as local, its sending account will be
 // added to the allowlist, preventing any associated transaction from being dropped
 // out of the pool due to pricing constraints.
-func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, err error) {
+//
+// If limit is set, the transaction takes an admission token of its sender, as
+// done for newly submitted transactions, but not for the ones reinjected after a
+// reorg or loaded from the journal.
+func (pool *LegacyPool) add(tx *types.Transaction, local bool, limit bool) (replaced bool, err error) {
 	// If the transaction is already known, discard it
 	hash := tx.Hash()
 	if pool.all.Get(hash) != nil {
@@ -726,6 +816,13 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 	// already validated by this point
 	from, _ := types.Sender(pool.signer, tx)
 
+	// If the sender is submitting transactions too fast, discard it
+	if limit && !pool.senderLimiter.Allow(from) {
+		log.Trace("Discarding rate limited transaction", "hash", hash, "from", from)
+		senderLimitedMeter.Mark(1)
+		return false, txpool.ErrSenderRateLimited
+	}
+
 	// If the address is not yet known, request exclusivity to track the account
 	// only by this subpool until all transactions are evicted
 	var (
@@ -805,6 +902,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 
 			sender, _ := types.Sender(pool.signer, tx)
 			dropped := pool.removeTx(tx.Hash(), false, sender != from) // Don't unreserve the sender of the tx being added if last from the acc
+			pool.txEvents.RecordDrop(tx.Hash(), txpool.TxDropUnderpriced)
 
 			pool.changesSinceReorg += dropped
 		}
@@ -823,11 +921,14 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.txEvents.RecordReplace(old.Hash(), hash)
 		}
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
+		pool.indexL1Margin(from, list, pool.currentState.GetBalance(from))
 		pool.journalTx(from, tx)
 		pool.queueTxEvent(tx)
+		pool.txEvents.Record(hash, txpool.TxEventAdded)
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
 
 		// Successful promotion, bump the heartbeat
@@ -849,6 +950,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 		localGauge.Inc(1)
 	}
 	pool.journalTx(from, tx)
+	pool.txEvents.Record(hash, txpool.TxEventAdded)
 
 	log.Trace("Pooled new future transaction", "hash", hash, "from", from, "to", tx.To())
 	return replaced, nil
@@ -898,6 +1000,7 @@ func (pool *LegacyPool) enqueueTx(hash common.Hash, tx *types.Transaction, local
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.txEvents.RecordReplace(old.Hash(), hash)
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
@@ -925,11 +1028,41 @@ func (pool *LegacyPool) journalTx(from common.Address, tx *types.Transaction) {
 	if pool.journal == nil || (!pool.config.JournalRemote && !pool.locals.contains(from)) {
 		return
 	}
-	if err := pool.journal.insert(tx); err != nil {
+	err := pool.journal.insert(tx)
+	if errors.Is(err, errJournalFull) {
+		// Request the regeneration of the journal to make room, unless the pool
+		// contents can't fit in it in the first place. The transaction is not
+		// journaled meanwhile, the rotation picks it up from the pool.
+		if !pool.journal.isTruncated() {
+			select {
+			case pool.rejournalCh <- struct{}{}:
+			default:
+			}
+		}
+		return
+	}
+	if err != nil {
 		log.Warn("Failed to journal local transaction", "err", err)
 	}
 }
 
+// rotateJournal regenerates the transaction journal from the pool contents. The
+// pool lock is only held while gathering the contents, not while writing them.
+func (pool *LegacyPool) rotateJournal() {
+	if pool.journal == nil {
+		return
+	}
+	pool.journal.suspend()
+
+	pool.mu.Lock()
+	txs := pool.toJournal()
+	pool.mu.Unlock()
+
+	if err := pool.journal.rotate(txs); err != nil {
+		log.Warn("Failed to rotate local tx journal", "err", err)
+	}
+}
+
 // promoteTx adds a transaction to the pending (processable) list of transactions
 // and returns whether it was inserted or an older was better.
 //
@@ -940,6 +1073,7 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.pending[addr] = newList(true)
 	}
 	list := pool.pending[addr]
+	pool.priceL1Costs(list)
 
 	inserted, old := list.Add(tx, pool.config.PriceBump, pool.l1CostFn)
 	if !inserted {
@@ -947,13 +1081,17 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
+		pool.txEvents.RecordDrop(hash, txpool.TxDropSuperseded)
 		return false
 	}
+	pool.txEvents.Record(hash, txpool.TxEventPromoted)
+
 	// Otherwise discard any previous transaction and mark this
 	if old != nil {
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.txEvents.RecordReplace(old.Hash(), hash)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
@@ -1012,6 +1150,12 @@ func (pool *LegacyPool) addRemoteSync(tx *types.Transaction) error {
 // If sync is set, the method will block until all internal maintenance related
 // to the add is finished. Only use this during tests for determinism!
 func (pool *LegacyPool) Add(txs []*types.Transaction, local, sync bool) []error {
+	return pool.addTxs(txs, local, sync, true)
+}
+
+// addTxs enqueues a batch of transactions into the pool if they are valid,
+// taking admission tokens of their senders if limit is set.
+func (pool *LegacyPool) addTxs(txs []*types.Transaction, local, sync, limit bool) []error {
 	// Do not treat as local if local transactions have been disabled
 	local = local && !pool.config.NoLocals
 
@@ -1045,8 +1189,9 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, local, sync bool) []error
 
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
-	newErrs, dirtyAddrs := pool.addTxsLocked(news, local)
+	newErrs, dirtyAddrs := pool.addTxsLocked(news, local, limit)
 	pool.mu.Unlock()
+	pool.txEvents.Flush()
 
 	var nilSlot = 0
 	for _, err := range newErrs {
@@ -1066,11 +1211,11 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, local, sync bool) []error
 
 // addTxsLocked attempts to queue a batch of transactions if they are valid.
 // The transaction pool lock must be held.
-func (pool *LegacyPool) addTxsLocked(txs []*types.Transaction, local bool) ([]error, *accountSet) {
+func (pool *LegacyPool) addTxsLocked(txs []*types.Transaction, local, limit bool) ([]error, *accountSet) {
 	dirty := newAccountSet(pool.signer)
 	errs := make([]error, len(txs))
 	for i, tx := range txs {
-		replaced, err := pool.add(tx, local)
+		replaced, err := pool.add(tx, local, limit)
 		errs[i] = err
 		if err == nil && !replaced {
 			dirty.addTx(tx)
@@ -1109,6 +1254,18 @@ func (pool *LegacyPool) Get(hash common.Hash) *types.Transaction {
 	return tx
 }
 
+// ValidateTxBasics implements txpool.SubPool, checking whether a transaction
+// passes the stateless and stateful validation without adding it to the pool.
+func (pool *LegacyPool) ValidateTxBasics(tx *types.Transaction, local bool) error {
+	if err := pool.validateTxBasics(tx, local); err != nil {
+		return err
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.validateTx(tx, local || pool.locals.containsTx(tx))
+}
+
 // get returns a transaction if it is contained in the pool and nil otherwise.
 func (pool *LegacyPool) get(hash common.Hash) *types.Transaction {
 	return pool.all.Get(hash)
@@ -1165,11 +1322,13 @@ func (pool *LegacyPool) removeTx(hash common.Hash, outofbound bool, unreserve bo
 			// If no more pending transactions are left, remove the list
 			if pending.Empty() {
 				delete(pool.pending, addr)
+				pool.l1Margins.remove(addr)
 			}
 			// Postpone any invalidated transactions
 			for _, tx := range invalids {
 				// Internal shuffle shouldn't touch the lookup set.
 				pool.enqueueTx(tx.Hash(), tx, false, false)
+				pool.txEvents.Record(tx.Hash(), txpool.TxEventDemoted)
 			}
 			// Update the account nonce if needed
 			pool.pendingNonces.setIfLower(addr, tx.Nonce())
@@ -1356,7 +1515,9 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 
 	dropBetweenReorgHistogram.Update(int64(pool.changesSinceReorg))
 	pool.changesSinceReorg = 0 // Reset change counter
+	pool.included = nil
 	pool.mu.Unlock()
+	pool.txEvents.Flush()
 
 	// Notify subsystems for newly added transactions
 	for _, tx := range promoted {
@@ -1381,6 +1542,17 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 	// If we're reorging an old state, reinject all dropped transactions
 	var reinject types.Transactions
 
+	// Track the transactions included by the new blocks, to not report them
+	// as dropped once removed
+	pool.included = make(map[common.Hash]struct{})
+
+	if oldHead != nil && newHead != nil && oldHead.Hash() == newHead.ParentHash {
+		if block := pool.chain.GetBlock(newHead.Hash(), newHead.Number.Uint64()); block != nil {
+			for _, tx := range block.Transactions() {
+				pool.included[tx.Hash()] = struct{}{}
+			}
+		}
+	}
 	if oldHead != nil && oldHead.Hash() != newHead.ParentHash {
 		// If the reorg is too deep, avoid doing it (will happen during fast sync)
 		oldNum := oldHead.Number.Uint64()
@@ -1445,6 +1617,9 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 						return
 					}
 				}
+				for _, tx := range included {
+					pool.included[tx.Hash()] = struct{}{}
+				}
 				lost := make([]*types.Transaction, 0, len(discarded))
 				for _, tx := range types.TxDifference(discarded, included) {
 					if pool.Filter(tx) {
@@ -1468,34 +1643,33 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 	pool.currentState = statedb
 	pool.pendingNonces = newNoncer(statedb)
 
-	if costFn := types.NewL1CostFunc(pool.chainconfig, statedb); costFn != nil {
-		pool.l1CostFn = func(rollupCostData types.RollupCostData) *big.Int {
-			return costFn(rollupCostData, newHead.Time)
-		}
-	}
+	pool.updateL1Costs(statedb, newHead)
 
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	core.SenderCacher.Recover(pool.signer, reinject)
-	pool.addTxsLocked(reinject, false)
-}
-
-// reduceBalanceByL1Cost returns the given balance, reduced by the L1Cost of the first transaction in list if applicable
-// Other txs will get filtered out necessary.
-func (pool *LegacyPool) reduceBalanceByL1Cost(list *list, balance *uint256.Int) *uint256.Int {
-	if !list.Empty() && pool.l1CostFn != nil {
-		el := list.txs.FirstElement()
-		if l1Cost := pool.l1CostFn(el.RollupCostData()); l1Cost != nil {
-			l1Cost256 := uint256.MustFromBig(l1Cost)
-			if l1Cost256.Cmp(balance) >= 0 {
-				// Avoid underflow
-				balance = uint256.NewInt(0)
-			} else {
-				balance = new(uint256.Int).Sub(balance, l1Cost256)
-			}
-		}
+	pool.addTxsLocked(reinject, false, false)
+}
+
+// removeForwarded removes a transaction with a nonce below the one of its sender
+// from the lookup. The transactions included in the new blocks are reported as
+// such by the primary pool, the others are dropped: their nonce was used by other
+// transactions.
+func (pool *LegacyPool) removeForwarded(tx *types.Transaction) {
+	hash := tx.Hash()
+	pool.all.Remove(hash)
+	if _, ok := pool.included[hash]; !ok {
+		pool.txEvents.RecordDrop(hash, txpool.TxDropNonceTooLow)
 	}
-	return balance
+}
+
+// unpayableReason returns why a transaction was filtered out as too costly: its
+// gas being above the block gas limit, or else its cost above the balance.
+func unpayableReason(tx *types.Transaction, gasLimit uint64) txpool.TxDropReason {
+	if tx.Gas() > gasLimit {
+		return txpool.TxDropGasLimit
+	}
+	return txpool.TxDropNoFunds
 }
 
 // promoteExecutables moves transactions that have become processable from the
@@ -1515,29 +1689,42 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		// Drop all transactions that are deemed too old (low nonce)
 		forwards := list.Forward(pool.currentState.GetNonce(addr))
 		for _, tx := range forwards {
-			hash := tx.Hash()
-			pool.all.Remove(hash)
+			pool.removeForwarded(tx)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		balance := pool.currentState.GetBalance(addr)
-		balance = pool.reduceBalanceByL1Cost(list, balance)
 		// Drop all transactions that are too costly (low balance or out of gas)
 		drops, _ := list.Filter(balance, gasLimit)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.txEvents.RecordDrop(hash, unpayableReason(tx, gasLimit))
 		}
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
 
-		// Gather all executable transactions and promote them
-		readies := list.Ready(pool.pendingNonces.get(addr))
+		// Gather all executable transactions and promote them, up until the first
+		// one whose L1 cost can't be afforded, which remains queued until it can
+		var (
+			start = pool.pendingNonces.get(addr)
+			limit = uint64(math.MaxUint64)
+		)
+		if first := list.txs.FirstElement(); first != nil && first.Nonce() <= start {
+			pool.priceL1Costs(list)
+			if nonce, ok := list.FirstUnaffordable(balance); ok {
+				limit = nonce
+			}
+		}
+		readies := list.ReadyBelow(start, limit)
 		for _, tx := range readies {
 			hash := tx.Hash()
 			if pool.promoteTx(addr, hash, tx) {
 				promoted = append(promoted, tx)
 			}
 		}
+		if pending := pool.pending[addr]; pending != nil && len(readies) > 0 {
+			pool.indexL1Margin(addr, pending, balance)
+		}
 		log.Trace("Promoted queued transactions", "count", len(promoted))
 		queuedGauge.Dec(int64(len(readies)))
 
@@ -1548,6 +1735,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 			for _, tx := range caps {
 				hash := tx.Hash()
 				pool.all.Remove(hash)
+				pool.txEvents.RecordDrop(hash, txpool.TxDropAccountLimit)
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
 			queuedRateLimitMeter.Mark(int64(len(caps)))
@@ -1613,6 +1801,7 @@ func (pool *LegacyPool) truncatePending() {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
 						pool.all.Remove(hash)
+						pool.txEvents.RecordDrop(hash, txpool.TxDropPoolLimit)
 
 						// Update the account nonce to the dropped transaction
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
@@ -1640,6 +1829,7 @@ func (pool *LegacyPool) truncatePending() {
 					// Drop the transaction from the global pools too
 					hash := tx.Hash()
 					pool.all.Remove(hash)
+					pool.txEvents.RecordDrop(hash, txpool.TxDropPoolLimit)
 
 					// Update the account nonce to the dropped transaction
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
@@ -1687,6 +1877,7 @@ func (pool *LegacyPool) truncateQueue() {
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
 				pool.removeTx(tx.Hash(), true, true)
+				pool.txEvents.RecordDrop(tx.Hash(), txpool.TxDropPoolLimit)
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
@@ -1696,6 +1887,7 @@ func (pool *LegacyPool) truncateQueue() {
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			pool.removeTx(txs[i].Hash(), true, true)
+			pool.txEvents.RecordDrop(txs[i].Hash(), txpool.TxDropPoolLimit)
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1718,31 +1910,55 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		// Drop all transactions that are deemed too old (low nonce)
 		olds := list.Forward(nonce)
 		for _, tx := range olds {
+			pool.removeForwarded(tx)
+			log.Trace("Removed old pending transaction", "hash", tx.Hash())
+		}
+		// Drop all transactions rejected by the block builder for failed preconditions,
+		// and queue the ones after them back for later
+		rejects, invalids := list.FilterRejected()
+		for _, tx := range rejects {
 			hash := tx.Hash()
+			log.Trace("Removed rejected pending transaction", "hash", hash)
 			pool.all.Remove(hash)
-			log.Trace("Removed old pending transaction", "hash", hash)
+			pool.txEvents.RecordDrop(hash, txpool.TxDropConditional)
 		}
 		balance := pool.currentState.GetBalance(addr)
-		balance = pool.reduceBalanceByL1Cost(list, balance)
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
-		drops, invalids := list.Filter(balance, gasLimit)
+		drops, unpayables := list.Filter(balance, gasLimit)
+		invalids = append(invalids, unpayables...)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 			pool.all.Remove(hash)
+			pool.txEvents.RecordDrop(hash, unpayableReason(tx, gasLimit))
 		}
 		pendingNofundsMeter.Mark(int64(len(drops)))
 
+		// Queue the first transaction whose L1 cost can't be afforded anymore back for
+		// later, along with the ones after it. Only the accounts whose L1 margin was
+		// crossed by an L1 fee rise, or whose balance fell, need to be checked.
+		if pool.checkL1Costs(addr, balance) {
+			pool.priceL1Costs(list)
+			if nonce, ok := list.FirstUnaffordable(balance); ok {
+				tx := list.txs.Get(nonce)
+				_, followers := list.Remove(tx)
+				invalids = append(append(invalids, tx), followers...)
+				pendingL1CostMeter.Mark(1)
+			}
+			pool.indexL1Margin(addr, list, balance)
+		}
+
 		for _, tx := range invalids {
 			hash := tx.Hash()
 			log.Trace("Demoting pending transaction", "hash", hash)
 
 			// Internal shuffle shouldn't touch the lookup set.
 			pool.enqueueTx(hash, tx, false, false)
+			pool.txEvents.Record(hash, txpool.TxEventDemoted)
 		}
-		pendingGauge.Dec(int64(len(olds) + len(drops) + len(invalids)))
+		pendingGauge.Dec(int64(len(olds) + len(rejects) + len(drops) + len(invalids)))
 		if pool.locals.contains(addr) {
-			localGauge.Dec(int64(len(olds) + len(drops) + len(invalids)))
+			localGauge.Dec(int64(len(olds) + len(rejects) + len(drops) + len(invalids)))
 		}
 		// If there's a gap in front, alert (should never happen) and postpone all transactions
 		if list.Len() > 0 && list.txs.Get(nonce) == nil {
@@ -1753,17 +1969,20 @@ func (pool *LegacyPool) demoteUnexecutables() {
 
 				// Internal shuffle shouldn't touch the lookup set.
 				pool.enqueueTx(hash, tx, false, false)
+				pool.txEvents.Record(hash, txpool.TxEventDemoted)
 			}
 			pendingGauge.Dec(int64(len(gapped)))
 		}
 		// Delete the entire pending entry if it became empty.
 		if list.Empty() {
 			delete(pool.pending, addr)
+			pool.l1Margins.remove(addr)
 			if _, ok := pool.queue[addr]; !ok {
 				pool.reserve(addr, false)
 			}
 		}
 	}
+	clear(pool.l1Crossed)
 }
 
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
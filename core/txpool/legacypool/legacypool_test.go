@@ -24,6 +24,7 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -477,14 +478,14 @@ func TestChainFork(t *testing.T) {
 	resetState()
 
 	tx := transaction(0, 100000, key)
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 	pool.removeTx(tx.Hash(), true, true)
 
 	// reset the pool's internal state
 	resetState()
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 }
@@ -511,10 +512,10 @@ func TestDoubleNonce(t *testing.T) {
 	tx3, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(100), 1000000, big.NewInt(1), nil), signer, key)
 
 	// Add the first two transaction, ensure higher priced stays only
-	if replace, err := pool.add(tx1, false); err != nil || replace {
+	if replace, err := pool.add(tx1, false, true); err != nil || replace {
 		t.Errorf("first transaction insert failed (%v) or reported replacement (%v)", err, replace)
 	}
-	if replace, err := pool.add(tx2, false); err != nil || !replace {
+	if replace, err := pool.add(tx2, false, true); err != nil || !replace {
 		t.Errorf("second transaction insert failed (%v) or not reported replacement (%v)", err, replace)
 	}
 	<-pool.requestPromoteExecutables(newAccountSet(signer, addr))
@@ -526,7 +527,7 @@ func TestDoubleNonce(t *testing.T) {
 	}
 
 	// Add the third transaction and ensure it's not saved (smaller price)
-	pool.add(tx3, false)
+	pool.add(tx3, false, true)
 	<-pool.requestPromoteExecutables(newAccountSet(signer, addr))
 	if pool.pending[addr].Len() != 1 {
 		t.Error("expected 1 pending transactions, got", pool.pending[addr].Len())
@@ -549,7 +550,7 @@ func TestMissingNonce(t *testing.T) {
 	addr := crypto.PubkeyToAddress(key.PublicKey)
 	testAddBalance(pool, addr, big.NewInt(100000000000000))
 	tx := transaction(1, 100000, key)
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 	if len(pool.pending) != 0 {
@@ -2342,6 +2343,423 @@ func TestReplacementDynamicFee(t *testing.T) {
 	}
 }
 
+// Tests that the lifecycle events of transactions are published, along with the
+// reason of their removal from the pool.
+func TestTxEvents(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	events := make(chan txpool.TxEvent, 32)
+	sub := pool.SubscribeTxEvents(events)
+	defer sub.Unsubscribe()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000))
+
+	// Add a transaction and replace it once it's pending
+	var (
+		tx              = pricedTransaction(0, 100000, big.NewInt(1), key)
+		replacement     = pricedTransaction(0, 100000, big.NewInt(2), key)
+		replacementHash = replacement.Hash()
+	)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(replacement); err != nil {
+		t.Fatalf("failed to add replacement transaction: %v", err)
+	}
+	// Drain the account, the replacement is dropped on the next reset
+	pool.mu.Lock()
+	pool.currentState.SetBalance(account, new(uint256.Int), tracing.BalanceChangeUnspecified)
+	pool.mu.Unlock()
+	<-pool.requestReset(nil, nil)
+
+	want := []txpool.TxEvent{
+		{Hash: tx.Hash(), Type: txpool.TxEventAdded},
+		{Hash: tx.Hash(), Type: txpool.TxEventPromoted},
+		{Hash: tx.Hash(), Type: txpool.TxEventReplaced, ReplacedBy: &replacementHash},
+		{Hash: replacement.Hash(), Type: txpool.TxEventAdded},
+		{Hash: replacement.Hash(), Type: txpool.TxEventDropped, Reason: txpool.TxDropNoFunds},
+	}
+	for i, want := range want {
+		select {
+		case have := <-events:
+			have.Time = time.Time{}
+			if !reflect.DeepEqual(have, want) {
+				t.Errorf("event %d mismatch: have %+v, want %+v", i, have, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not fired", i)
+		}
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// Tests that transactions rejected by the block builder for failed preconditions
+// are withheld from it right away, and dropped from the pool on the next reset
+// along with the demotion of the transactions after them.
+func TestRejectedTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	events := make(chan txpool.TxEvent, 32)
+	sub := pool.SubscribeTxEvents(events)
+	defer sub.Unsubscribe()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000))
+
+	txs := types.Transactions{
+		transaction(0, 100000, key),
+		transaction(1, 100000, key),
+		transaction(2, 100000, key),
+	}
+	var want []txpool.TxEvent
+	for _, tx := range txs {
+		if err := pool.addRemoteSync(tx); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+		want = append(want,
+			txpool.TxEvent{Hash: tx.Hash(), Type: txpool.TxEventAdded},
+			txpool.TxEvent{Hash: tx.Hash(), Type: txpool.TxEventPromoted},
+		)
+	}
+
+	txs[1].SetRejected()
+	if pending := pool.Pending(txpool.PendingFilter{})[account]; len(pending) != 1 || pending[0].Hash != txs[0].Hash() {
+		t.Fatalf("rejected transaction not withheld: have %d pending, want 1", len(pending))
+	}
+	<-pool.requestReset(nil, nil)
+
+	pending, queued := pool.Stats()
+	if pending != 1 {
+		t.Errorf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	if queued != 1 {
+		t.Errorf("queued transactions mismatched: have %d, want %d", queued, 1)
+	}
+	if pool.Has(txs[1].Hash()) {
+		t.Errorf("rejected transaction still in the pool")
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	want = append(want,
+		txpool.TxEvent{Hash: txs[1].Hash(), Type: txpool.TxEventDropped, Reason: txpool.TxDropConditional},
+		txpool.TxEvent{Hash: txs[2].Hash(), Type: txpool.TxEventDemoted},
+	)
+	for i, want := range want {
+		select {
+		case have := <-events:
+			have.Time = time.Time{}
+			if !reflect.DeepEqual(have, want) {
+				t.Errorf("event %d mismatch: have %+v, want %+v", i, have, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not fired", i)
+		}
+	}
+}
+
+// includingBlockChain is a test chain whose blocks include a fixed set of
+// transactions.
+type includingBlockChain struct {
+	*testBlockChain
+	txs types.Transactions
+}
+
+func (bc *includingBlockChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return types.NewBlock(bc.CurrentBlock(), &types.Body{Transactions: bc.txs}, nil, trie.NewStackTrie(nil))
+}
+
+// Tests that the transactions included by a new block are not reported as dropped
+// once removed from the pool, unlike the ones whose nonce was used by others.
+func TestTxEventsIncluded(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &includingBlockChain{testBlockChain: newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))}
+
+	pool := New(testTxPoolConfig, blockchain)
+	pool.Init(testTxPoolConfig.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	included, _ := crypto.GenerateKey()
+	replaced, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(included.PublicKey), big.NewInt(1000000))
+	testAddBalance(pool, crypto.PubkeyToAddress(replaced.PublicKey), big.NewInt(1000000))
+
+	var (
+		tx1 = transaction(0, 100000, included)
+		tx2 = transaction(0, 100000, replaced)
+	)
+	events := make(chan txpool.TxEvent, 32)
+	sub := pool.SubscribeTxEvents(events)
+	defer sub.Unsubscribe()
+
+	if err := pool.addRemoteSync(tx1); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(tx2); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+
+	// Import a block including the first transaction, and another one with the
+	// nonce of the second
+	blockchain.txs = types.Transactions{tx1}
+	testSetNonce(pool, crypto.PubkeyToAddress(included.PublicKey), 1)
+	testSetNonce(pool, crypto.PubkeyToAddress(replaced.PublicKey), 1)
+
+	oldHead := blockchain.CurrentBlock()
+	newHead := &types.Header{Number: big.NewInt(1), ParentHash: oldHead.Hash(), GasLimit: oldHead.GasLimit, BaseFee: big.NewInt(1)}
+	<-pool.requestReset(oldHead, newHead)
+
+	want := []txpool.TxEvent{
+		{Hash: tx1.Hash(), Type: txpool.TxEventAdded},
+		{Hash: tx1.Hash(), Type: txpool.TxEventPromoted},
+		{Hash: tx2.Hash(), Type: txpool.TxEventAdded},
+		{Hash: tx2.Hash(), Type: txpool.TxEventPromoted},
+		{Hash: tx2.Hash(), Type: txpool.TxEventDropped, Reason: txpool.TxDropNonceTooLow},
+	}
+	for i, want := range want {
+		select {
+		case have := <-events:
+			have.Time = time.Time{}
+			if !reflect.DeepEqual(have, want) {
+				t.Errorf("event %d mismatch: have %+v, want %+v", i, have, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not fired", i)
+		}
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if pool.all.Count() != 0 {
+		t.Fatalf("transactions left in the pool: %d", pool.all.Count())
+	}
+}
+
+// Tests that a subscriber not reading the lifecycle events doesn't stall the pool.
+func TestTxEventsSlowSubscriber(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	sub := pool.SubscribeTxEvents(make(chan txpool.TxEvent))
+	defer sub.Unsubscribe()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := uint64(0); i < 16; i++ {
+			pool.addRemoteSync(transaction(i, 100000, key))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("pool stalled by the event subscriber")
+	}
+}
+
+// Tests that the transactions of a sender above its admission rate are rejected,
+// without affecting the other senders.
+func TestSenderRateLimit(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.SenderRate = 0.001 // Don't refill during the test
+	config.SenderBurst = 2
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	keys := make([]*ecdsa.PrivateKey, 2)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+	for i := uint64(0); i < 2; i++ {
+		if err := pool.addRemoteSync(transaction(i, 100000, keys[0])); err != nil {
+			t.Fatalf("failed to add transaction %d within the burst: %v", i, err)
+		}
+	}
+	if err := pool.addRemoteSync(transaction(2, 100000, keys[0])); !errors.Is(err, txpool.ErrSenderRateLimited) {
+		t.Fatalf("transaction above the burst error mismatch: have %v, want %v", err, txpool.ErrSenderRateLimited)
+	}
+	if err := pool.addRemoteSync(transaction(0, 100000, keys[1])); err != nil {
+		t.Fatalf("failed to add transaction of another sender: %v", err)
+	}
+	// Transactions reinjected after a reorg are not rate limited
+	pool.mu.Lock()
+	errs, _ := pool.addTxsLocked([]*types.Transaction{transaction(2, 100000, keys[0])}, false, false)
+	pool.mu.Unlock()
+	if errs[0] != nil {
+		t.Fatalf("failed to reinject transaction above the burst: %v", errs[0])
+	}
+	<-pool.requestPromoteExecutables(newAccountSet(pool.signer, crypto.PubkeyToAddress(keys[0].PublicKey)))
+
+	if pending, _ := pool.Stats(); pending != 4 {
+		t.Fatalf("pending transactions mismatch: have %d, want %d", pending, 4)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that after an L1 fee rise, the pending transactions whose L1 data fee
+// can't be afforded anymore are queued back along with the ones after them, while
+// the accounts whose L1 margin wasn't crossed are left untouched.
+func TestL1CostRepricing(t *testing.T) {
+	t.Parallel()
+
+	config := *params.TestChainConfig
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(types.L1BlockAddr, types.L1BaseFeeSlot, common.BigToHash(big.NewInt(1)))
+	statedb.SetState(types.L1BlockAddr, types.ScalarSlot, common.BigToHash(big.NewInt(1_000_000)))
+	blockchain := newTestBlockChain(&config, 1000000, statedb, new(event.Feed))
+
+	pool := New(testTxPoolConfig, blockchain)
+	pool.Init(testTxPoolConfig.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	events := make(chan txpool.TxEvent, 32)
+	sub := pool.SubscribeTxEvents(events)
+	defer sub.Unsubscribe()
+
+	poor, _ := crypto.GenerateKey()
+	rich, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(poor.PublicKey), big.NewInt(1000000))
+	testAddBalance(pool, crypto.PubkeyToAddress(rich.PublicKey), big.NewInt(1000000000000))
+
+	txs := types.Transactions{
+		transaction(0, 100000, rich),
+		transaction(0, 100000, poor),
+		transaction(1, 100000, poor),
+	}
+	var want []txpool.TxEvent
+	for _, tx := range txs {
+		if err := pool.addRemoteSync(tx); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+		want = append(want,
+			txpool.TxEvent{Hash: tx.Hash(), Type: txpool.TxEventAdded},
+			txpool.TxEvent{Hash: tx.Hash(), Type: txpool.TxEventPromoted},
+		)
+	}
+	setL1BaseFee := func(fee int64) {
+		pool.mu.Lock()
+		statedb.SetState(types.L1BlockAddr, types.L1BaseFeeSlot, common.BigToHash(big.NewInt(fee)))
+		pool.mu.Unlock()
+		<-pool.requestReset(nil, nil)
+	}
+	check := func(pending, queued int) {
+		t.Helper()
+		if have, _ := pool.Stats(); have != pending {
+			t.Fatalf("pending transactions mismatch: have %d, want %d", have, pending)
+		}
+		if _, have := pool.Stats(); have != queued {
+			t.Fatalf("queued transactions mismatch: have %d, want %d", have, queued)
+		}
+		if err := validatePoolInternals(pool); err != nil {
+			t.Fatalf("pool internal state corrupted: %v", err)
+		}
+	}
+	check(3, 0)
+
+	// Raise the L1 base fee so the L1 data fee alone is above the poor balance
+	setL1BaseFee(1000)
+	check(1, 2)
+
+	if !pool.Has(txs[1].Hash()) {
+		t.Errorf("transaction with unaffordable L1 cost dropped from the pool")
+	}
+	pool.mu.RLock()
+	list, epoch := pool.pending[crypto.PubkeyToAddress(rich.PublicKey)], pool.l1Epoch
+	pool.mu.RUnlock()
+	if list.l1Epoch == epoch {
+		t.Errorf("transactions within their L1 margin repriced")
+	}
+	want = append(want,
+		txpool.TxEvent{Hash: txs[1].Hash(), Type: txpool.TxEventDemoted},
+		txpool.TxEvent{Hash: txs[2].Hash(), Type: txpool.TxEventDemoted},
+	)
+	checkEvents := func() {
+		t.Helper()
+		for i, want := range want {
+			select {
+			case have := <-events:
+				have.Time = time.Time{}
+				if !reflect.DeepEqual(have, want) {
+					t.Errorf("event %d mismatch: have %+v, want %+v", i, have, want)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("event %d not fired", i)
+			}
+		}
+		want = want[:0]
+	}
+	checkEvents()
+
+	// Lower it back, the queued transactions are promoted again
+	setL1BaseFee(1)
+	check(3, 0)
+
+	want = append(want,
+		txpool.TxEvent{Hash: txs[1].Hash(), Type: txpool.TxEventPromoted},
+		txpool.TxEvent{Hash: txs[2].Hash(), Type: txpool.TxEventPromoted},
+	)
+	checkEvents()
+}
+
+// Tests that the L1 margins account for the rounding of the L1 costs: an account
+// is only left unchecked after an L1 fee rise if it can afford the rise exactly.
+func TestL1MarginRounding(t *testing.T) {
+	t.Parallel()
+
+	pool := &LegacyPool{
+		l1CostFn:   func(types.RollupCostData) *big.Int { return big.NewInt(1) },
+		l1FeeLevel: uint256.NewInt(10),
+		l1Margins:  newL1Margins(),
+	}
+	key, _ := crypto.GenerateKey()
+	list := newList(true)
+	list.Add(transaction(0, 0, key), 0, nil) // Costs 100 besides its L1 data fee
+	list.l1costs[0] = uint256.NewInt(4)      // Rounded down, so below 5 at level 10
+
+	// The L1 cost may reach 10 at level 19 (below 20 before rounding), and 10.5
+	// at level 20 (below 21), above the balance left after the execution cost.
+	addr := common.Address{0x01}
+	pool.indexL1Margin(addr, list, uint256.NewInt(110))
+
+	if crossed := pool.l1Margins.cross(uint256.NewInt(19)); len(crossed) != 0 {
+		t.Errorf("account crossed below its margin: %v", crossed)
+	}
+	if crossed := pool.l1Margins.cross(uint256.NewInt(20)); len(crossed) != 1 || crossed[0] != addr {
+		t.Errorf("account not crossed at its margin: %v", crossed)
+	}
+}
+
 // Tests that local transactions are journaled to disk, but remote transactions
 // get discarded between restarts.
 func TestJournaling(t *testing.T)         { testJournaling(t, false, false) }
@@ -211,13 +211,19 @@ func (m *sortedMap) Remove(nonce uint64) bool {
 // prevent getting into an invalid state. This is not something that should ever
 // happen but better to be self correcting than failing!
 func (m *sortedMap) Ready(start uint64) types.Transactions {
+	return m.ReadyBelow(start, math.MaxUint64)
+}
+
+// ReadyBelow is like Ready, but only retrieves the transactions with a nonce below
+// the provided limit.
+func (m *sortedMap) ReadyBelow(start, limit uint64) types.Transactions {
 	// Short circuit if no transactions are available
-	if m.index.Len() == 0 || (*m.index)[0] > start {
+	if m.index.Len() == 0 || (*m.index)[0] > start || (*m.index)[0] >= limit {
 		return nil
 	}
 	// Otherwise start accumulating incremental transactions
 	var ready types.Transactions
-	for next := (*m.index)[0]; m.index.Len() > 0 && (*m.index)[0] == next; next++ {
+	for next := (*m.index)[0]; m.index.Len() > 0 && (*m.index)[0] == next && next < limit; next++ {
 		ready = append(ready, m.items[next])
 		delete(m.items, next)
 		heap.Pop(m.index)
@@ -283,9 +289,12 @@ type list struct {
 	strict bool       // Whether nonces are strictly continuous or not
 	txs    *sortedMap // Heap indexed sorted hash map of the transactions
 
-	costcap   *uint256.Int // Price of the highest costing transaction (reset only if exceeds balance)
+	costcap   *uint256.Int // Price of the highest costing transaction, including its L1 cost (reset only if exceeds balance)
 	gascap    uint64       // Gas limit of the highest spending transaction (reset only if exceeds block limit)
-	totalcost *uint256.Int // Total cost of all transactions in the list
+	totalcost *uint256.Int // Total cost of all transactions in the list, including their L1 costs
+
+	l1costs map[uint64]*uint256.Int // L1 data fees of the transactions by nonce, as last priced
+	l1Epoch uint64                  // L1 fee epoch of the pool the L1 data fees were all last priced in
 }
 
 // newList creates a new transaction list for maintaining nonce-indexable fast,
@@ -296,6 +305,7 @@ func newList(strict bool) *list {
 		txs:       newSortedMap(),
 		costcap:   new(uint256.Int),
 		totalcost: new(uint256.Int),
+		l1costs:   make(map[uint64]*uint256.Int),
 	}
 }
 
@@ -341,12 +351,12 @@ func (l *list) Add(tx *types.Transaction, priceBump uint64, l1CostFn txpool.L1Co
 	if overflow {
 		return false, nil
 	}
-	l.totalcost.Add(l.totalcost, cost)
-	if l1CostFn != nil {
-		if l1Cost := l1CostFn(tx.RollupCostData()); l1Cost != nil { // add rollup cost
-			l.totalcost.Add(l.totalcost, cost)
-		}
+	if l1Cost := rollupCost(tx, l1CostFn); l1Cost != nil {
+		l.l1costs[tx.Nonce()] = l1Cost
+		cost = new(uint256.Int).Add(cost, l1Cost)
 	}
+	l.totalcost.Add(l.totalcost, cost)
+
 	// Otherwise overwrite the old transaction with the current one
 	l.txs.Put(tx)
 	if l.costcap.Cmp(cost) < 0 {
@@ -381,13 +391,15 @@ func (l *list) Filter(costLimit *uint256.Int, gasLimit uint64) (types.Transactio
 	if l.costcap.Cmp(costLimit) <= 0 && l.gascap <= gasLimit {
 		return nil, nil
 	}
-	l.costcap = new(uint256.Int).Set(costLimit) // Lower the caps to the thresholds
-	l.gascap = gasLimit
+	l.gascap = gasLimit // Lower the gas cap to the threshold
 
 	// Filter out all the transactions above the account's funds
 	removed := l.txs.Filter(func(tx *types.Transaction) bool {
 		return tx.Gas() > gasLimit || tx.Cost().Cmp(costLimit.ToBig()) > 0
 	})
+	// Lower the cost cap to the highest remaining cost. The L1 costs are left to
+	// FirstUnaffordable, so the remaining costs may still be above the threshold.
+	l.resetCostcap()
 
 	if len(removed) == 0 {
 		return nil, nil
@@ -410,6 +422,82 @@ func (l *list) Filter(costLimit *uint256.Int, gasLimit uint64) (types.Transactio
 	return removed, invalids
 }
 
+// FilterRejected removes all transactions from the list rejected by the block
+// builder, returning them along with the transactions invalidated by their
+// removal if the list is strict.
+func (l *list) FilterRejected() (types.Transactions, types.Transactions) {
+	removed := l.txs.Filter(func(tx *types.Transaction) bool {
+		return tx.Rejected()
+	})
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	l.resetCostcap()
+
+	var invalids types.Transactions
+	// If the list was strict, filter anything above the lowest nonce
+	if l.strict {
+		lowest := uint64(math.MaxUint64)
+		for _, tx := range removed {
+			if nonce := tx.Nonce(); lowest > nonce {
+				lowest = nonce
+			}
+		}
+		invalids = l.txs.filter(func(tx *types.Transaction) bool { return tx.Nonce() > lowest })
+	}
+	l.subTotalCost(removed)
+	l.subTotalCost(invalids)
+	l.txs.reheap()
+	return removed, invalids
+}
+
+// FirstUnaffordable returns the lowest nonce of the transactions in the list with
+// a cost including their L1 data fee above the provided balance, if any.
+//
+// This method uses the cached costcap to quickly decide if there's even a point
+// in checking all the costs, lowering the cap to the highest cost if all of them
+// turn out to be affordable.
+func (l *list) FirstUnaffordable(balance *uint256.Int) (uint64, bool) {
+	if l.costcap.Cmp(balance) <= 0 {
+		return 0, false
+	}
+	var (
+		lowest = uint64(math.MaxUint64)
+		found  bool
+	)
+	for _, tx := range l.txs.items {
+		if nonce := tx.Nonce(); nonce < lowest && l.cost(tx).Cmp(balance) > 0 {
+			lowest, found = nonce, true
+		}
+	}
+	if !found {
+		l.resetCostcap()
+	}
+	return lowest, found
+}
+
+// Reprice recalculates the L1 data fees of all the transactions in the list with
+// the provided L1 cost function, updating the total cost and cost cap with them.
+func (l *list) Reprice(l1CostFn txpool.L1CostFunc) {
+	l.l1costs = make(map[uint64]*uint256.Int)
+	l.totalcost = new(uint256.Int)
+	for nonce, tx := range l.txs.items {
+		if l1Cost := rollupCost(tx, l1CostFn); l1Cost != nil {
+			l.l1costs[nonce] = l1Cost
+		}
+		l.totalcost.Add(l.totalcost, l.cost(tx))
+	}
+	l.resetCostcap()
+}
+
+// Truncate removes all transactions from the list with a nonce not lower than the
+// provided threshold, returning them.
+func (l *list) Truncate(threshold uint64) types.Transactions {
+	txs := l.txs.Filter(func(tx *types.Transaction) bool { return tx.Nonce() >= threshold })
+	l.subTotalCost(txs)
+	return txs
+}
+
 // Cap places a hard limit on the number of items, returning all transactions
 // exceeding that limit.
 func (l *list) Cap(threshold int) types.Transactions {
@@ -450,6 +538,14 @@ func (l *list) Ready(start uint64) types.Transactions {
 	return txs
 }
 
+// ReadyBelow is like Ready, but only retrieves the transactions with a nonce below
+// the provided limit.
+func (l *list) ReadyBelow(start, limit uint64) types.Transactions {
+	txs := l.txs.ReadyBelow(start, limit)
+	l.subTotalCost(txs)
+	return txs
+}
+
 // Len returns the length of the transaction list.
 func (l *list) Len() int {
 	return l.txs.Len()
@@ -477,11 +573,44 @@ func (l *list) LastElement() *types.Transaction {
 // total cost of all transactions.
 func (l *list) subTotalCost(txs []*types.Transaction) {
 	for _, tx := range txs {
-		_, underflow := l.totalcost.SubOverflow(l.totalcost, uint256.MustFromBig(tx.Cost()))
+		_, underflow := l.totalcost.SubOverflow(l.totalcost, l.cost(tx))
 		if underflow {
 			panic("totalcost underflow")
 		}
+		delete(l.l1costs, tx.Nonce())
+	}
+}
+
+// cost returns the cost of a transaction of the list, including its L1 data fee.
+func (l *list) cost(tx *types.Transaction) *uint256.Int {
+	cost := uint256.MustFromBig(tx.Cost())
+	if l1Cost := l.l1costs[tx.Nonce()]; l1Cost != nil {
+		cost.Add(cost, l1Cost)
+	}
+	return cost
+}
+
+// resetCostcap sets the cost cap to the highest cost of the transactions in the
+// list, including their L1 data fees.
+func (l *list) resetCostcap() {
+	l.costcap = new(uint256.Int)
+	for _, tx := range l.txs.items {
+		if cost := l.cost(tx); l.costcap.Cmp(cost) < 0 {
+			l.costcap = cost
+		}
+	}
+}
+
+// rollupCost returns the L1 data fee of a transaction, or nil if there's none.
+func rollupCost(tx *types.Transaction, l1CostFn txpool.L1CostFunc) *uint256.Int {
+	if l1CostFn == nil {
+		return nil
+	}
+	l1Cost := l1CostFn(tx.RollupCostData())
+	if l1Cost == nil || l1Cost.Sign() == 0 {
+		return nil
 	}
+	return uint256.MustFromBig(l1Cost)
 }
 
 // priceHeap is a heap.Interface implementation over transactions for retrieving
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/holiman/uint256"
@@ -68,6 +69,44 @@ func TestListAddVeryExpensive(t *testing.T) {
 	}
 }
 
+// Tests that the total cost and the affordability checks of a list include the
+// L1 data fees of the transactions, and follow them when repriced.
+func TestListL1Costs(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+
+	txs := make(types.Transactions, 3)
+	for i := 0; i < len(txs); i++ {
+		txs[i] = transaction(uint64(i), 1000, key)
+	}
+	l1Cost := func(fee int64) txpool.L1CostFunc {
+		return func(types.RollupCostData) *big.Int { return big.NewInt(fee) }
+	}
+	list := newList(true)
+	for _, tx := range txs {
+		list.Add(tx, DefaultConfig.PriceBump, l1Cost(100))
+	}
+	// Every transaction costs 1000 gas at 1 wei, a value of 100 wei and the L1 fee
+	if have, want := list.totalcost.Uint64(), uint64(3*(1100+100)); have != want {
+		t.Fatalf("total cost mismatch: have %d, want %d", have, want)
+	}
+	if _, ok := list.FirstUnaffordable(uint256.NewInt(1200)); ok {
+		t.Fatalf("affordable transaction reported unaffordable")
+	}
+	list.Reprice(l1Cost(200))
+	if have, want := list.totalcost.Uint64(), uint64(3*(1100+200)); have != want {
+		t.Fatalf("repriced total cost mismatch: have %d, want %d", have, want)
+	}
+	if nonce, ok := list.FirstUnaffordable(uint256.NewInt(1200)); !ok || nonce != 0 {
+		t.Fatalf("first unaffordable transaction mismatch: have %d (%v), want %d", nonce, ok, 0)
+	}
+	if postponed := list.Truncate(1); len(postponed) != 2 {
+		t.Fatalf("truncated transaction count mismatch: have %d, want %d", len(postponed), 2)
+	}
+	if have, want := list.totalcost.Uint64(), uint64(1100+200); have != want {
+		t.Fatalf("truncated total cost mismatch: have %d, want %d", have, want)
+	}
+}
+
 func BenchmarkListAdd(b *testing.B) {
 	// Generate a list of transactions to insert
 	key, _ := crypto.GenerateKey()
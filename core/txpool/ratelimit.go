@@ -0,0 +1,95 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"golang.org/x/time/rate"
+)
+
+// rateLimiterKeys is the number of keys a rate limiter tracks the buckets of.
+// Above it, the least recently seen keys are forgotten, restarting with a full
+// bucket when seen again.
+const rateLimiterKeys = 65536
+
+// RateLimiter throttles the admission of transactions per key, such as their
+// sender or the RPC client submitting them, with a token bucket for each key.
+// A nil limiter admits everything.
+type RateLimiter[K comparable] struct {
+	limit   rate.Limit
+	burst   int
+	buckets lru.BasicLRU[K, *rate.Limiter]
+	lock    sync.Mutex
+}
+
+// NewRateLimiter creates a rate limiter admitting the given number of transactions
+// per second and key, in bursts of up to the given size. It returns nil if the
+// rate is not positive, disabling the limits.
+func NewRateLimiter[K comparable](perSecond float64, burst int) *RateLimiter[K] {
+	if perSecond <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter[K]{
+		limit:   rate.Limit(perSecond),
+		burst:   burst,
+		buckets: lru.NewBasicLRU[K, *rate.Limiter](rateLimiterKeys),
+	}
+}
+
+// Allow takes a token from the bucket of the given key, reporting whether there
+// was one available.
+func (l *RateLimiter[K]) Allow(key K) bool {
+	return l.allowAt(key, time.Now())
+}
+
+func (l *RateLimiter[K]) allowAt(key K, now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	bucket, ok := l.buckets.Get(key)
+	if !ok {
+		bucket = rate.NewLimiter(l.limit, l.burst)
+		l.buckets.Add(key, bucket)
+	}
+	return bucket.AllowN(now, 1)
+}
+
+// originKey is the context key of the origin of submitted transactions.
+type originKey struct{}
+
+// WithOrigin returns a copy of the context carrying the origin of the submitted
+// transactions, e.g. the address of the RPC client, to rate limit them by.
+func WithOrigin(ctx context.Context, origin string) context.Context {
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+// OriginFromContext returns the origin of the submitted transactions carried by
+// the context, or an empty string if unknown.
+func OriginFromContext(ctx context.Context) string {
+	origin, _ := ctx.Value(originKey{}).(string)
+	return origin
+}
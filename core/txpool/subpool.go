@@ -123,6 +123,11 @@ type SubPool interface {
 	// Get returns a transaction if it is contained in the pool, or nil otherwise.
 	Get(hash common.Hash) *types.Transaction
 
+	// ValidateTxBasics checks whether a transaction passes the cheap validation
+	// of the subpool: the consensus rules, the pool filters and the nonce and
+	// balance of its sender. It doesn't add the transaction to the pool.
+	ValidateTxBasics(tx *types.Transaction, local bool) error
+
 	// Add enqueues a batch of transactions into the pool if they are valid. Due
 	// to the large transaction churn, add may postpone fully integrating the tx
 	// to a later point to batch multiple ones together.
@@ -140,6 +145,10 @@ type SubPool interface {
 	// or also for reorged out ones.
 	SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool) event.Subscription
 
+	// SubscribeTxEvents subscribes to the lifecycle events of the transactions
+	// in the pool: additions, promotions, replacements and drops with a reason.
+	SubscribeTxEvents(ch chan<- TxEvent) event.Subscription
+
 	// Nonce returns the next nonce of an account, with all transactions executable
 	// by the pool already applied on top.
 	Nonce(addr common.Address) uint64
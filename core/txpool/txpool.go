@@ -17,12 +17,18 @@
 package txpool
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
@@ -42,6 +48,34 @@ const (
 	TxStatusIncluded
 )
 
+const (
+	// interopCacheLimit is the number of transactions to cache the results of the
+	// cross-chain message checks of, to not simulate resent transactions again.
+	interopCacheLimit = 4096
+
+	// interopCheckTimeout is the time budget of the cross-chain message checks of
+	// a batch of added transactions. The transactions not checked in time are
+	// rejected, to not let a slow supervisor throttle the admission of the pool.
+	interopCheckTimeout = 250 * time.Millisecond
+
+	// interopCheckConcurrency is the maximum number of cross-chain message checks
+	// running concurrently for a batch of added transactions.
+	interopCheckConcurrency = 16
+
+	// txHistoryLimit is the number of transactions to keep the lifecycle events
+	// of, to report why a transaction left the pool after the fact.
+	txHistoryLimit = 65536
+
+	// txHistoryEvents is the number of most recent lifecycle events to keep per
+	// transaction.
+	txHistoryEvents = 16
+
+	// txHistoryDepth is the maximum number of blocks to report the included
+	// transactions of on a single head event, when several blocks are imported
+	// at once.
+	txHistoryDepth = 64
+)
+
 var (
 	// reservationsGaugeName is the prefix of a per-subpool address reservation
 	// metric.
@@ -49,6 +83,10 @@ var (
 	// This is mostly a sanity metric to ensure there's no bug that would make
 	// some subpool hog all the reservations due to mis-accounting.
 	reservationsGaugeName = "txpool/reservations"
+
+	// originLimitedMeter counts the transactions rejected due to the rate limit
+	// of their submitting RPC client.
+	originLimitedMeter = metrics.NewRegisteredMeter("txpool/origin/ratelimited", nil)
 )
 
 // BlockChain defines the minimal set of methods needed to back a tx pool with
@@ -57,6 +95,9 @@ type BlockChain interface {
 	// CurrentBlock returns the current head of the chain.
 	CurrentBlock() *types.Header
 
+	// GetBlock retrieves a specific block, used to report the included transactions.
+	GetBlock(hash common.Hash, number uint64) *types.Block
+
 	// SubscribeChainHeadEvent subscribes to new blocks being added to the chain.
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
 }
@@ -67,11 +108,22 @@ type BlockChain interface {
 // They exit the pool when they are included in the blockchain or evicted due to
 // resource constraints.
 type TxPool struct {
-	subpools []SubPool // List of subpools for specialized transaction handling
+	subpools []SubPool  // List of subpools for specialized transaction handling
+	chain    BlockChain // Chain to retrieve the current head from
+
+	interop       atomic.Pointer[InteropValidationOptions] // Optional cross-chain message validation (Optimism interop)
+	interopChecks *lru.Cache[common.Hash, interopCheck]    // Recent results of the cross-chain message checks
+
+	originLimiter atomic.Pointer[RateLimiter[string]] // Optional admission rate limit per origin of submitted transactions
 
 	reservations map[common.Address]SubPool // Map with the account to pool reservations
 	reserveLock  sync.Mutex                 // Lock protecting the account reservations
 
+	txEvents    TxEventFeed                        // Feed of the inclusion events of pooled transactions
+	history     *lru.Cache[common.Hash, []TxEvent] // Recent lifecycle events of pooled transactions
+	historyQuit chan struct{}                      // Quit channel to tear down the history tracker
+	historyTerm chan struct{}                      // Termination channel of the history tracker
+
 	subs event.SubscriptionScope // Subscription scope to unsubscribe all on shutdown
 	quit chan chan error         // Quit channel to tear down the head updater
 	term chan struct{}           // Termination channel to detect a closed pool
@@ -88,11 +140,16 @@ func New(gasTip uint64, chain BlockChain, subpools []SubPool) (*TxPool, error) {
 	head := chain.CurrentBlock()
 
 	pool := &TxPool{
-		subpools:     subpools,
-		reservations: make(map[common.Address]SubPool),
-		quit:         make(chan chan error),
-		term:         make(chan struct{}),
-		sync:         make(chan chan error),
+		subpools:      subpools,
+		chain:         chain,
+		reservations:  make(map[common.Address]SubPool),
+		interopChecks: lru.NewCache[common.Hash, interopCheck](interopCacheLimit),
+		history:       lru.NewCache[common.Hash, []TxEvent](txHistoryLimit),
+		historyQuit:   make(chan struct{}),
+		historyTerm:   make(chan struct{}),
+		quit:          make(chan chan error),
+		term:          make(chan struct{}),
+		sync:          make(chan chan error),
 	}
 	for i, subpool := range subpools {
 		if err := subpool.Init(gasTip, head, pool.reserver(i, subpool)); err != nil {
@@ -103,6 +160,20 @@ func New(gasTip uint64, chain BlockChain, subpools []SubPool) (*TxPool, error) {
 		}
 	}
 	go pool.loop(head, chain)
+
+	// Subscribe to the lifecycle events before returning, to not miss the events
+	// of the first transactions added
+	var (
+		eventCh = make(chan TxEvent, 256)
+		headCh  = make(chan core.ChainHeadEvent, 10)
+		subs    = make([]event.Subscription, 0, len(subpools)+1)
+	)
+	for _, subpool := range subpools {
+		subs = append(subs, subpool.SubscribeTxEvents(eventCh))
+	}
+	subs = append(subs, chain.SubscribeChainHeadEvent(headCh))
+	go pool.trackHistory(event.JoinSubscriptions(subs...), eventCh, headCh)
+
 	return pool, nil
 }
 
@@ -171,6 +242,11 @@ func (p *TxPool) Close() error {
 	// Unsubscribe anyone still listening for tx events
 	p.subs.Close()
 
+	// Terminate the history tracker and the delivery of its events
+	close(p.historyQuit)
+	<-p.historyTerm
+	p.txEvents.Close()
+
 	if len(errs) > 0 {
 		return fmt.Errorf("subpool close errors: %v", errs)
 	}
@@ -278,6 +354,146 @@ func (p *TxPool) loop(head *types.Header, chain BlockChain) {
 	errc <- nil
 }
 
+// trackHistory records the lifecycle events of the transactions published by the
+// subpools, and reports the inclusion of the recorded transactions in new blocks.
+func (p *TxPool) trackHistory(sub event.Subscription, eventCh <-chan TxEvent, headCh <-chan core.ChainHeadEvent) {
+	defer close(p.historyTerm)
+	defer sub.Unsubscribe()
+
+	reported := lru.NewBasicLRU[common.Hash, struct{}](2 * txHistoryDepth)
+	if head := p.chain.CurrentBlock(); head != nil {
+		reported.Add(head.Hash(), struct{}{})
+	}
+
+	for {
+		select {
+		case ev := <-eventCh:
+			p.recordHistory(ev)
+
+		case head := <-headCh:
+			// Gather the blocks not reported yet, as head events are not fired
+			// for every block of a batch import
+			var blocks []*types.Block
+			for block := head.Block; block != nil && len(blocks) < txHistoryDepth; {
+				if reported.Contains(block.Hash()) {
+					break
+				}
+				blocks = append(blocks, block)
+				if block.NumberU64() == 0 {
+					break
+				}
+				block = p.chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+			}
+			now := time.Now()
+			for i := len(blocks) - 1; i >= 0; i-- {
+				var (
+					number = hexutil.Uint64(blocks[i].NumberU64())
+					hash   = blocks[i].Hash()
+				)
+				reported.Add(hash, struct{}{})
+				for _, tx := range blocks[i].Transactions() {
+					if !p.history.Contains(tx.Hash()) {
+						continue
+					}
+					p.recordHistory(TxEvent{Hash: tx.Hash(), Type: TxEventIncluded, Time: now, BlockNumber: &number, BlockHash: &hash})
+					p.txEvents.RecordInclusion(tx.Hash(), uint64(number), hash)
+				}
+			}
+			p.txEvents.Flush()
+
+		case <-sub.Err():
+			return
+
+		case <-p.historyQuit:
+			return
+		}
+	}
+}
+
+// recordHistory appends a lifecycle event to the history of its transaction.
+func (p *TxPool) recordHistory(ev TxEvent) {
+	events, _ := p.history.Get(ev.Hash)
+	if len(events) >= txHistoryEvents {
+		events = events[len(events)-txHistoryEvents+1:]
+	}
+	p.history.Add(ev.Hash, append(events, ev))
+}
+
+// SetInteropValidation enables the validation of cross-chain executing messages
+// emitted by inbound transactions once the interop fork is active.
+func (p *TxPool) SetInteropValidation(opts *InteropValidationOptions) {
+	p.interop.Store(opts)
+}
+
+// interopCheck is the result of the cross-chain message checks of a transaction.
+type interopCheck struct {
+	head common.Hash // Chain head the transaction was simulated on
+	err  error       // Error of the checks, nil if the messages are valid
+}
+
+// validateInterops checks the cross-chain executing messages of the transactions
+// at the given indices concurrently, within the time budget of the batch, and
+// stores the errors at the same indices.
+func (p *TxPool) validateInterops(txs []*types.Transaction, indices []int, head *types.Header, opts *InteropValidationOptions, errs []error) {
+	ctx, cancel := context.WithTimeout(context.Background(), interopCheckTimeout)
+	defer cancel()
+
+	var (
+		wg    sync.WaitGroup
+		slots = make(chan struct{}, interopCheckConcurrency)
+	)
+	for _, i := range indices {
+		slots <- struct{}{}
+		if err := ctx.Err(); err != nil {
+			<-slots
+			errs[i] = fmt.Errorf("%w: %v", ErrInvalidExecutingMessage, err)
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			errs[i] = p.validateInterop(ctx, txs[i], head, opts)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// validateInterop checks the cross-chain executing messages of a transaction on
+// top of the given head, reusing the result of an earlier check on the same head.
+// Checks cut short by the context are not reused.
+func (p *TxPool) validateInterop(ctx context.Context, tx *types.Transaction, head *types.Header, opts *InteropValidationOptions) error {
+	hash, headHash := tx.Hash(), head.Hash()
+	if check, ok := p.interopChecks.Get(hash); ok && check.head == headHash {
+		return check.err
+	}
+	err := ValidateTransactionInterop(ctx, tx, head, opts)
+	if ctx.Err() == nil {
+		p.interopChecks.Add(hash, interopCheck{head: headHash, err: err})
+	}
+	return err
+}
+
+// SetOriginRateLimit limits the admission of transactions submitted by each
+// origin, e.g. RPC client, to the given rate per second, in bursts of up to the
+// given size.
+func (p *TxPool) SetOriginRateLimit(perSecond float64, burst int) {
+	p.originLimiter.Store(NewRateLimiter[string](perSecond, burst))
+}
+
+// CheckOrigin takes an admission token for a transaction submitted by the given
+// origin, returning ErrOriginRateLimited if its rate limit is exceeded. The
+// transactions of an unknown origin are not limited.
+func (p *TxPool) CheckOrigin(origin string) error {
+	if origin == "" || p.originLimiter.Load().Allow(origin) {
+		return nil
+	}
+	originLimitedMeter.Mark(1)
+	return ErrOriginRateLimited
+}
+
 // SetGasTip updates the minimum gas tip required by the transaction pool for a
 // new transaction, and drops all transactions below this threshold.
 func (p *TxPool) SetGasTip(tip *big.Int) {
@@ -310,6 +526,11 @@ func (p *TxPool) Get(hash common.Hash) *types.Transaction {
 // Add enqueues a batch of transactions into the pool if they are valid. Due
 // to the large transaction churn, add may postpone fully integrating the tx
 // to a later point to batch multiple ones together.
+//
+// Once interop is active, the cross-chain executing messages of transactions
+// declaring the CrossL2Inbox in their access list are checked before they reach
+// the subpools. Transactions emitting executing messages without declaring the
+// inbox are not simulated here, and only caught by the block building checks.
 func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 	// Split the input transactions between the subpools. It shouldn't really
 	// happen that we receive merged batches, but better graceful than strange
@@ -320,18 +541,53 @@ func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 	txsets := make([][]*types.Transaction, len(p.subpools))
 	splits := make([]int, len(txs))
 
+	var (
+		interop     = p.interop.Load()
+		interopErrs []error
+		head        *types.Header
+	)
+	if interop != nil {
+		head = p.chain.CurrentBlock()
+		if !interop.Config.IsOptimismInterop(head.Time) {
+			interop = nil
+		} else {
+			interopErrs = make([]error, len(txs))
+		}
+	}
+	var checks []int // Transactions to check the cross-chain messages of
 	for i, tx := range txs {
 		// Mark this transaction belonging to no-subpool
 		splits[i] = -1
 
 		// Try to find a subpool that accepts the transaction
 		for j, subpool := range p.subpools {
-			if subpool.Filter(tx) {
-				txsets[j] = append(txsets[j], tx)
-				splits[i] = j
-				break
+			if !subpool.Filter(tx) {
+				continue
+			}
+			splits[i] = j
+
+			// The cross-chain messages are only simulated for transactions
+			// declaring the CrossL2Inbox, once they pass the cheap checks.
+			if interop != nil && accessesCrossL2Inbox(tx) {
+				if err := subpool.ValidateTxBasics(tx, local); err != nil {
+					interopErrs[i] = err
+				} else {
+					checks = append(checks, i)
+				}
 			}
+			break
+		}
+	}
+	// Reject transactions with invalid cross-chain messages before they reach
+	// the subpools.
+	if len(checks) > 0 {
+		p.validateInterops(txs, checks, head, interop, interopErrs)
+	}
+	for i, tx := range txs {
+		if splits[i] == -1 || (interopErrs != nil && interopErrs[i] != nil) {
+			continue
 		}
+		txsets[splits[i]] = append(txsets[splits[i]], tx)
 	}
 	// Add the transactions split apart to the individual subpools and piece
 	// back the errors into the original sort order.
@@ -341,6 +597,11 @@ func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 	}
 	errs := make([]error, len(txs))
 	for i, split := range splits {
+		// If the transaction failed the cross-chain message checks, report it
+		if interopErrs != nil && interopErrs[i] != nil {
+			errs[i] = interopErrs[i]
+			continue
+		}
 		// If the transaction was rejected by all subpools, mark it unsupported
 		if split == -1 {
 			errs[i] = core.ErrTxTypeNotSupported
@@ -378,6 +639,25 @@ func (p *TxPool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool)
 	return p.subs.Track(event.JoinSubscriptions(subs...))
 }
 
+// SubscribeTxEvents registers a subscription for the lifecycle events of the
+// pooled transactions, from their addition to their inclusion in a block or
+// their removal from the pool.
+func (p *TxPool) SubscribeTxEvents(ch chan<- TxEvent) event.Subscription {
+	subs := make([]event.Subscription, 0, len(p.subpools)+1)
+	for _, subpool := range p.subpools {
+		subs = append(subs, subpool.SubscribeTxEvents(ch))
+	}
+	subs = append(subs, p.txEvents.Subscribe(ch))
+	return p.subs.Track(event.JoinSubscriptions(subs...))
+}
+
+// TxHistory returns the recent lifecycle events of a transaction, oldest first,
+// or nil if the pool has no record of it.
+func (p *TxPool) TxHistory(hash common.Hash) []TxEvent {
+	events, _ := p.history.Get(hash)
+	return slices.Clone(events)
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 func (p *TxPool) Nonce(addr common.Address) uint64 {
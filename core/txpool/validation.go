@@ -17,6 +17,7 @@
 package txpool
 
 import (
+	"context"
 	"crypto/sha256"
 	"errors"
 	"fmt"
@@ -26,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/types/interoptypes"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -81,7 +83,7 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	if tx.Type() == types.DepositTxType {
 		return core.ErrTxTypeNotSupported
 	}
-	if opts.Config.IsOptimism() && tx.Type() == types.BlobTxType {
+	if !opts.Config.HasBlobTxs() && tx.Type() == types.BlobTxType {
 		return core.ErrTxTypeNotSupported
 	}
 	// Ensure transactions not implemented by the calling pool are rejected
@@ -286,3 +288,63 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 	}
 	return nil
 }
+
+// InteropValidationOptions define the checks of the cross-chain executing messages
+// emitted by a transaction, once the interop fork is active.
+type InteropValidationOptions struct {
+	Config *params.ChainConfig // Chain configuration to check whether interop is active
+
+	// SimLogs is a mandatory callback to retrieve the logs emitted by a transaction
+	// when executed on top of the current chain head.
+	SimLogs func(tx *types.Transaction) ([]*types.Log, error)
+
+	// Supervisor checks the executing messages against the other chains. If it
+	// is not set, all transactions emitting executing messages are rejected.
+	Supervisor interoptypes.Supervisor
+
+	// MinSafety is the minimum safety level of the initiating messages.
+	MinSafety interoptypes.SafetyLevel
+}
+
+// accessesCrossL2Inbox returns whether the access list of a transaction declares
+// the CrossL2Inbox predeploy, as needed to emit executing messages.
+func accessesCrossL2Inbox(tx *types.Transaction) bool {
+	for _, tuple := range tx.AccessList() {
+		if tuple.Address == params.InteropCrossL2InboxAddress {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransactionInterop is a helper method to check whether the executing
+// messages emitted by a transaction through the CrossL2Inbox predeploy are valid.
+// Transactions not emitting any executing messages are always accepted.
+//
+// The check simulates the transaction, so callers should only run it for the
+// transactions passing the cheaper validation and declaring the CrossL2Inbox in
+// their access list. Messages emitted without declaring it are still caught by
+// the block building checks.
+func ValidateTransactionInterop(ctx context.Context, tx *types.Transaction, head *types.Header, opts *InteropValidationOptions) error {
+	if !opts.Config.IsOptimismInterop(head.Time) {
+		return nil
+	}
+	logs, err := opts.SimLogs(tx)
+	if err != nil {
+		return fmt.Errorf("%w: failed to simulate transaction: %v", ErrInvalidExecutingMessage, err)
+	}
+	msgs, err := interoptypes.ExecutingMessagesFromLogs(logs)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidExecutingMessage, err)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	if opts.Supervisor == nil {
+		return fmt.Errorf("%w: no interop supervisor configured", ErrInvalidExecutingMessage)
+	}
+	if err := opts.Supervisor.CheckMessages(ctx, msgs, opts.MinSafety); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidExecutingMessage, err)
+	}
+	return nil
+}
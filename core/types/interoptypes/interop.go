@@ -0,0 +1,226 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package interoptypes contains the types of the OP-stack interop protocol:
+// executing messages emitted by the CrossL2Inbox predeploy, and the interface
+// of the supervisor that checks them.
+package interoptypes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// ExecutingMessageEventTopic is the topic of the ExecutingMessage event emitted
+// by the CrossL2Inbox predeploy.
+var ExecutingMessageEventTopic = crypto.Keccak256Hash([]byte("ExecutingMessage(bytes32,(address,uint256,uint256,uint256,uint256))"))
+
+// CheckMessagesTimeout is the maximum time to wait for the supervisor to check
+// the executing messages of a single transaction.
+const CheckMessagesTimeout = 2 * time.Second
+
+// executingMessageDataLength is the length of the ABI encoded identifier in the
+// data of an ExecutingMessage event.
+const executingMessageDataLength = 5 * 32
+
+// Supervisor checks executing messages against the state of the other chains
+// in the dependency set.
+type Supervisor interface {
+	// CheckMessages returns an error if any of the messages is invalid, or not
+	// yet known at the given minimum safety level.
+	CheckMessages(ctx context.Context, messages []Message, minSafety SafetyLevel) error
+}
+
+// Message is an executing message: a reference to an initiating message on
+// another chain, together with the hash of its payload.
+type Message struct {
+	Identifier  Identifier  `json:"identifier"`
+	PayloadHash common.Hash `json:"payloadHash"`
+}
+
+// Identifier uniquely identifies an initiating message log.
+type Identifier struct {
+	Origin      common.Address
+	BlockNumber uint64
+	LogIndex    uint32
+	Timestamp   uint64
+	ChainID     uint256.Int // flat, not a pointer, so the identifier can be used as a map key
+}
+
+type identifierMarshaling struct {
+	Origin      common.Address `json:"origin"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	LogIndex    hexutil.Uint64 `json:"logIndex"`
+	Timestamp   hexutil.Uint64 `json:"timestamp"`
+	ChainID     hexutil.U256   `json:"chainID"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (id Identifier) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&identifierMarshaling{
+		Origin:      id.Origin,
+		BlockNumber: hexutil.Uint64(id.BlockNumber),
+		LogIndex:    hexutil.Uint64(id.LogIndex),
+		Timestamp:   hexutil.Uint64(id.Timestamp),
+		ChainID:     hexutil.U256(id.ChainID),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (id *Identifier) UnmarshalJSON(input []byte) error {
+	var dec identifierMarshaling
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.LogIndex > math.MaxUint32 {
+		return fmt.Errorf("log index too large: %d", uint64(dec.LogIndex))
+	}
+	id.Origin = dec.Origin
+	id.BlockNumber = uint64(dec.BlockNumber)
+	id.LogIndex = uint32(dec.LogIndex)
+	id.Timestamp = uint64(dec.Timestamp)
+	id.ChainID = (uint256.Int)(dec.ChainID)
+	return nil
+}
+
+// SafetyLevel is the level of safety at which the initiating message of an
+// executing message is known to the supervisor.
+type SafetyLevel string
+
+const (
+	Finalized   SafetyLevel = "finalized"
+	Safe        SafetyLevel = "safe"
+	LocalSafe   SafetyLevel = "local-safe"
+	CrossUnsafe SafetyLevel = "cross-unsafe"
+	Unsafe      SafetyLevel = "unsafe"
+	Invalid     SafetyLevel = "invalid"
+)
+
+// safetyRank orders the safety levels from weakest to strongest.
+var safetyRank = map[SafetyLevel]int{
+	Invalid:     0,
+	Unsafe:      1,
+	CrossUnsafe: 2,
+	LocalSafe:   3,
+	Safe:        4,
+	Finalized:   5,
+}
+
+// String implements fmt.Stringer.
+func (lvl SafetyLevel) String() string {
+	return string(lvl)
+}
+
+// Validate returns an error if the safety level is not known.
+func (lvl SafetyLevel) Validate() error {
+	if _, ok := safetyRank[lvl]; !ok {
+		return fmt.Errorf("unknown safety level %q", string(lvl))
+	}
+	return nil
+}
+
+// AtLeast returns whether the safety level is at least as strong as the given
+// minimum. Unknown levels never satisfy any minimum.
+func (lvl SafetyLevel) AtLeast(min SafetyLevel) bool {
+	rank, ok := safetyRank[lvl]
+	if !ok {
+		return false
+	}
+	return rank >= safetyRank[min]
+}
+
+var (
+	// ErrNotExecutingMessage is returned if a log is not an ExecutingMessage
+	// event emitted by the CrossL2Inbox predeploy.
+	ErrNotExecutingMessage = errors.New("not an executing message")
+
+	// ErrMalformedExecutingMessage is returned if an ExecutingMessage event
+	// can't be decoded.
+	ErrMalformedExecutingMessage = errors.New("malformed executing message")
+)
+
+// ExecutingMessageFromLog decodes the executing message of an ExecutingMessage
+// event emitted by the CrossL2Inbox predeploy.
+func ExecutingMessageFromLog(log *types.Log) (Message, error) {
+	if log.Address != params.InteropCrossL2InboxAddress || len(log.Topics) == 0 || log.Topics[0] != ExecutingMessageEventTopic {
+		return Message{}, ErrNotExecutingMessage
+	}
+	if len(log.Topics) != 2 {
+		return Message{}, fmt.Errorf("%w: expected 2 topics, got %d", ErrMalformedExecutingMessage, len(log.Topics))
+	}
+	if len(log.Data) != executingMessageDataLength {
+		return Message{}, fmt.Errorf("%w: expected %d bytes of data, got %d", ErrMalformedExecutingMessage, executingMessageDataLength, len(log.Data))
+	}
+	var (
+		id   Identifier
+		word = func(i int) []byte { return log.Data[i*32 : (i+1)*32] }
+	)
+	if !isZero(word(0)[:12]) {
+		return Message{}, fmt.Errorf("%w: origin is not an address", ErrMalformedExecutingMessage)
+	}
+	id.Origin = common.BytesToAddress(word(0)[12:])
+
+	var blockNumber, logIndex, timestamp uint256.Int
+	blockNumber.SetBytes32(word(1))
+	logIndex.SetBytes32(word(2))
+	timestamp.SetBytes32(word(3))
+	if !blockNumber.IsUint64() || !timestamp.IsUint64() || !logIndex.IsUint64() || logIndex.Uint64() > math.MaxUint32 {
+		return Message{}, fmt.Errorf("%w: identifier field out of range", ErrMalformedExecutingMessage)
+	}
+	id.BlockNumber = blockNumber.Uint64()
+	id.LogIndex = uint32(logIndex.Uint64())
+	id.Timestamp = timestamp.Uint64()
+	id.ChainID.SetBytes32(word(4))
+
+	return Message{Identifier: id, PayloadHash: log.Topics[1]}, nil
+}
+
+// ExecutingMessagesFromLogs collects the executing messages of the given logs,
+// skipping any log that is not an ExecutingMessage event.
+func ExecutingMessagesFromLogs(logs []*types.Log) ([]Message, error) {
+	var msgs []Message
+	for _, log := range logs {
+		msg, err := ExecutingMessageFromLog(log)
+		if errors.Is(err, ErrNotExecutingMessage) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func isZero(b []byte) bool {
+	for _, x := range b {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package interoptypes
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+func executingMessageLog(id Identifier, payloadHash common.Hash) *types.Log {
+	data := make([]byte, executingMessageDataLength)
+	copy(data[12:32], id.Origin[:])
+	new(uint256.Int).SetUint64(id.BlockNumber).WriteToSlice(data[32:64])
+	new(uint256.Int).SetUint64(uint64(id.LogIndex)).WriteToSlice(data[64:96])
+	new(uint256.Int).SetUint64(id.Timestamp).WriteToSlice(data[96:128])
+	id.ChainID.WriteToSlice(data[128:160])
+	return &types.Log{
+		Address: params.InteropCrossL2InboxAddress,
+		Topics:  []common.Hash{ExecutingMessageEventTopic, payloadHash},
+		Data:    data,
+	}
+}
+
+func TestExecutingMessageFromLog(t *testing.T) {
+	id := Identifier{
+		Origin:      common.HexToAddress("0x1234"),
+		BlockNumber: 100,
+		LogIndex:    3,
+		Timestamp:   1700000000,
+		ChainID:     *uint256.NewInt(901),
+	}
+	hash := common.HexToHash("0xabcd")
+
+	msg, err := ExecutingMessageFromLog(executingMessageLog(id, hash))
+	if err != nil {
+		t.Fatalf("failed to decode executing message: %v", err)
+	}
+	if msg.Identifier != id || msg.PayloadHash != hash {
+		t.Fatalf("message mismatch: have %+v, want %+v/%x", msg, id, hash)
+	}
+	// Logs of other contracts or events are ignored
+	other := executingMessageLog(id, hash)
+	other.Address = common.HexToAddress("0x1")
+	msgs, err := ExecutingMessagesFromLogs([]*types.Log{other, executingMessageLog(id, hash)})
+	if err != nil {
+		t.Fatalf("failed to collect executing messages: %v", err)
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("message count mismatch: have %d, want 1", len(msgs))
+	}
+	// Malformed events are rejected
+	bad := executingMessageLog(id, hash)
+	bad.Data = bad.Data[:64]
+	if _, err := ExecutingMessagesFromLogs([]*types.Log{bad}); !errors.Is(err, ErrMalformedExecutingMessage) {
+		t.Fatalf("unexpected error: have %v, want %v", err, ErrMalformedExecutingMessage)
+	}
+	bad = executingMessageLog(id, hash)
+	bad.Data[64] = 1 // log index overflow
+	if _, err := ExecutingMessageFromLog(bad); !errors.Is(err, ErrMalformedExecutingMessage) {
+		t.Fatalf("unexpected error: have %v, want %v", err, ErrMalformedExecutingMessage)
+	}
+}
+
+func TestIdentifierJSON(t *testing.T) {
+	id := Identifier{
+		Origin:      common.HexToAddress("0x1234"),
+		BlockNumber: 100,
+		LogIndex:    3,
+		Timestamp:   1700000000,
+		ChainID:     *uint256.NewInt(901),
+	}
+	enc, err := json.Marshal(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Identifier
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec != id {
+		t.Fatalf("identifier mismatch after roundtrip: have %+v, want %+v", dec, id)
+	}
+}
+
+func TestSafetyLevel(t *testing.T) {
+	if !Finalized.AtLeast(CrossUnsafe) || !CrossUnsafe.AtLeast(CrossUnsafe) {
+		t.Error("stronger safety level not accepted")
+	}
+	if Unsafe.AtLeast(CrossUnsafe) || Invalid.AtLeast(Unsafe) {
+		t.Error("weaker safety level accepted")
+	}
+	if SafetyLevel("bogus").Validate() == nil {
+		t.Error("unknown safety level accepted")
+	}
+}
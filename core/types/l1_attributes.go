@@ -0,0 +1,150 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// bedrockL1AttributesLength is the length of the calldata of a Bedrock L1
+	// attributes transaction: the selector followed by 8 ABI-encoded words.
+	bedrockL1AttributesLength = 4 + 32*8
+
+	// ecotoneL1AttributesLength is the length of the calldata of an Ecotone L1
+	// attributes transaction, which is tightly packed.
+	ecotoneL1AttributesLength = 164
+)
+
+// L1Attributes are the attributes of the L1 origin of an L2 block, as set by the
+// L1 attributes deposit transaction at the start of the block.
+type L1Attributes struct {
+	Number         uint64      // L1 origin block number
+	Time           uint64      // L1 origin block timestamp
+	BaseFee        *big.Int    // L1 origin base fee
+	BlockHash      common.Hash // L1 origin block hash
+	SequenceNumber uint64      // Number of L2 blocks since the start of the epoch
+	BatcherHash    common.Hash // Versioned hash of the batcher address
+
+	L1FeeOverhead *big.Int // pre-ecotone
+	L1FeeScalar   *big.Int // pre-ecotone
+
+	BlobBaseFee       *big.Int // post-ecotone
+	BaseFeeScalar     *uint32  // post-ecotone
+	BlobBaseFeeScalar *uint32  // post-ecotone
+}
+
+// IsEcotone reports whether the attributes are in the Ecotone layout, which is
+// also used by all later upgrades.
+func (a *L1Attributes) IsEcotone() bool {
+	return a.BlobBaseFee != nil
+}
+
+// DecodeL1Attributes decodes the calldata of an L1 attributes deposit transaction,
+// in either the Bedrock or the Ecotone layout, as indicated by its selector.
+func DecodeL1Attributes(data []byte) (*L1Attributes, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("expected at least 4 L1 info bytes, got %d", len(data))
+	}
+	switch {
+	case bytes.Equal(data[:4], BedrockL1AttributesSelector):
+		return decodeL1AttributesBedrock(data)
+	case bytes.Equal(data[:4], EcotoneL1AttributesSelector):
+		return decodeL1AttributesEcotone(data)
+	default:
+		return nil, fmt.Errorf("unknown L1 info selector %x", data[:4])
+	}
+}
+
+func decodeL1AttributesBedrock(data []byte) (*L1Attributes, error) {
+	// Trailing data is ignored, as it always has been by the L1 cost function
+	if len(data) < bedrockL1AttributesLength {
+		return nil, fmt.Errorf("expected at least %d L1 info bytes, got %d", bedrockL1AttributesLength, len(data))
+	}
+	// data layout for Bedrock, after the selector, each argument is an ABI-encoded word:
+	// 0     uint64 _number
+	// 1     uint64 _timestamp
+	// 2     uint256 _basefee
+	// 3     bytes32 _hash
+	// 4     uint64 _sequenceNumber
+	// 5     bytes32 _batcherHash
+	// 6     uint256 _l1FeeOverhead
+	// 7     uint256 _l1FeeScalar
+	word := func(i int) []byte { return data[4+32*i : 4+32*(i+1)] }
+	uint64Word := func(i int) (uint64, error) {
+		w := new(big.Int).SetBytes(word(i))
+		if !w.IsUint64() {
+			return 0, fmt.Errorf("L1 info argument %d overflows uint64", i)
+		}
+		return w.Uint64(), nil
+	}
+	var (
+		attrs = &L1Attributes{
+			BaseFee:       new(big.Int).SetBytes(word(2)),
+			BlockHash:     common.BytesToHash(word(3)),
+			BatcherHash:   common.BytesToHash(word(5)),
+			L1FeeOverhead: new(big.Int).SetBytes(word(6)),
+			L1FeeScalar:   new(big.Int).SetBytes(word(7)),
+		}
+		err error
+	)
+	if attrs.Number, err = uint64Word(0); err != nil {
+		return nil, err
+	}
+	if attrs.Time, err = uint64Word(1); err != nil {
+		return nil, err
+	}
+	if attrs.SequenceNumber, err = uint64Word(4); err != nil {
+		return nil, err
+	}
+	return attrs, nil
+}
+
+func decodeL1AttributesEcotone(data []byte) (*L1Attributes, error) {
+	if len(data) != ecotoneL1AttributesLength {
+		return nil, fmt.Errorf("expected %d L1 info bytes, got %d", ecotoneL1AttributesLength, len(data))
+	}
+	// data layout for Ecotone, tightly packed after the selector:
+	// offset type varname
+	// 4     uint32 _basefeeScalar
+	// 8     uint32 _blobBaseFeeScalar
+	// 12    uint64 _sequenceNumber,
+	// 20    uint64 _timestamp,
+	// 28    uint64 _l1BlockNumber
+	// 36    uint256 _basefee,
+	// 68    uint256 _blobBaseFee,
+	// 100   bytes32 _hash,
+	// 132   bytes32 _batcherHash,
+	baseFeeScalar := binary.BigEndian.Uint32(data[4:8])
+	blobBaseFeeScalar := binary.BigEndian.Uint32(data[8:12])
+	return &L1Attributes{
+		SequenceNumber:    binary.BigEndian.Uint64(data[12:20]),
+		Time:              binary.BigEndian.Uint64(data[20:28]),
+		Number:            binary.BigEndian.Uint64(data[28:36]),
+		BaseFee:           new(big.Int).SetBytes(data[36:68]),
+		BlobBaseFee:       new(big.Int).SetBytes(data[68:100]),
+		BlockHash:         common.BytesToHash(data[100:132]),
+		BatcherHash:       common.BytesToHash(data[132:164]),
+		BaseFeeScalar:     &baseFeeScalar,
+		BlobBaseFeeScalar: &blobBaseFeeScalar,
+	}, nil
+}
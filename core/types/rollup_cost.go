@@ -18,11 +18,11 @@ package types
 
 import (
 	"bytes"
-	"encoding/binary"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 )
@@ -115,6 +115,17 @@ func NewRollupCostData(data []byte) (out RollupCostData) {
 	return out
 }
 
+// placeholderSignature stands in for the signature of unsigned transactions. Its
+// R and S values are incompressible, like those of an actual signature.
+var placeholderSignature = append(append(crypto.Keccak256([]byte("r")), crypto.Keccak256([]byte("s"))...), 1)
+
+// WithPlaceholderSignature returns a copy of an unsigned transaction with a
+// placeholder signature, so that its encoded size matches the signed one, as
+// needed to estimate its data availability fee.
+func (tx *Transaction) WithPlaceholderSignature(signer Signer) (*Transaction, error) {
+	return tx.WithSignature(signer, placeholderSignature)
+}
+
 // NewL1CostFunc returns a function used for calculating data availability fees, or nil if this is
 // not an op-stack chain.
 func NewL1CostFunc(config *params.ChainConfig, statedb StateGetter) L1CostFunc {
@@ -124,41 +135,10 @@ func NewL1CostFunc(config *params.ChainConfig, statedb StateGetter) L1CostFunc {
 	forBlock := ^uint64(0)
 	var cachedFunc l1CostFunc
 	selectFunc := func(blockTime uint64) l1CostFunc {
-		if !config.IsOptimismEcotone(blockTime) {
-			return newL1CostFuncBedrock(config, statedb, blockTime)
-		}
-
-		// Note: the various state variables below are not initialized from the DB until this
+		// Note: the various state variables are not initialized from the DB until this
 		// point to allow deposit transactions from the block to be processed first by state
 		// transition.  This behavior is consensus critical!
-		l1FeeScalars := statedb.GetState(L1BlockAddr, L1FeeScalarsSlot).Bytes()
-		l1BlobBaseFee := statedb.GetState(L1BlockAddr, L1BlobBaseFeeSlot).Big()
-		l1BaseFee := statedb.GetState(L1BlockAddr, L1BaseFeeSlot).Big()
-
-		// Edge case: the very first Ecotone block requires we use the Bedrock cost
-		// function. We detect this scenario by checking if the Ecotone parameters are
-		// unset. Note here we rely on assumption that the scalar parameters are adjacent
-		// in the buffer and l1BaseFeeScalar comes first. We need to check this prior to
-		// other forks, as the first block of Fjord and Ecotone could be the same block.
-		firstEcotoneBlock := l1BlobBaseFee.BitLen() == 0 &&
-			bytes.Equal(emptyScalars, l1FeeScalars[scalarSectionStart:scalarSectionStart+8])
-		if firstEcotoneBlock {
-			log.Info("using bedrock l1 cost func for first Ecotone block", "time", blockTime)
-			return newL1CostFuncBedrock(config, statedb, blockTime)
-		}
-
-		l1BaseFeeScalar, l1BlobBaseFeeScalar := extractEcotoneFeeParams(l1FeeScalars)
-
-		if config.IsOptimismFjord(blockTime) {
-			return NewL1CostFuncFjord(
-				l1BaseFee,
-				l1BlobBaseFee,
-				l1BaseFeeScalar,
-				l1BlobBaseFeeScalar,
-			)
-		} else {
-			return newL1CostFuncEcotone(l1BaseFee, l1BlobBaseFee, l1BaseFeeScalar, l1BlobBaseFeeScalar)
-		}
+		return extractL1GasParamsFromState(config, statedb, blockTime).costFunc
 	}
 
 	return func(rollupCostData RollupCostData, blockTime uint64) *big.Int {
@@ -179,18 +159,8 @@ func NewL1CostFunc(config *params.ChainConfig, statedb StateGetter) L1CostFunc {
 	}
 }
 
-// newL1CostFuncBedrock returns an L1 cost function suitable for Bedrock, Regolith, and the first
-// block only of the Ecotone upgrade.
-func newL1CostFuncBedrock(config *params.ChainConfig, statedb StateGetter, blockTime uint64) l1CostFunc {
-	l1BaseFee := statedb.GetState(L1BlockAddr, L1BaseFeeSlot).Big()
-	overhead := statedb.GetState(L1BlockAddr, OverheadSlot).Big()
-	scalar := statedb.GetState(L1BlockAddr, ScalarSlot).Big()
-	isRegolith := config.IsRegolith(blockTime)
-	return newL1CostFuncBedrockHelper(l1BaseFee, overhead, scalar, isRegolith)
-}
-
-// newL1CostFuncBedrockHelper is lower level version of newL1CostFuncBedrock that expects already
-// extracted parameters
+// newL1CostFuncBedrockHelper returns an L1 cost function suitable for Bedrock, Regolith, and the
+// first block only of the Ecotone upgrade.
 func newL1CostFuncBedrockHelper(l1BaseFee, overhead, scalar *big.Int, isRegolith bool) l1CostFunc {
 	return func(rollupCostData RollupCostData) (fee, gasUsed *big.Int) {
 		if rollupCostData == (RollupCostData{}) {
@@ -259,83 +229,137 @@ func intToScaledFloat(scalar *big.Int) *big.Float {
 
 // extractL1GasParams extracts the gas parameters necessary to compute gas costs from L1 block info
 func extractL1GasParams(config *params.ChainConfig, time uint64, data []byte) (gasParams, error) {
+	attrs, err := DecodeL1Attributes(data)
+	if err != nil {
+		return gasParams{}, err
+	}
 	// edge case: for the very first Ecotone block we still need to use the Bedrock
-	// function. We detect this edge case by seeing if the function selector is the old one
-	// If so, fall through to the pre-ecotone format
-	// Both Ecotone and Fjord use the same function selector
-	if config.IsEcotone(time) && len(data) >= 4 && !bytes.Equal(data[0:4], BedrockL1AttributesSelector) {
-		p, err := extractL1GasParamsPostEcotone(data)
-		if err != nil {
-			return gasParams{}, err
-		}
+	// function. It is detected by the old function selector, decoded into the
+	// Bedrock layout. Both Ecotone and Fjord use the same function selector.
+	if !attrs.IsEcotone() {
+		return gasParams{
+			l1BaseFee: attrs.BaseFee,
+			costFunc:  newL1CostFuncBedrockHelper(attrs.BaseFee, attrs.L1FeeOverhead, attrs.L1FeeScalar, config.IsRegolith(time)),
+			feeScalar: intToScaledFloat(attrs.L1FeeScalar), // legacy: format fee scalar as big Float
+		}, nil
+	}
+	if !config.IsEcotone(time) {
+		return gasParams{}, fmt.Errorf("unexpected Ecotone L1 info at time %d, before Ecotone", time)
+	}
+	p := gasParams{
+		l1BaseFee:           attrs.BaseFee,
+		l1BlobBaseFee:       attrs.BlobBaseFee,
+		l1BaseFeeScalar:     attrs.BaseFeeScalar,
+		l1BlobBaseFeeScalar: attrs.BlobBaseFeeScalar,
+	}
+	if config.IsFjord(time) {
+		p.costFunc = NewL1CostFuncFjord(
+			p.l1BaseFee,
+			p.l1BlobBaseFee,
+			big.NewInt(int64(*p.l1BaseFeeScalar)),
+			big.NewInt(int64(*p.l1BlobBaseFeeScalar)),
+		)
+	} else {
+		p.costFunc = newL1CostFuncEcotone(
+			p.l1BaseFee,
+			p.l1BlobBaseFee,
+			big.NewInt(int64(*p.l1BaseFeeScalar)),
+			big.NewInt(int64(*p.l1BlobBaseFeeScalar)),
+		)
+	}
+	return p, nil
+}
 
-		if config.IsFjord(time) {
-			p.costFunc = NewL1CostFuncFjord(
-				p.l1BaseFee,
-				p.l1BlobBaseFee,
-				big.NewInt(int64(*p.l1BaseFeeScalar)),
-				big.NewInt(int64(*p.l1BlobBaseFeeScalar)),
-			)
-		} else {
-			p.costFunc = newL1CostFuncEcotone(
-				p.l1BaseFee,
-				p.l1BlobBaseFee,
-				big.NewInt(int64(*p.l1BaseFeeScalar)),
-				big.NewInt(int64(*p.l1BlobBaseFeeScalar)),
-			)
-		}
+// L1GasParams are the L1 gas attributes of an L2 block, as set by the L1 attributes transaction
+// at the start of the block.
+type L1GasParams struct {
+	L1BaseFee           *big.Int
+	L1BlobBaseFee       *big.Int   // post-ecotone
+	FeeScalar           *big.Float // pre-ecotone
+	L1BaseFeeScalar     *uint64    // post-ecotone
+	L1BlobBaseFeeScalar *uint64    // post-ecotone
 
-		return p, nil
-	}
-	return extractL1GasParamsPreEcotone(config, time, data)
+	costFunc l1CostFunc
 }
 
-func extractL1GasParamsPreEcotone(config *params.ChainConfig, time uint64, data []byte) (gasParams, error) {
-	// data consists of func selector followed by 7 ABI-encoded parameters (32 bytes each)
-	if len(data) < 4+32*8 {
-		return gasParams{}, fmt.Errorf("expected at least %d L1 info bytes, got %d", 4+32*8, len(data))
+// ExtractL1GasParams decodes the L1 gas attributes from the calldata of the L1 attributes
+// transaction of a block with the given timestamp.
+func ExtractL1GasParams(config *params.ChainConfig, time uint64, data []byte) (*L1GasParams, error) {
+	p, err := extractL1GasParams(config, time, data)
+	if err != nil {
+		return nil, err
 	}
-	data = data[4:]                                       // trim function selector
-	l1BaseFee := new(big.Int).SetBytes(data[32*2 : 32*3]) // arg index 2
-	overhead := new(big.Int).SetBytes(data[32*6 : 32*7])  // arg index 6
-	scalar := new(big.Int).SetBytes(data[32*7 : 32*8])    // arg index 7
-	feeScalar := intToScaledFloat(scalar)                 // legacy: format fee scalar as big Float
-	costFunc := newL1CostFuncBedrockHelper(l1BaseFee, overhead, scalar, config.IsRegolith(time))
-	return gasParams{
-		l1BaseFee: l1BaseFee,
-		costFunc:  costFunc,
-		feeScalar: feeScalar,
+	return &L1GasParams{
+		L1BaseFee:           p.l1BaseFee,
+		L1BlobBaseFee:       p.l1BlobBaseFee,
+		FeeScalar:           p.feeScalar,
+		L1BaseFeeScalar:     u32ptrTou64ptr(p.l1BaseFeeScalar),
+		L1BlobBaseFeeScalar: u32ptrTou64ptr(p.l1BlobBaseFeeScalar),
+		costFunc:            p.costFunc,
 	}, nil
 }
 
-// extractL1GasParamsPostEcotone extracts the gas parameters necessary to compute gas from L1 attribute
-// info calldata after the Ecotone upgrade, but not for the very first Ecotone block.
-func extractL1GasParamsPostEcotone(data []byte) (gasParams, error) {
-	if len(data) != 164 {
-		return gasParams{}, fmt.Errorf("expected 164 L1 info bytes, got %d", len(data))
+// L1Cost returns the data availability fee and the L1 gas used of a transaction with the given
+// cost data, under the L1 gas attributes.
+func (p *L1GasParams) L1Cost(rcd RollupCostData) (fee, gasUsed *big.Int) {
+	return p.costFunc(rcd)
+}
+
+// extractL1GasParamsFromState extracts the gas parameters necessary to compute gas costs from the
+// L1 gas attributes stored in the L1Block contract, as updated by the L1 attributes transaction
+// of the block.
+func extractL1GasParamsFromState(config *params.ChainConfig, statedb StateGetter, time uint64) gasParams {
+	l1BaseFee := statedb.GetState(L1BlockAddr, L1BaseFeeSlot).Big()
+	if config.IsOptimismEcotone(time) {
+		l1FeeScalars := statedb.GetState(L1BlockAddr, L1FeeScalarsSlot).Bytes()
+		l1BlobBaseFee := statedb.GetState(L1BlockAddr, L1BlobBaseFeeSlot).Big()
+
+		// Edge case: the very first Ecotone block requires we use the Bedrock cost
+		// function. We detect this scenario by checking if the Ecotone parameters are
+		// unset. Note here we rely on assumption that the scalar parameters are adjacent
+		// in the buffer and l1BaseFeeScalar comes first. We need to check this prior to
+		// other forks, as the first block of Fjord and Ecotone could be the same block.
+		firstEcotoneBlock := l1BlobBaseFee.BitLen() == 0 &&
+			bytes.Equal(emptyScalars, l1FeeScalars[scalarSectionStart:scalarSectionStart+8])
+		if firstEcotoneBlock {
+			log.Info("using bedrock l1 cost func for first Ecotone block", "time", time)
+		} else {
+			l1BaseFeeScalar, l1BlobBaseFeeScalar := extractEcotoneFeeParams(l1FeeScalars)
+			baseFeeScalar, blobBaseFeeScalar := uint32(l1BaseFeeScalar.Uint64()), uint32(l1BlobBaseFeeScalar.Uint64())
+			p := gasParams{
+				l1BaseFee:           l1BaseFee,
+				l1BlobBaseFee:       l1BlobBaseFee,
+				l1BaseFeeScalar:     &baseFeeScalar,
+				l1BlobBaseFeeScalar: &blobBaseFeeScalar,
+			}
+			if config.IsOptimismFjord(time) {
+				p.costFunc = NewL1CostFuncFjord(l1BaseFee, l1BlobBaseFee, l1BaseFeeScalar, l1BlobBaseFeeScalar)
+			} else {
+				p.costFunc = newL1CostFuncEcotone(l1BaseFee, l1BlobBaseFee, l1BaseFeeScalar, l1BlobBaseFeeScalar)
+			}
+			return p
+		}
 	}
-	// data layout assumed for Ecotone:
-	// offset type varname
-	// 0     <selector>
-	// 4     uint32 _basefeeScalar
-	// 8     uint32 _blobBaseFeeScalar
-	// 12    uint64 _sequenceNumber,
-	// 20    uint64 _timestamp,
-	// 28    uint64 _l1BlockNumber
-	// 36    uint256 _basefee,
-	// 68    uint256 _blobBaseFee,
-	// 100   bytes32 _hash,
-	// 132   bytes32 _batcherHash,
-	l1BaseFee := new(big.Int).SetBytes(data[36:68])
-	l1BlobBaseFee := new(big.Int).SetBytes(data[68:100])
-	l1BaseFeeScalar := binary.BigEndian.Uint32(data[4:8])
-	l1BlobBaseFeeScalar := binary.BigEndian.Uint32(data[8:12])
+	overhead := statedb.GetState(L1BlockAddr, OverheadSlot).Big()
+	scalar := statedb.GetState(L1BlockAddr, ScalarSlot).Big()
 	return gasParams{
-		l1BaseFee:           l1BaseFee,
-		l1BlobBaseFee:       l1BlobBaseFee,
-		l1BaseFeeScalar:     &l1BaseFeeScalar,
-		l1BlobBaseFeeScalar: &l1BlobBaseFeeScalar,
-	}, nil
+		l1BaseFee: l1BaseFee,
+		costFunc:  newL1CostFuncBedrockHelper(l1BaseFee, overhead, scalar, config.IsOptimismRegolith(time)),
+		feeScalar: intToScaledFloat(scalar),
+	}
+}
+
+// SetL1FeeFieldsFromState sets the L1 fee fields of the receipt of a non-deposit transaction,
+// reading the L1 gas attributes from the L1Block contract in the given state. The result matches
+// the fields that DeriveFields computes from the L1 attributes transaction of the block.
+func (r *Receipt) SetL1FeeFieldsFromState(config *params.ChainConfig, statedb StateGetter, time uint64, costData RollupCostData) {
+	gasParams := extractL1GasParamsFromState(config, statedb, time)
+	r.L1GasPrice = gasParams.l1BaseFee
+	r.L1BlobBaseFee = gasParams.l1BlobBaseFee
+	r.L1Fee, r.L1GasUsed = gasParams.costFunc(costData)
+	r.FeeScalar = gasParams.feeScalar
+	r.L1BaseFeeScalar = u32ptrTou64ptr(gasParams.l1BaseFeeScalar)
+	r.L1BlobBaseFeeScalar = u32ptrTou64ptr(gasParams.l1BlobBaseFeeScalar)
 }
 
 // L1Cost computes the the data availability fee for transactions in blocks prior to the Ecotone
@@ -174,7 +174,7 @@ func TestExtractEcotoneGasParams(t *testing.T) {
 
 	// make sure wrong amont of data results in error
 	data = append(data, 0x00) // tack on garbage byte
-	_, err = extractL1GasParamsPostEcotone(data)
+	_, err = extractL1GasParams(config, zeroTime, data)
 	require.Error(t, err)
 }
 
@@ -208,6 +208,31 @@ func TestExtractFjordGasParams(t *testing.T) {
 
 // make sure the first block of the ecotone upgrade is properly detected, and invokes the bedrock
 // cost function appropriately
+func TestExtractL1GasParamsExported(t *testing.T) {
+	zeroTime := uint64(0)
+	config := &params.ChainConfig{
+		Optimism:     params.OptimismTestConfig.Optimism,
+		RegolithTime: &zeroTime,
+		EcotoneTime:  &zeroTime,
+	}
+	data := getEcotoneL1Attributes(baseFee, blobBaseFee, baseFeeScalar, blobBaseFeeScalar)
+
+	p, err := ExtractL1GasParams(config, zeroTime, data)
+	require.NoError(t, err)
+	require.Equal(t, baseFee, p.L1BaseFee)
+	require.Equal(t, blobBaseFee, p.L1BlobBaseFee)
+	require.Equal(t, baseFeeScalar.Uint64(), *p.L1BaseFeeScalar)
+	require.Equal(t, blobBaseFeeScalar.Uint64(), *p.L1BlobBaseFeeScalar)
+	require.Nil(t, p.FeeScalar)
+
+	c, g := p.L1Cost(emptyTx.RollupCostData())
+	require.Equal(t, ecotoneGas, g)
+	require.Equal(t, ecotoneFee, c)
+
+	_, err = ExtractL1GasParams(config, zeroTime, data[:100])
+	require.Error(t, err)
+}
+
 func TestFirstBlockEcotoneGasParams(t *testing.T) {
 	zeroTime := uint64(0)
 	// create a config where ecotone upgrade is active
@@ -365,6 +390,58 @@ func TestNewL1CostFunc(t *testing.T) {
 	require.Equal(t, regolithFee, fee)
 }
 
+// TestReceiptL1FeeFieldsFromState tests that the L1 fee fields read from the state match the
+// ones derived from the L1 attributes transaction.
+func TestReceiptL1FeeFieldsFromState(t *testing.T) {
+	zeroTime := uint64(0)
+	config := &params.ChainConfig{
+		Optimism:     params.OptimismTestConfig.Optimism,
+		RegolithTime: &zeroTime,
+	}
+	statedb := &testStateGetter{
+		baseFee:           baseFee,
+		overhead:          overhead,
+		scalar:            scalar,
+		blobBaseFee:       blobBaseFee,
+		baseFeeScalar:     uint32(baseFeeScalar.Uint64()),
+		blobBaseFeeScalar: uint32(blobBaseFeeScalar.Uint64()),
+	}
+	var r Receipt
+	r.SetL1FeeFieldsFromState(config, statedb, zeroTime, emptyTx.RollupCostData())
+	require.Equal(t, regolithFee, r.L1Fee)
+	require.Equal(t, regolithGas, r.L1GasUsed)
+	require.Equal(t, baseFee, r.L1GasPrice)
+	require.Equal(t, "7", r.FeeScalar.String())
+	require.Nil(t, r.L1BlobBaseFee)
+	require.Nil(t, r.L1BaseFeeScalar)
+
+	config.EcotoneTime = &zeroTime
+	r = Receipt{}
+	r.SetL1FeeFieldsFromState(config, statedb, zeroTime, emptyTx.RollupCostData())
+	require.Equal(t, ecotoneFee, r.L1Fee)
+	require.Equal(t, ecotoneGas, r.L1GasUsed)
+	require.Equal(t, blobBaseFee, r.L1BlobBaseFee)
+	require.Equal(t, baseFeeScalar.Uint64(), *r.L1BaseFeeScalar)
+	require.Equal(t, blobBaseFeeScalar.Uint64(), *r.L1BlobBaseFeeScalar)
+	require.Nil(t, r.FeeScalar)
+
+	config.FjordTime = &zeroTime
+	r = Receipt{}
+	r.SetL1FeeFieldsFromState(config, statedb, zeroTime, emptyTx.RollupCostData())
+	require.Equal(t, fjordFee, r.L1Fee)
+	require.Equal(t, minimumFjordGas, r.L1GasUsed)
+
+	// first Ecotone block: the Bedrock parameters are still in use
+	statedb.baseFeeScalar = 0
+	statedb.blobBaseFeeScalar = 0
+	statedb.blobBaseFee = new(big.Int)
+	config.FjordTime = nil
+	r = Receipt{}
+	r.SetL1FeeFieldsFromState(config, statedb, zeroTime, emptyTx.RollupCostData())
+	require.Equal(t, regolithFee, r.L1Fee)
+	require.Nil(t, r.L1BaseFeeScalar)
+}
+
 func TestFlzCompressLen(t *testing.T) {
 	var (
 		emptyTxBytes, _   = emptyTx.MarshalBinary()
@@ -405,3 +482,61 @@ func TestFlzCompressLen(t *testing.T) {
 		require.Equal(t, tc.expectedLen, output)
 	}
 }
+
+func TestDecodeL1Attributes(t *testing.T) {
+	// Bedrock layout: selector followed by 8 ABI-encoded words.
+	data := make([]byte, 4+32*8)
+	copy(data, BedrockL1AttributesSelector)
+	big.NewInt(100).FillBytes(data[4 : 4+32])            // number
+	big.NewInt(10).FillBytes(data[4+32 : 4+32*2])        // timestamp
+	baseFee.FillBytes(data[4+32*2 : 4+32*3])             // basefee
+	copy(data[4+32*3:4+32*4], common.Hash{0x01}.Bytes()) // hash
+	big.NewInt(3).FillBytes(data[4+32*4 : 4+32*5])       // sequence number
+	copy(data[4+32*5:4+32*6], common.Hash{0x02}.Bytes()) // batcher hash
+	overhead.FillBytes(data[4+32*6 : 4+32*7])            // l1 fee overhead
+	scalar.FillBytes(data[4+32*7 : 4+32*8])              // l1 fee scalar
+	attrs, err := DecodeL1Attributes(data)
+	require.NoError(t, err)
+	require.False(t, attrs.IsEcotone())
+	require.Equal(t, &L1Attributes{
+		Number:         100,
+		Time:           10,
+		BaseFee:        baseFee,
+		BlockHash:      common.Hash{0x01},
+		SequenceNumber: 3,
+		BatcherHash:    common.Hash{0x02},
+		L1FeeOverhead:  overhead,
+		L1FeeScalar:    scalar,
+	}, attrs)
+
+	// Ecotone layout: tightly packed.
+	data = make([]byte, 164)
+	copy(data, EcotoneL1AttributesSelector)
+	binary.BigEndian.PutUint32(data[4:8], uint32(baseFeeScalar.Uint64()))
+	binary.BigEndian.PutUint32(data[8:12], uint32(blobBaseFeeScalar.Uint64()))
+	binary.BigEndian.PutUint64(data[12:20], 3)
+	binary.BigEndian.PutUint64(data[20:28], 10)
+	binary.BigEndian.PutUint64(data[28:36], 100)
+	baseFee.FillBytes(data[36:68])
+	blobBaseFee.FillBytes(data[68:100])
+	copy(data[100:132], common.Hash{0x01}.Bytes())
+	copy(data[132:164], common.Hash{0x02}.Bytes())
+	attrs, err = DecodeL1Attributes(data)
+	require.NoError(t, err)
+	require.True(t, attrs.IsEcotone())
+	require.Equal(t, uint64(100), attrs.Number)
+	require.Equal(t, uint64(10), attrs.Time)
+	require.Equal(t, uint64(3), attrs.SequenceNumber)
+	require.Equal(t, baseFee, attrs.BaseFee)
+	require.Equal(t, blobBaseFee, attrs.BlobBaseFee)
+	require.Equal(t, uint32(2), *attrs.BaseFeeScalar)
+	require.Equal(t, uint32(3), *attrs.BlobBaseFeeScalar)
+	require.Equal(t, common.Hash{0x01}, attrs.BlockHash)
+	require.Equal(t, common.Hash{0x02}, attrs.BatcherHash)
+
+	// Malformed calldata is rejected.
+	_, err = DecodeL1Attributes(data[:100])
+	require.Error(t, err)
+	_, err = DecodeL1Attributes([]byte{0xde, 0xad, 0xbe, 0xef})
+	require.Error(t, err)
+}
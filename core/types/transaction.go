@@ -64,6 +64,10 @@ type Transaction struct {
 
 	// cache of details to compute the data availability fee
 	rollupCostData atomic.Value
+
+	// optional preconditions for inclusion, enforced by the sequencer
+	conditional atomic.Pointer[TransactionConditional]
+	rejected    atomic.Bool // set by the sequencer if the preconditions failed
 }
 
 // NewTx creates a new transaction.
@@ -562,6 +566,31 @@ func (tx *Transaction) Time() time.Time {
 	return tx.time
 }
 
+// Conditional returns the preconditions for the inclusion of the transaction,
+// if it was submitted with any.
+func (tx *Transaction) Conditional() *TransactionConditional {
+	return tx.conditional.Load()
+}
+
+// SetConditional sets the preconditions for the inclusion of the transaction.
+// They are not part of the transaction's encoding, so they are lost when the
+// transaction is gossiped.
+func (tx *Transaction) SetConditional(cond *TransactionConditional) {
+	tx.conditional.Store(cond)
+}
+
+// Rejected reports whether the transaction was rejected by the block builder
+// because its preconditions didn't hold.
+func (tx *Transaction) Rejected() bool {
+	return tx.rejected.Load()
+}
+
+// SetRejected marks the transaction as rejected by the block builder, for the
+// transaction pool to drop it.
+func (tx *Transaction) SetRejected() {
+	tx.rejected.Store(true)
+}
+
 // Hash returns the transaction hash.
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
@@ -0,0 +1,135 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+//go:generate go run github.com/fjl/gencodec -type TransactionConditional -field-override transactionConditionalMarshaling -out gen_transaction_conditional_json.go
+
+// KnownAccounts maps accounts to the pre-state a conditional transaction
+// expects them to have.
+type KnownAccounts map[common.Address]KnownAccount
+
+// KnownAccount is the expected pre-state of an account: either its storage
+// root, or the values of a set of storage slots. Only one of the two is set.
+type KnownAccount struct {
+	StorageRoot  *common.Hash
+	StorageSlots map[common.Hash]common.Hash
+}
+
+// MarshalJSON encodes the known account as either a storage root, or a map of
+// storage slots.
+func (ka KnownAccount) MarshalJSON() ([]byte, error) {
+	if ka.StorageRoot != nil {
+		return json.Marshal(ka.StorageRoot)
+	}
+	return json.Marshal(ka.StorageSlots)
+}
+
+// UnmarshalJSON decodes a known account from either a storage root, or a map
+// of storage slots.
+func (ka *KnownAccount) UnmarshalJSON(input []byte) error {
+	var root common.Hash
+	if err := json.Unmarshal(input, &root); err == nil {
+		ka.StorageRoot, ka.StorageSlots = &root, nil
+		return nil
+	}
+	var slots map[common.Hash]common.Hash
+	if err := json.Unmarshal(input, &slots); err != nil {
+		return errors.New("known account must be a storage root or a map of storage slots")
+	}
+	ka.StorageRoot, ka.StorageSlots = nil, slots
+	return nil
+}
+
+// TransactionConditional is a set of preconditions for the inclusion of a
+// transaction, as submitted through eth_sendRawTransactionConditional. It is
+// not part of the consensus encoding of the transaction, and is only enforced
+// by the sequencer building the block. Block number and timestamp bounds are
+// inclusive.
+type TransactionConditional struct {
+	KnownAccounts  KnownAccounts `json:"knownAccounts"`
+	BlockNumberMin *big.Int      `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *big.Int      `json:"blockNumberMax,omitempty"`
+	TimestampMin   *uint64       `json:"timestampMin,omitempty"`
+	TimestampMax   *uint64       `json:"timestampMax,omitempty"`
+}
+
+// field type overrides for gencodec
+type transactionConditionalMarshaling struct {
+	BlockNumberMin *hexutil.Big
+	BlockNumberMax *hexutil.Big
+	TimestampMin   *hexutil.Uint64
+	TimestampMax   *hexutil.Uint64
+}
+
+// Validate checks the consistency of the conditional.
+func (cond *TransactionConditional) Validate() error {
+	if cond.BlockNumberMin != nil && cond.BlockNumberMax != nil && cond.BlockNumberMin.Cmp(cond.BlockNumberMax) > 0 {
+		return fmt.Errorf("block number minimum %v is greater than maximum %v", cond.BlockNumberMin, cond.BlockNumberMax)
+	}
+	if cond.TimestampMin != nil && cond.TimestampMax != nil && *cond.TimestampMin > *cond.TimestampMax {
+		return fmt.Errorf("timestamp minimum %d is greater than maximum %d", *cond.TimestampMin, *cond.TimestampMax)
+	}
+	return nil
+}
+
+// Cost returns the number of checks needed to enforce the conditional, which
+// is used to bound the work an RPC caller can request.
+func (cond *TransactionConditional) Cost() int {
+	cost := 0
+	if cond.BlockNumberMin != nil || cond.BlockNumberMax != nil {
+		cost++
+	}
+	if cond.TimestampMin != nil || cond.TimestampMax != nil {
+		cost++
+	}
+	for _, account := range cond.KnownAccounts {
+		if account.StorageRoot != nil {
+			cost++
+		} else {
+			cost += len(account.StorageSlots)
+		}
+	}
+	return cost
+}
+
+// CheckTransactionConditional checks the block number and timestamp bounds of
+// the conditional against the header.
+func (h *Header) CheckTransactionConditional(cond *TransactionConditional) error {
+	if cond.BlockNumberMin != nil && h.Number.Cmp(cond.BlockNumberMin) < 0 {
+		return fmt.Errorf("block number %v below minimum %v", h.Number, cond.BlockNumberMin)
+	}
+	if cond.BlockNumberMax != nil && h.Number.Cmp(cond.BlockNumberMax) > 0 {
+		return fmt.Errorf("block number %v above maximum %v", h.Number, cond.BlockNumberMax)
+	}
+	if cond.TimestampMin != nil && h.Time < *cond.TimestampMin {
+		return fmt.Errorf("timestamp %d below minimum %d", h.Time, *cond.TimestampMin)
+	}
+	if cond.TimestampMax != nil && h.Time > *cond.TimestampMax {
+		return fmt.Errorf("timestamp %d above maximum %d", h.Time, *cond.TimestampMax)
+	}
+	return nil
+}
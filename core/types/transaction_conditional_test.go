@@ -0,0 +1,95 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTransactionConditionalJSON(t *testing.T) {
+	input := `{
+		"knownAccounts": {
+			"0x0000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002",
+			"0x0000000000000000000000000000000000000003": {
+				"0x0000000000000000000000000000000000000000000000000000000000000004": "0x0000000000000000000000000000000000000000000000000000000000000005"
+			}
+		},
+		"blockNumberMin": "0x10",
+		"timestampMax": "0x20"
+	}`
+	var cond TransactionConditional
+	if err := json.Unmarshal([]byte(input), &cond); err != nil {
+		t.Fatal(err)
+	}
+	root := common.HexToHash("0x2")
+	want := TransactionConditional{
+		KnownAccounts: KnownAccounts{
+			common.HexToAddress("0x1"): {StorageRoot: &root},
+			common.HexToAddress("0x3"): {StorageSlots: map[common.Hash]common.Hash{common.HexToHash("0x4"): common.HexToHash("0x5")}},
+		},
+		BlockNumberMin: big.NewInt(16),
+		TimestampMax:   new(uint64),
+	}
+	*want.TimestampMax = 32
+	if !reflect.DeepEqual(cond, want) {
+		t.Fatalf("decoding mismatch: have %+v, want %+v", cond, want)
+	}
+	blob, err := json.Marshal(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec TransactionConditional
+	if err := json.Unmarshal(blob, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, want) {
+		t.Fatalf("round trip mismatch: have %+v, want %+v", dec, want)
+	}
+	if cost := cond.Cost(); cost != 4 {
+		t.Fatalf("cost mismatch: have %d, want 4", cost)
+	}
+}
+
+func TestHeaderCheckTransactionConditional(t *testing.T) {
+	u64 := func(n uint64) *uint64 { return &n }
+	header := &Header{Number: big.NewInt(10), Time: 100}
+	tests := []struct {
+		cond TransactionConditional
+		ok   bool
+	}{
+		{TransactionConditional{}, true},
+		{TransactionConditional{BlockNumberMin: big.NewInt(10), BlockNumberMax: big.NewInt(10)}, true},
+		{TransactionConditional{BlockNumberMin: big.NewInt(11)}, false},
+		{TransactionConditional{BlockNumberMax: big.NewInt(9)}, false},
+		{TransactionConditional{TimestampMin: u64(100), TimestampMax: u64(100)}, true},
+		{TransactionConditional{TimestampMin: u64(101)}, false},
+		{TransactionConditional{TimestampMax: u64(99)}, false},
+	}
+	for i, tt := range tests {
+		if err := header.CheckTransactionConditional(&tt.cond); (err == nil) != tt.ok {
+			t.Errorf("test %d: unexpected result: %v", i, err)
+		}
+	}
+	if err := (&TransactionConditional{BlockNumberMin: big.NewInt(2), BlockNumberMax: big.NewInt(1)}).Validate(); err == nil {
+		t.Error("inverted block number range not rejected")
+	}
+}
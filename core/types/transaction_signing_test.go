@@ -178,6 +178,31 @@ func TestNilSigner(t *testing.T) {
 	}
 }
 
+// Tests that the signers of OP-Stack chains only accept blob transactions if the
+// chain opted in to them.
+func TestOptimismBlobTxSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	tx := createEmptyBlobTx(key, false)
+
+	config := *params.MergedTestChainConfig
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+	for _, signer := range []Signer{MakeSigner(&config, big.NewInt(0), 0), LatestSigner(&config)} {
+		if _, err := Sender(signer, tx); !errors.Is(err, ErrTxTypeNotSupported) {
+			t.Fatalf("blob transaction sender error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+		}
+	}
+	config.Optimism.BlobTxs = true
+	for _, signer := range []Signer{MakeSigner(&config, big.NewInt(0), 0), LatestSigner(&config)} {
+		from, err := Sender(signer, tx)
+		if err != nil {
+			t.Fatalf("failed to recover blob transaction sender: %v", err)
+		}
+		if want := crypto.PubkeyToAddress(key.PublicKey); from != want {
+			t.Fatalf("blob transaction sender mismatch: have %x, want %x", from, want)
+		}
+	}
+}
+
 func createTestLegacyTxInner() *LegacyTx {
 	return &LegacyTx{
 		Nonce:    uint64(0),
@@ -21,6 +21,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"maps"
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -210,6 +211,37 @@ func init() {
 	}
 }
 
+// activePrecompiledContracts returns the precompiled contracts enabled with the
+// current configuration.
+func activePrecompiledContracts(rules params.Rules) map[common.Address]PrecompiledContract {
+	switch {
+	case rules.IsOptimismGranite:
+		return PrecompiledContractsGranite
+	case rules.IsOptimismFjord:
+		return PrecompiledContractsFjord
+	case rules.IsVerkle:
+		return PrecompiledContractsVerkle
+	case rules.IsPrague:
+		return PrecompiledContractsPrague
+	case rules.IsCancun:
+		return PrecompiledContractsCancun
+	case rules.IsBerlin:
+		return PrecompiledContractsBerlin
+	case rules.IsIstanbul:
+		return PrecompiledContractsIstanbul
+	case rules.IsByzantium:
+		return PrecompiledContractsByzantium
+	default:
+		return PrecompiledContractsHomestead
+	}
+}
+
+// ActivePrecompiledContracts returns a copy of the precompiled contracts enabled
+// with the current configuration.
+func ActivePrecompiledContracts(rules params.Rules) map[common.Address]PrecompiledContract {
+	return maps.Clone(activePrecompiledContracts(rules))
+}
+
 // ActivePrecompiles returns the precompiles enabled with the current configuration.
 func ActivePrecompiles(rules params.Rules) []common.Address {
 	switch {
@@ -41,28 +41,7 @@ type (
 )
 
 func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
-	var precompiles map[common.Address]PrecompiledContract
-	switch {
-	case evm.chainRules.IsOptimismGranite:
-		precompiles = PrecompiledContractsGranite
-	case evm.chainRules.IsOptimismFjord:
-		precompiles = PrecompiledContractsFjord
-	case evm.chainRules.IsVerkle:
-		precompiles = PrecompiledContractsVerkle
-	case evm.chainRules.IsPrague:
-		precompiles = PrecompiledContractsPrague
-	case evm.chainRules.IsCancun:
-		precompiles = PrecompiledContractsCancun
-	case evm.chainRules.IsBerlin:
-		precompiles = PrecompiledContractsBerlin
-	case evm.chainRules.IsIstanbul:
-		precompiles = PrecompiledContractsIstanbul
-	case evm.chainRules.IsByzantium:
-		precompiles = PrecompiledContractsByzantium
-	default:
-		precompiles = PrecompiledContractsHomestead
-	}
-	p, ok := precompiles[addr]
+	p, ok := activePrecompiledContracts(evm.chainRules)[addr]
 	if evm.Config.PrecompileOverrides != nil {
 		override := evm.Config.PrecompileOverrides(evm.chainRules, p, addr)
 		return override, override != nil
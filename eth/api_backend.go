@@ -34,12 +34,14 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -289,15 +291,23 @@ func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscri
 }
 
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
-	if b.ChainConfig().IsOptimism() && signedTx.Type() == types.BlobTxType {
+	if !b.ChainConfig().HasBlobTxs() && signedTx.Type() == types.BlobTxType {
 		return types.ErrTxTypeNotSupported
 	}
-	if b.eth.seqRPCService != nil {
+	if err := b.eth.txPool.CheckOrigin(txpool.OriginFromContext(ctx)); err != nil {
+		return err
+	}
+	if b.eth.seqForwarder != nil {
 		data, err := signedTx.MarshalBinary()
 		if err != nil {
 			return err
 		}
-		if err := b.eth.seqRPCService.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data)); err != nil {
+		if cond := signedTx.Conditional(); cond != nil {
+			err = b.eth.seqForwarder.CallContext(ctx, nil, "eth_sendRawTransactionConditional", hexutil.Encode(data), cond)
+		} else {
+			err = b.eth.seqForwarder.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data))
+		}
+		if err != nil {
 			return err
 		}
 		if b.disableTxPool {
@@ -315,6 +325,20 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return b.eth.txPool.Add([]*types.Transaction{signedTx}, true, false)[0]
 }
 
+func (b *EthAPIBackend) SendBundle(ctx context.Context, bundle *bundlepool.Bundle) error {
+	// A bundle takes a single admission token of its origin, like a transaction
+	if err := b.eth.txPool.CheckOrigin(txpool.OriginFromContext(ctx)); err != nil {
+		return err
+	}
+	if b.eth.seqForwarder != nil {
+		return b.eth.seqForwarder.CallContext(ctx, nil, "eth_sendBundle", bundle)
+	}
+	if b.disableTxPool {
+		return nil
+	}
+	return b.eth.bundlePool.AddBundle(bundle)
+}
+
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	pending := b.eth.txPool.Pending(txpool.PendingFilter{})
 	var txs types.Transactions
@@ -377,6 +401,18 @@ func (b *EthAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.S
 	return b.eth.txPool.SubscribeTransactions(ch, true)
 }
 
+func (b *EthAPIBackend) TxPoolStatus(hash common.Hash) txpool.TxStatus {
+	return b.eth.txPool.Status(hash)
+}
+
+func (b *EthAPIBackend) TxPoolHistory(hash common.Hash) []txpool.TxEvent {
+	return b.eth.txPool.TxHistory(hash)
+}
+
+func (b *EthAPIBackend) SubscribeTxPoolEvents(ch chan<- txpool.TxEvent) event.Subscription {
+	return b.eth.txPool.SubscribeTxEvents(ch)
+}
+
 func (b *EthAPIBackend) SyncProgress() ethereum.SyncProgress {
 	prog := b.eth.Downloader().Progress()
 	if txProg, err := b.eth.blockchain.TxIndexProgress(); err == nil {
@@ -429,6 +465,13 @@ func (b *EthAPIBackend) RPCEVMTimeout() time.Duration {
 	return b.eth.config.RPCEVMTimeout
 }
 
+func (b *EthAPIBackend) RPCTxConditionalMaxCost() uint64 {
+	if !b.eth.config.RollupTxConditionalEnabled {
+		return 0
+	}
+	return b.eth.config.RollupTxConditionalMaxCost
+}
+
 func (b *EthAPIBackend) RPCTxFeeCap() float64 {
 	return b.eth.config.RPCTxFeeCap
 }
@@ -460,8 +503,8 @@ func (b *EthAPIBackend) StateAtTransaction(ctx context.Context, block *types.Blo
 	return b.eth.stateAtTransaction(ctx, block, txIndex, reexec)
 }
 
-func (b *EthAPIBackend) HistoricalRPCService() *rpc.Client {
-	return b.eth.historicalRPCService
+func (b *EthAPIBackend) HistoricalRouter() *ethapi.HistoricalRouter {
+	return b.eth.historicalRouter
 }
 
 func (b *EthAPIBackend) Genesis() *types.Block {
@@ -22,10 +22,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
@@ -223,6 +225,9 @@ func (api *DebugAPI) StorageRangeAt(ctx context.Context, blockNrOrHash rpc.Block
 	if block == nil {
 		return StorageRangeResult{}, fmt.Errorf("block %v not found", blockNrOrHash)
 	}
+	if histResult, routed, err := ethapi.RouteHistorical[StorageRangeResult](ctx, api.eth.APIBackend.HistoricalRouter(), block.Number(), "debug_storageRangeAt", blockNrOrHash, txIndex, contractAddress, keyStart, maxResult); routed {
+		return histResult, err
+	}
 	_, _, statedb, release, err := api.eth.stateAtTransaction(ctx, block, txIndex, 0)
 	if err != nil {
 		return StorageRangeResult{}, err
@@ -446,3 +451,98 @@ func (api *DebugAPI) GetTrieFlushInterval() (string, error) {
 	}
 	return api.eth.blockchain.GetTrieFlushInterval().String(), nil
 }
+
+// witnessReexec is the number of blocks re-executed at most to regenerate the
+// pruned parent state of a block whose execution witness is requested.
+const witnessReexec = 128
+
+// ExecutionWitness re-executes the given canonical block with witness collection
+// enabled, and returns the witness needed to execute it statelessly: the headers
+// of its parent and of the ancestors accessed via BLOCKHASH, and the state trie
+// nodes and codes touched during execution.
+func (api *DebugAPI) ExecutionWitness(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*stateless.ExecutionWitness, error) {
+	block, err := api.eth.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %v not found", blockNrOrHash)
+	}
+	witness, err := api.generateWitness(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	return witness.ToExecutionWitness(), nil
+}
+
+// ExecutionWitnessByBlock returns the execution witness of an RLP encoded block,
+// which doesn't need to be known to the node. The state of its parent must be
+// available or recent enough to be regenerated though.
+func (api *DebugAPI) ExecutionWitnessByBlock(ctx context.Context, blob hexutil.Bytes) (*stateless.ExecutionWitness, error) {
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(blob, block); err != nil {
+		return nil, fmt.Errorf("could not decode block: %v", err)
+	}
+	witness, err := api.generateWitness(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	return witness.ToExecutionWitness(), nil
+}
+
+// ExecutionWitnessByPayload returns the execution witness of an execution payload,
+// with the same semantics as ExecutionWitnessByBlock. The versioned hashes and the
+// parent beacon block root are the ones passed along the payload to engine_newPayload.
+func (api *DebugAPI) ExecutionWitnessByPayload(ctx context.Context, payload engine.ExecutableData, versionedHashes []common.Hash, beaconRoot *common.Hash) (*stateless.ExecutionWitness, error) {
+	block, err := engine.ExecutableDataToBlock(payload, versionedHashes, beaconRoot)
+	if err != nil {
+		return nil, err
+	}
+	witness, err := api.generateWitness(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	return witness.ToExecutionWitness(), nil
+}
+
+// generateWitness executes the block on top of the state of its parent, with
+// witness collection enabled. The parent state is regenerated if it was pruned.
+// The witness is cross-checked with a stateless execution before being returned.
+func (api *DebugAPI) generateWitness(ctx context.Context, block *types.Block) (*stateless.Witness, error) {
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis block has no execution witness")
+	}
+	bc := api.eth.blockchain
+	witness, err := stateless.NewWitness(bc, block)
+	if err != nil {
+		return nil, err
+	}
+	parent := bc.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent block %x not found", block.ParentHash())
+	}
+	statedb, release, err := api.eth.stateAtBlock(ctx, parent, witnessReexec, nil, true, false)
+	if err != nil {
+		return nil, fmt.Errorf("parent state unavailable: %v", err)
+	}
+	defer release()
+
+	statedb.StartPrefetcher("debug_executionWitness", witness)
+	defer statedb.StopPrefetcher()
+
+	// Disable tracing, the block was already reported to any live tracer on import.
+	vmConfig := *bc.GetVMConfig()
+	vmConfig.Tracer = nil
+
+	receipts, _, usedGas, err := bc.Processor().Process(block, statedb, vmConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := bc.Validator().ValidateState(block, statedb, receipts, usedGas, false); err != nil {
+		return nil, err
+	}
+	if err := bc.Validator().ValidateWitness(witness, block.ReceiptHash(), block.Root()); err != nil {
+		return nil, fmt.Errorf("cross verification failed: %v", err)
+	}
+	return witness, nil
+}
@@ -18,7 +18,10 @@ package eth
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"slices"
 	"strings"
@@ -26,11 +29,17 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/holiman/uint256"
 )
@@ -221,3 +230,144 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+func TestExecutionWitness(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0xc0de")
+		// Reads the hash of the grandparent block and increments slot 0.
+		code = []byte{
+			byte(vm.PUSH1), 0x2, byte(vm.NUMBER), byte(vm.SUB), byte(vm.BLOCKHASH), byte(vm.POP),
+			byte(vm.PUSH1), 0x0, byte(vm.SLOAD), byte(vm.PUSH1), 0x1, byte(vm.ADD), byte(vm.PUSH1), 0x0, byte(vm.SSTORE),
+		}
+		gspec = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: types.GenesisAlloc{
+				sender:   {Balance: big.NewInt(params.Ether)},
+				contract: {Code: code},
+			},
+		}
+		engine = beacon.New(ethash.NewFaker())
+		signer = types.LatestSigner(gspec.Config)
+	)
+	db := rawdb.NewMemoryDatabase()
+	chain, err := core.NewBlockChain(db, core.DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+	api := NewDebugAPI(&Ethereum{blockchain: chain, chainDb: db})
+
+	// The hash read by the contract is discarded, so it doesn't matter the chain
+	// used for generation doesn't know the blocks yet.
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, 4, func(i int, b *core.BlockGen) {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   gspec.Config.ChainID,
+			Nonce:     uint64(i),
+			To:        &contract,
+			Gas:       100000,
+			GasFeeCap: b.BaseFee(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.AddTxWithChain(chain, tx)
+		b.SetPoS()
+	})
+	// Import all but the last block, whose witness is generated without importing it.
+	if _, err := chain.InsertChain(blocks[:3]); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	if _, err := api.generateWitness(context.Background(), chain.Genesis()); err == nil {
+		t.Fatal("expected error for the genesis block")
+	}
+	for _, block := range blocks {
+		witness, err := api.generateWitness(context.Background(), block)
+		if err != nil {
+			t.Fatalf("block %d: failed to generate witness: %v", block.NumberU64(), err)
+		}
+		// The headers go back to the grandparent accessed via BLOCKHASH.
+		if want := min(int(block.NumberU64()), 2); len(witness.Headers) != want {
+			t.Errorf("block %d: header count mismatch: have %d, want %d", block.NumberU64(), len(witness.Headers), want)
+		}
+		if _, ok := witness.Codes[string(code)]; !ok {
+			t.Errorf("block %d: contract code missing from witness", block.NumberU64())
+		}
+		// The witness must survive a JSON round trip.
+		blob, err := json.Marshal(witness.ToExecutionWitness())
+		if err != nil {
+			t.Fatalf("block %d: failed to encode witness: %v", block.NumberU64(), err)
+		}
+		var enc stateless.ExecutionWitness
+		if err := json.Unmarshal(blob, &enc); err != nil {
+			t.Fatalf("block %d: failed to decode witness: %v", block.NumberU64(), err)
+		}
+		dec, err := enc.ToWitness(block)
+		if err != nil {
+			t.Fatalf("block %d: failed to rebuild witness: %v", block.NumberU64(), err)
+		}
+		receiptRoot, stateRoot, err := core.ExecuteStateless(gspec.Config, dec)
+		if err != nil {
+			t.Fatalf("block %d: stateless execution failed: %v", block.NumberU64(), err)
+		}
+		if receiptRoot != block.ReceiptHash() || stateRoot != block.Root() {
+			t.Errorf("block %d: root mismatch", block.NumberU64())
+		}
+	}
+}
+
+// Tests that the execution witness of a block is generated on top of its
+// regenerated parent state if that was pruned.
+func TestExecutionWitnessPrunedState(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		gspec  = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc:  types.GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}},
+		}
+		engine = beacon.New(ethash.NewFaker())
+		signer = types.LatestSigner(gspec.Config)
+	)
+	db := rawdb.NewMemoryDatabase()
+	chain, err := core.NewBlockChain(db, core.DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+	api := NewDebugAPI(&Ethereum{blockchain: chain, chainDb: db})
+
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, 4, func(i int, b *core.BlockGen) {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   gspec.Config.ChainID,
+			Nonce:     uint64(i),
+			To:        &common.Address{0x01},
+			Value:     big.NewInt(1),
+			Gas:       params.TxGas,
+			GasFeeCap: b.BaseFee(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.AddTx(tx)
+		b.SetPoS()
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	// Prune the states of all but the genesis and the head block.
+	for _, block := range blocks[:3] {
+		chain.TrieDB().Dereference(block.Root())
+	}
+	if _, err := chain.StateAt(blocks[2].Root()); err == nil {
+		t.Fatal("parent state not pruned")
+	}
+	if _, err := api.generateWitness(context.Background(), blocks[3]); err != nil {
+		t.Fatalf("failed to generate witness on pruned state: %v", err)
+	}
+}
@@ -17,9 +17,12 @@
 package eth
 
 import (
+	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/miner"
 )
 
 // MinerAPI provides an API to control the miner.
@@ -56,3 +59,21 @@ func (api *MinerAPI) SetGasLimit(gasLimit hexutil.Uint64) bool {
 	api.e.Miner().SetGasCeil(uint64(gasLimit))
 	return true
 }
+
+// SetMaxDASize sets the maximum data availability size of the transactions
+// included in blocks, per transaction and per block. Zero means no limit.
+func (api *MinerAPI) SetMaxDASize(maxTxSize hexutil.Uint64, maxBlockSize hexutil.Uint64) bool {
+	api.e.Miner().SetMaxDASize(uint64(maxTxSize), uint64(maxBlockSize))
+	return true
+}
+
+// GetPayloadReport returns the report of a recently built payload: the included
+// transactions, the transactions left out with the reason, and the time spent in
+// each building phase.
+func (api *MinerAPI) GetPayloadReport(id engine.PayloadID) (*miner.PayloadReport, error) {
+	report := api.e.Miner().GetPayloadReport(id)
+	if report == nil {
+		return nil, fmt.Errorf("unknown payload %v", id)
+	}
+	return report, nil
+}
@@ -24,7 +24,6 @@ import (
 	"math/big"
 	"runtime"
 	"sync"
-	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
@@ -36,12 +35,16 @@ import (
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/types/interoptypes"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/forwarder"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
+	"github.com/ethereum/go-ethereum/eth/interop"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/eth/tracers"
@@ -69,15 +72,19 @@ type Ethereum struct {
 	config *ethconfig.Config
 
 	// Handlers
-	txPool *txpool.TxPool
+	txPool     *txpool.TxPool
+	bundlePool *bundlepool.BundlePool
 
 	blockchain         *core.BlockChain
 	handler            *handler
 	ethDialCandidates  enode.Iterator
 	snapDialCandidates enode.Iterator
 
-	seqRPCService        *rpc.Client
+	seqForwarder         *forwarder.Forwarder
 	historicalRPCService *rpc.Client
+	historicalRouter     *ethapi.HistoricalRouter // Routes requests for pre-Bedrock blocks to the legacy node
+	interopRPC           *interop.Client          // Interop supervisor RPC client, if configured
+	supervisor           interoptypes.Supervisor  // Checks cross-chain executing messages (Optimism interop)
 
 	// DB interfaces
 	chainDb ethdb.Database // Block chain database
@@ -114,6 +121,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if !config.SyncMode.IsValid() {
 		return nil, fmt.Errorf("invalid sync mode %d", config.SyncMode)
 	}
+	if err := config.Miner.Ordering.Validate(); err != nil {
+		return nil, err
+	}
 	if config.Miner.GasPrice == nil || config.Miner.GasPrice.Sign() <= 0 {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", ethconfig.Defaults.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(ethconfig.Defaults.Miner.GasPrice)
@@ -243,6 +253,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		overrides.OverrideOptimismInterop = config.OverrideOptimismInterop
 	}
 	overrides.ApplySuperchainUpgrades = config.ApplySuperchainUpgrades
+	overrides.SuperchainRegistry = config.SuperchainRegistry
 
 	// TODO (MariusVanDerWijden) get rid of shouldPreserve in a follow-up PR
 	shouldPreserve := func(header *types.Header) bool {
@@ -267,17 +278,36 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	if config.RollupPayloadJournal != "" {
+		config.RollupPayloadJournal = stack.ResolvePath(config.RollupPayloadJournal)
+	}
 	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
 
 	txPools := []txpool.SubPool{legacyPool}
-	if !eth.BlockChain().Config().IsOptimism() {
+	if eth.BlockChain().Config().HasBlobTxs() {
 		blobPool := blobpool.New(config.BlobPool, eth.blockchain)
 		txPools = append(txPools, blobPool)
 	}
+	eth.bundlePool = bundlepool.New(config.BundlePool, eth.blockchain)
+	txPools = append(txPools, eth.bundlePool)
+
 	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, txPools)
 	if err != nil {
 		return nil, err
 	}
+	eth.txPool.SetOriginRateLimit(config.TxPoolOriginRate, config.TxPoolOriginBurst)
+	if config.InteropMessageRPC != "" {
+		eth.interopRPC = interop.NewClient(config.InteropMessageRPC)
+		eth.supervisor = eth.interopRPC
+	}
+	if eth.blockchain.Config().InteropTime != nil {
+		eth.txPool.SetInteropValidation(&txpool.InteropValidationOptions{
+			Config:     eth.blockchain.Config(),
+			SimLogs:    eth.SimLogs,
+			Supervisor: eth,
+			MinSafety:  interoptypes.CrossUnsafe,
+		})
+	}
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
 	if eth.handler, err = newHandler(&handlerConfig{
@@ -316,13 +346,14 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 
 	if config.RollupSequencerHTTP != "" {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		client, err := rpc.DialContext(ctx, config.RollupSequencerHTTP)
-		cancel()
+		fwdConfig := forwarder.DefaultConfig
+		fwdConfig.Attempts = config.RollupSequencerRetries + 1
+		fwdConfig.HealthCheckInterval = config.RollupSequencerHealthCheckInterval
+		fwd, err := forwarder.New(forwarder.SplitURLs(config.RollupSequencerHTTP), fwdConfig)
 		if err != nil {
 			return nil, err
 		}
-		eth.seqRPCService = client
+		eth.seqForwarder = fwd
 	}
 
 	if config.RollupHistoricalRPC != "" {
@@ -334,6 +365,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		}
 		eth.historicalRPCService = client
 	}
+	eth.historicalRouter = ethapi.NewHistoricalRouter(eth.historicalRPCService, eth.blockchain.Config())
 
 	// Start the RPC service
 	eth.netRPCService = ethapi.NewNetAPI(eth.p2pServer, networkID)
@@ -404,6 +436,7 @@ func (s *Ethereum) Miner() *miner.Miner { return s.miner }
 func (s *Ethereum) AccountManager() *accounts.Manager  { return s.accountManager }
 func (s *Ethereum) BlockChain() *core.BlockChain       { return s.blockchain }
 func (s *Ethereum) TxPool() *txpool.TxPool             { return s.txPool }
+func (s *Ethereum) BundlePool() *bundlepool.BundlePool { return s.bundlePool }
 func (s *Ethereum) EventMux() *event.TypeMux           { return s.eventMux }
 func (s *Ethereum) Engine() consensus.Engine           { return s.engine }
 func (s *Ethereum) ChainDb() ethdb.Database            { return s.chainDb }
@@ -412,6 +445,7 @@ func (s *Ethereum) Downloader() *downloader.Downloader { return s.handler.downlo
 func (s *Ethereum) Synced() bool                       { return s.handler.synced.Load() }
 func (s *Ethereum) SetSynced()                         { s.handler.enableSyncedFeatures() }
 func (s *Ethereum) ArchiveMode() bool                  { return s.config.NoPruning }
+func (s *Ethereum) PayloadJournal() string             { return s.config.RollupPayloadJournal }
 func (s *Ethereum) BloomIndexer() *core.ChainIndexer   { return s.bloomIndexer }
 
 // Protocols returns all the currently configured
@@ -462,12 +496,15 @@ func (s *Ethereum) Stop() error {
 	s.txPool.Close()
 	s.blockchain.Stop()
 	s.engine.Close()
-	if s.seqRPCService != nil {
-		s.seqRPCService.Close()
+	if s.seqForwarder != nil {
+		s.seqForwarder.Close()
 	}
 	if s.historicalRPCService != nil {
 		s.historicalRPCService.Close()
 	}
+	if s.interopRPC != nil {
+		s.interopRPC.Close()
+	}
 
 	// Clean shutdown marker as the last thing before closing db
 	s.shutdownTracker.Stop()
@@ -27,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth"
@@ -100,6 +101,8 @@ type ConsensusAPI struct {
 	remoteBlocks *headerQueue  // Cache of remote payloads received
 	localBlocks  *payloadQueue // Cache of local payloads generated
 
+	journal *payloadJournal // Optional journal of the local payloads, to survive restarts (Optimism)
+
 	// The forkchoice update and new payload method require us to return the
 	// latest valid hash in an invalid chain. To support that return, we need
 	// to track historical bad blocks as well as bad tipsets in case a chain
@@ -158,9 +161,62 @@ func newConsensusAPIWithoutHeartbeat(eth *eth.Ethereum) *ConsensusAPI {
 		invalidTipsets:    make(map[common.Hash]*types.Header),
 	}
 	eth.Downloader().SetBadBlockCallback(api.setInvalidAncestor)
+
+	if path := eth.PayloadJournal(); path != "" {
+		api.journal = newPayloadJournal(path)
+		api.restorePayloads()
+	}
 	return api
 }
 
+// restorePayloads restores the payloads journaled before a restart, for them to
+// be retrievable with the same ID. Payloads with a full block built are served
+// as is, the others are built again.
+func (api *ConsensusAPI) restorePayloads() {
+	journaled, err := api.journal.load()
+	if err != nil {
+		log.Warn("Failed to load payload journal", "err", err)
+		return
+	}
+	for _, item := range journaled {
+		args, err := item.Args.buildArgs()
+		if err != nil {
+			log.Warn("Failed to decode journaled payload", "id", item.ID, "err", err)
+			continue
+		}
+		if id := args.Id(); id != item.ID {
+			log.Warn("Journaled payload ID mismatch", "id", item.ID, "args", id)
+			continue
+		}
+		if api.eth.BlockChain().GetBlockByHash(args.Parent) == nil {
+			log.Debug("Dropping journaled payload with unknown parent", "id", item.ID, "parent", args.Parent)
+			continue
+		}
+		var payload *miner.Payload
+		if item.Envelope != nil {
+			payload, err = api.eth.Miner().RestorePayload(item.ID, item.Envelope)
+		} else {
+			payload, err = api.eth.Miner().BuildPayload(args)
+		}
+		if err != nil {
+			log.Warn("Failed to restore journaled payload", "id", item.ID, "err", err)
+			continue
+		}
+		if item.Envelope == nil {
+			api.journalUpdates(item.ID, payload)
+		}
+		api.localBlocks.put(item.ID, payload)
+		log.Info("Restored journaled payload", "id", item.ID, "parent", args.Parent, "built", item.Envelope != nil)
+	}
+}
+
+// journalUpdates journals the full blocks built for a payload.
+func (api *ConsensusAPI) journalUpdates(id engine.PayloadID, payload *miner.Payload) {
+	payload.SetUpdateCallback(func(envelope *engine.ExecutionPayloadEnvelope) {
+		api.journal.update(id, envelope)
+	})
+}
+
 // ForkchoiceUpdatedV1 has several responsibilities:
 //
 // We try to set our blockchain to the headBlock.
@@ -362,6 +418,13 @@ func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payl
 		if api.eth.BlockChain().Config().Optimism != nil && payloadAttributes.GasLimit == nil {
 			return engine.STATUS_INVALID, engine.InvalidPayloadAttributes.With(errors.New("gasLimit parameter is required"))
 		}
+		if api.eth.BlockChain().Config().IsOptimismHolocene(payloadAttributes.Timestamp) {
+			if err := eip1559.ValidateHolocene1559Params(payloadAttributes.EIP1559Params); err != nil {
+				return engine.STATUS_INVALID, engine.InvalidPayloadAttributes.With(err)
+			}
+		} else if payloadAttributes.EIP1559Params != nil {
+			return engine.STATUS_INVALID, engine.InvalidPayloadAttributes.With(errors.New("eip1559Params not supported prior to Holocene upgrade"))
+		}
 		transactions := make(types.Transactions, 0, len(payloadAttributes.Transactions))
 		for i, otx := range payloadAttributes.Transactions {
 			var tx types.Transaction
@@ -371,16 +434,17 @@ func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payl
 			transactions = append(transactions, &tx)
 		}
 		args := &miner.BuildPayloadArgs{
-			Parent:       update.HeadBlockHash,
-			Timestamp:    payloadAttributes.Timestamp,
-			FeeRecipient: payloadAttributes.SuggestedFeeRecipient,
-			Random:       payloadAttributes.Random,
-			Withdrawals:  payloadAttributes.Withdrawals,
-			BeaconRoot:   payloadAttributes.BeaconRoot,
-			NoTxPool:     payloadAttributes.NoTxPool,
-			Transactions: transactions,
-			GasLimit:     payloadAttributes.GasLimit,
-			Version:      payloadVersion,
+			Parent:        update.HeadBlockHash,
+			Timestamp:     payloadAttributes.Timestamp,
+			FeeRecipient:  payloadAttributes.SuggestedFeeRecipient,
+			Random:        payloadAttributes.Random,
+			Withdrawals:   payloadAttributes.Withdrawals,
+			BeaconRoot:    payloadAttributes.BeaconRoot,
+			NoTxPool:      payloadAttributes.NoTxPool,
+			Transactions:  transactions,
+			GasLimit:      payloadAttributes.GasLimit,
+			EIP1559Params: payloadAttributes.EIP1559Params,
+			Version:       payloadVersion,
 		}
 		id := args.Id()
 		// If we already are busy generating this work, then we do not need
@@ -407,6 +471,12 @@ func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payl
 			return valid(nil), engine.InvalidPayloadAttributes.With(err)
 		}
 		api.localBlocks.put(id, payload)
+		if api.journal != nil {
+			if err := api.journal.put(id, args); err != nil {
+				log.Warn("Failed to journal payload", "id", id, "err", err)
+			}
+			api.journalUpdates(id, payload)
+		}
 		return valid(&id), nil
 	}
 	return valid(nil), nil
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"math/big"
 	"math/rand"
+	"path/filepath"
 	"reflect"
 	"sync"
 	"testing"
@@ -1704,3 +1705,83 @@ func TestGetClientVersion(t *testing.T) {
 		t.Fatalf("client info does match expected, got %s", info.String())
 	}
 }
+
+// TestPayloadJournal checks that the journaled payloads can be retrieved after a
+// restart, either served as built before or built again.
+func TestPayloadJournal(t *testing.T) {
+	genesis, blocks := generateMergeChain(10, false)
+	genesis.Config.TerminalTotalDifficulty.Sub(genesis.Config.TerminalTotalDifficulty, blocks[9].Difficulty())
+
+	mcfg := miner.DefaultConfig
+	mcfg.PendingFeeRecipient = testAddr
+	ethcfg := &ethconfig.Config{Genesis: genesis, SyncMode: downloader.FullSync, TrieTimeout: time.Minute, TrieDirtyCache: 256, TrieCleanCache: 256, Miner: mcfg}
+	ethcfg.RollupPayloadJournal = filepath.Join(t.TempDir(), "payloads.json")
+	n, ethservice := startEthServiceWithConfigFn(t, blocks[:9], ethcfg)
+	defer n.Close()
+
+	api := newConsensusAPIWithoutHeartbeat(ethservice)
+	ethservice.TxPool().Add(blocks[9].Transactions(), true, true)
+
+	fcState := engine.ForkchoiceStateV1{HeadBlockHash: blocks[8].Hash()}
+	resp, err := api.ForkchoiceUpdatedV1(fcState, &engine.PayloadAttributes{Timestamp: blocks[8].Time() + 5})
+	if err != nil {
+		t.Fatalf("error preparing payload, err=%v", err)
+	}
+	id := *resp.PayloadID
+	require.NoError(t, waitForApiPayloadToBuild(api, id))
+	built, err := api.GetPayloadV1(id)
+	if err != nil {
+		t.Fatalf("error getting payload, err=%v", err)
+	}
+	waitForJournaledPayload(t, api.journal, id, built.BlockHash)
+
+	// Restart the engine API, the built payload is served from the journal.
+	restarted := newConsensusAPIWithoutHeartbeat(ethservice)
+	restored, err := restarted.GetPayloadV1(id)
+	if err != nil {
+		t.Fatalf("error getting restored payload, err=%v", err)
+	}
+	if restored.BlockHash != built.BlockHash || len(restored.Transactions) != len(built.Transactions) {
+		t.Fatalf("restored payload mismatch: have %x, want %x", restored.BlockHash, built.BlockHash)
+	}
+	// Journal a payload without any block built, it is built again on restart.
+	args := &miner.BuildPayloadArgs{
+		Parent:    blocks[8].Hash(),
+		Timestamp: blocks[8].Time() + 10,
+		Version:   engine.PayloadV1,
+	}
+	if err := restarted.journal.put(args.Id(), args); err != nil {
+		t.Fatalf("failed to journal payload: %v", err)
+	}
+	restarted.journal.written.Wait()
+	rebuilt, err := newConsensusAPIWithoutHeartbeat(ethservice).GetPayloadV1(args.Id())
+	if err != nil {
+		t.Fatalf("error getting rebuilt payload, err=%v", err)
+	}
+	if rebuilt.Timestamp != args.Timestamp || rebuilt.ParentHash != args.Parent {
+		t.Fatalf("rebuilt payload mismatch: timestamp %d, parent %x", rebuilt.Timestamp, rebuilt.ParentHash)
+	}
+}
+
+// waitForJournaledPayload waits until the journal has written the given full block
+// built for a payload to disk.
+func waitForJournaledPayload(t *testing.T, j *payloadJournal, id engine.PayloadID, hash common.Hash) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		j.lock.Lock()
+		var built bool
+		for _, payload := range j.payloads {
+			if payload.ID == id && payload.Envelope != nil && payload.Envelope.ExecutionPayload.BlockHash == hash {
+				built = true
+			}
+		}
+		j.lock.Unlock()
+
+		if built {
+			j.written.Wait()
+			return
+		}
+	}
+	t.Fatalf("full block %x of payload %v not journaled", hash, id)
+}
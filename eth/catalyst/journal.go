@@ -0,0 +1,230 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package catalyst
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/miner"
+)
+
+// journaledArgs is the journaled form of the arguments a payload is built with.
+type journaledArgs struct {
+	Parent        common.Hash           `json:"parent"`
+	Timestamp     uint64                `json:"timestamp"`
+	FeeRecipient  common.Address        `json:"feeRecipient"`
+	Random        common.Hash           `json:"random"`
+	Withdrawals   types.Withdrawals     `json:"withdrawals"`
+	BeaconRoot    *common.Hash          `json:"beaconRoot,omitempty"`
+	Version       engine.PayloadVersion `json:"version"`
+	NoTxPool      bool                  `json:"noTxPool,omitempty"`
+	Transactions  []hexutil.Bytes       `json:"transactions,omitempty"`
+	GasLimit      *uint64               `json:"gasLimit,omitempty"`
+	EIP1559Params hexutil.Bytes         `json:"eip1559Params,omitempty"`
+}
+
+// journaledPayload is an in-flight payload, with the envelope of the latest full
+// block built for it, if any.
+type journaledPayload struct {
+	ID       engine.PayloadID                 `json:"id"`
+	Args     journaledArgs                    `json:"args"`
+	Envelope *engine.ExecutionPayloadEnvelope `json:"envelope,omitempty"`
+}
+
+// payloadJournal persists the latest handful of in-flight payloads to disk, so
+// that they can still be retrieved after a restart of the node. The journal is
+// written in the background, coalescing the changes made meanwhile, to keep the
+// disk access off the payload building path.
+type payloadJournal struct {
+	path     string
+	payloads []*journaledPayload // Newest first, as in the payload queue
+	dirty    bool                // Whether the payloads changed since the last write started
+	writing  bool                // Whether a background write is in progress
+	written  sync.WaitGroup      // Tracks the background writes, to wait for them in tests
+	lock     sync.Mutex
+}
+
+// newPayloadJournal creates a journal backed by the file at the given path.
+func newPayloadJournal(path string) *payloadJournal {
+	return &payloadJournal{path: path}
+}
+
+// load reads the payloads of the journal, oldest first. A missing journal is
+// not an error.
+func (j *payloadJournal) load() ([]*journaledPayload, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var payloads []*journaledPayload
+	if err := json.Unmarshal(data, &payloads); err != nil {
+		return nil, err
+	}
+	if len(payloads) > maxTrackedPayloads {
+		payloads = payloads[:maxTrackedPayloads]
+	}
+	j.payloads = payloads
+
+	oldest := make([]*journaledPayload, len(payloads))
+	for i, payload := range payloads {
+		oldest[len(payloads)-1-i] = payload
+	}
+	return oldest, nil
+}
+
+// put journals a new payload with the arguments it is built with.
+func (j *payloadJournal) put(id engine.PayloadID, args *miner.BuildPayloadArgs) error {
+	payload := &journaledPayload{
+		ID: id,
+		Args: journaledArgs{
+			Parent:        args.Parent,
+			Timestamp:     args.Timestamp,
+			FeeRecipient:  args.FeeRecipient,
+			Random:        args.Random,
+			Withdrawals:   args.Withdrawals,
+			BeaconRoot:    args.BeaconRoot,
+			Version:       args.Version,
+			NoTxPool:      args.NoTxPool,
+			GasLimit:      args.GasLimit,
+			EIP1559Params: args.EIP1559Params,
+		},
+	}
+	for _, tx := range args.Transactions {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		payload.Args.Transactions = append(payload.Args.Transactions, data)
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.payloads = append([]*journaledPayload{payload}, j.payloads...)
+	if len(j.payloads) > maxTrackedPayloads {
+		j.payloads = j.payloads[:maxTrackedPayloads]
+	}
+	j.schedule()
+	return nil
+}
+
+// update journals the envelope of the latest full block built for a payload. An
+// envelope of lower value than the journaled one is stale and ignored.
+func (j *payloadJournal) update(id engine.PayloadID, envelope *engine.ExecutionPayloadEnvelope) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	for _, payload := range j.payloads {
+		if payload.ID == id {
+			if payload.Envelope != nil && payload.Envelope.BlockValue.Cmp(envelope.BlockValue) > 0 {
+				return
+			}
+			payload.Envelope = envelope
+			j.schedule()
+			return
+		}
+	}
+	// evicted meanwhile
+}
+
+// schedule marks the journal as changed, starting a background write unless one
+// is in progress already, which then writes the changes once done. It must be
+// called with the lock held.
+func (j *payloadJournal) schedule() {
+	j.dirty = true
+	if j.writing {
+		return
+	}
+	j.writing = true
+	j.written.Add(1)
+	go j.loop()
+}
+
+// loop writes the journal to disk until there are no more changes to write.
+func (j *payloadJournal) loop() {
+	defer j.written.Done()
+
+	j.lock.Lock()
+	for j.dirty {
+		j.dirty = false
+
+		// Copy the payloads, the envelopes are replaced but never modified
+		payloads := make([]journaledPayload, len(j.payloads))
+		for i, payload := range j.payloads {
+			payloads[i] = *payload
+		}
+		j.lock.Unlock()
+
+		if err := j.write(payloads); err != nil {
+			log.Warn("Failed to write payload journal", "err", err)
+		}
+		j.lock.Lock()
+	}
+	j.writing = false
+	j.lock.Unlock()
+}
+
+// write atomically replaces the journal on disk with the given payloads.
+func (j *payloadJournal) write(payloads []journaledPayload) error {
+	data, err := json.Marshal(payloads)
+	if err != nil {
+		return err
+	}
+	tmp := j.path + ".new"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path)
+}
+
+// buildArgs converts journaled arguments back to payload building arguments.
+func (args *journaledArgs) buildArgs() (*miner.BuildPayloadArgs, error) {
+	txs := make([]*types.Transaction, len(args.Transactions))
+	for i, data := range args.Transactions {
+		txs[i] = new(types.Transaction)
+		if err := txs[i].UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+	}
+	return &miner.BuildPayloadArgs{
+		Parent:        args.Parent,
+		Timestamp:     args.Timestamp,
+		FeeRecipient:  args.FeeRecipient,
+		Random:        args.Random,
+		Withdrawals:   args.Withdrawals,
+		BeaconRoot:    args.BeaconRoot,
+		Version:       args.Version,
+		NoTxPool:      args.NoTxPool,
+		Transactions:  txs,
+		GasLimit:      args.GasLimit,
+		EIP1559Params: args.EIP1559Params,
+	}, nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
@@ -64,10 +65,15 @@ var Defaults = Config{
 	Miner:              miner.DefaultConfig,
 	TxPool:             legacypool.DefaultConfig,
 	BlobPool:           blobpool.DefaultConfig,
+	BundlePool:         bundlepool.DefaultConfig,
 	RPCGasCap:          50000000,
 	RPCEVMTimeout:      5 * time.Second,
 	GPO:                FullNodeGPO,
 	RPCTxFeeCap:        1, // 1 ether
+
+	RollupSequencerRetries:             2,
+	RollupSequencerHealthCheckInterval: 10 * time.Second,
+	RollupTxConditionalMaxCost:         1000,
 }
 
 //go:generate go run github.com/fjl/gencodec -type Config -formats toml -out gen_config.go
@@ -133,8 +139,15 @@ type Config struct {
 	Miner miner.Config
 
 	// Transaction pool options
-	TxPool   legacypool.Config
-	BlobPool blobpool.Config
+	TxPool     legacypool.Config
+	BlobPool   blobpool.Config
+	BundlePool bundlepool.Config
+
+	// Admission rate limit of the transactions submitted per RPC client. Clients
+	// are identified by the address of their TCP connection, unless it is one of
+	// the trusted reverse proxies of the node's RPC origin header.
+	TxPoolOriginRate  float64 // Maximum number of transactions admitted per second and origin (0 = unlimited)
+	TxPoolOriginBurst int     // Maximum number of transactions admitted at once per origin, above the rate
 
 	// Gas Price Oracle options
 	GPO gasprice.Config
@@ -183,12 +196,25 @@ type Config struct {
 	// ApplySuperchainUpgrades requests the node to load chain-configuration from the superchain-registry.
 	ApplySuperchainUpgrades bool `toml:",omitempty"`
 
-	RollupSequencerHTTP                     string
+	// SuperchainRegistry is the local superchain-registry the chain-configuration is
+	// loaded from, on top of the embedded one. Nil to use the embedded one only.
+	SuperchainRegistry *params.SuperchainRegistry `toml:"-"`
+
+	RollupSequencerHTTP                     string        // Comma separated sequencer endpoints, in order of preference
+	RollupSequencerRetries                  int           // Number of times a failed forward to the sequencer is retried
+	RollupSequencerHealthCheckInterval      time.Duration // Interval between sequencer endpoint health checks
 	RollupHistoricalRPC                     string
 	RollupHistoricalRPCTimeout              time.Duration
 	RollupDisableTxPoolGossip               bool
 	RollupDisableTxPoolAdmission            bool
 	RollupHaltOnIncompatibleProtocolVersion string
+	RollupPayloadJournal                    string `toml:",omitempty"` // Journal of the in-flight payloads, to retrieve them after a restart
+	RollupTxConditionalEnabled              bool   // Whether transactions with preconditions are accepted over RPC
+	RollupTxConditionalMaxCost              uint64 // Maximum number of checks the preconditions of a transaction may require
+
+	// InteropMessageRPC is the RPC endpoint of the interop supervisor, used to
+	// check cross-chain executing messages in the tx-pool and block building.
+	InteropMessageRPC string
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
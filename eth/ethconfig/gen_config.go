@@ -8,10 +8,12 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // MarshalTOML marshals as TOML.
@@ -48,6 +50,9 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		Miner                                   miner.Config
 		TxPool                                  legacypool.Config
 		BlobPool                                blobpool.Config
+		BundlePool                              bundlepool.Config
+		TxPoolOriginRate                        float64
+		TxPoolOriginBurst                       int
 		GPO                                     gasprice.Config
 		EnablePreimageRecording                 bool
 		EnableWitnessCollection                 bool `toml:"-"`
@@ -57,21 +62,28 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		RPCGasCap                               uint64
 		RPCEVMTimeout                           time.Duration
 		RPCTxFeeCap                             float64
-		OverrideCancun                          *uint64 `toml:",omitempty"`
-		OverrideVerkle                          *uint64 `toml:",omitempty"`
-		OverrideOptimismCanyon                  *uint64 `toml:",omitempty"`
-		OverrideOptimismEcotone                 *uint64 `toml:",omitempty"`
-		OverrideOptimismFjord                   *uint64 `toml:",omitempty"`
-		OverrideOptimismGranite                 *uint64 `toml:",omitempty"`
-		OverrideOptimismHolocene                *uint64 `toml:",omitempty"`
-		OverrideOptimismInterop                 *uint64 `toml:",omitempty"`
-		ApplySuperchainUpgrades                 bool    `toml:",omitempty"`
+		OverrideCancun                          *uint64                    `toml:",omitempty"`
+		OverrideVerkle                          *uint64                    `toml:",omitempty"`
+		OverrideOptimismCanyon                  *uint64                    `toml:",omitempty"`
+		OverrideOptimismEcotone                 *uint64                    `toml:",omitempty"`
+		OverrideOptimismFjord                   *uint64                    `toml:",omitempty"`
+		OverrideOptimismGranite                 *uint64                    `toml:",omitempty"`
+		OverrideOptimismHolocene                *uint64                    `toml:",omitempty"`
+		OverrideOptimismInterop                 *uint64                    `toml:",omitempty"`
+		ApplySuperchainUpgrades                 bool                       `toml:",omitempty"`
+		SuperchainRegistry                      *params.SuperchainRegistry `toml:"-"`
 		RollupSequencerHTTP                     string
+		RollupSequencerRetries                  int
+		RollupSequencerHealthCheckInterval      time.Duration
 		RollupHistoricalRPC                     string
 		RollupHistoricalRPCTimeout              time.Duration
 		RollupDisableTxPoolGossip               bool
 		RollupDisableTxPoolAdmission            bool
 		RollupHaltOnIncompatibleProtocolVersion string
+		RollupPayloadJournal                    string `toml:",omitempty"`
+		RollupTxConditionalEnabled              bool
+		RollupTxConditionalMaxCost              uint64
+		InteropMessageRPC                       string
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -105,6 +117,9 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.Miner = c.Miner
 	enc.TxPool = c.TxPool
 	enc.BlobPool = c.BlobPool
+	enc.BundlePool = c.BundlePool
+	enc.TxPoolOriginRate = c.TxPoolOriginRate
+	enc.TxPoolOriginBurst = c.TxPoolOriginBurst
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
 	enc.EnableWitnessCollection = c.EnableWitnessCollection
@@ -123,12 +138,19 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.OverrideOptimismHolocene = c.OverrideOptimismHolocene
 	enc.OverrideOptimismInterop = c.OverrideOptimismInterop
 	enc.ApplySuperchainUpgrades = c.ApplySuperchainUpgrades
+	enc.SuperchainRegistry = c.SuperchainRegistry
 	enc.RollupSequencerHTTP = c.RollupSequencerHTTP
+	enc.RollupSequencerRetries = c.RollupSequencerRetries
+	enc.RollupSequencerHealthCheckInterval = c.RollupSequencerHealthCheckInterval
 	enc.RollupHistoricalRPC = c.RollupHistoricalRPC
 	enc.RollupHistoricalRPCTimeout = c.RollupHistoricalRPCTimeout
 	enc.RollupDisableTxPoolGossip = c.RollupDisableTxPoolGossip
 	enc.RollupDisableTxPoolAdmission = c.RollupDisableTxPoolAdmission
 	enc.RollupHaltOnIncompatibleProtocolVersion = c.RollupHaltOnIncompatibleProtocolVersion
+	enc.RollupPayloadJournal = c.RollupPayloadJournal
+	enc.RollupTxConditionalEnabled = c.RollupTxConditionalEnabled
+	enc.RollupTxConditionalMaxCost = c.RollupTxConditionalMaxCost
+	enc.InteropMessageRPC = c.InteropMessageRPC
 	return &enc, nil
 }
 
@@ -166,6 +188,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		Miner                                   *miner.Config
 		TxPool                                  *legacypool.Config
 		BlobPool                                *blobpool.Config
+		BundlePool                              *bundlepool.Config
+		TxPoolOriginRate                        *float64
+		TxPoolOriginBurst                       *int
 		GPO                                     *gasprice.Config
 		EnablePreimageRecording                 *bool
 		EnableWitnessCollection                 *bool `toml:"-"`
@@ -175,21 +200,28 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		RPCGasCap                               *uint64
 		RPCEVMTimeout                           *time.Duration
 		RPCTxFeeCap                             *float64
-		OverrideCancun                          *uint64 `toml:",omitempty"`
-		OverrideVerkle                          *uint64 `toml:",omitempty"`
-		OverrideOptimismCanyon                  *uint64 `toml:",omitempty"`
-		OverrideOptimismEcotone                 *uint64 `toml:",omitempty"`
-		OverrideOptimismFjord                   *uint64 `toml:",omitempty"`
-		OverrideOptimismGranite                 *uint64 `toml:",omitempty"`
-		OverrideOptimismHolocene                *uint64 `toml:",omitempty"`
-		OverrideOptimismInterop                 *uint64 `toml:",omitempty"`
-		ApplySuperchainUpgrades                 *bool   `toml:",omitempty"`
+		OverrideCancun                          *uint64                    `toml:",omitempty"`
+		OverrideVerkle                          *uint64                    `toml:",omitempty"`
+		OverrideOptimismCanyon                  *uint64                    `toml:",omitempty"`
+		OverrideOptimismEcotone                 *uint64                    `toml:",omitempty"`
+		OverrideOptimismFjord                   *uint64                    `toml:",omitempty"`
+		OverrideOptimismGranite                 *uint64                    `toml:",omitempty"`
+		OverrideOptimismHolocene                *uint64                    `toml:",omitempty"`
+		OverrideOptimismInterop                 *uint64                    `toml:",omitempty"`
+		ApplySuperchainUpgrades                 *bool                      `toml:",omitempty"`
+		SuperchainRegistry                      *params.SuperchainRegistry `toml:"-"`
 		RollupSequencerHTTP                     *string
+		RollupSequencerRetries                  *int
+		RollupSequencerHealthCheckInterval      *time.Duration
 		RollupHistoricalRPC                     *string
 		RollupHistoricalRPCTimeout              *time.Duration
 		RollupDisableTxPoolGossip               *bool
 		RollupDisableTxPoolAdmission            *bool
 		RollupHaltOnIncompatibleProtocolVersion *string
+		RollupPayloadJournal                    *string `toml:",omitempty"`
+		RollupTxConditionalEnabled              *bool
+		RollupTxConditionalMaxCost              *uint64
+		InteropMessageRPC                       *string
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -288,6 +320,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.BlobPool != nil {
 		c.BlobPool = *dec.BlobPool
 	}
+	if dec.BundlePool != nil {
+		c.BundlePool = *dec.BundlePool
+	}
+	if dec.TxPoolOriginRate != nil {
+		c.TxPoolOriginRate = *dec.TxPoolOriginRate
+	}
+	if dec.TxPoolOriginBurst != nil {
+		c.TxPoolOriginBurst = *dec.TxPoolOriginBurst
+	}
 	if dec.GPO != nil {
 		c.GPO = *dec.GPO
 	}
@@ -342,9 +383,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.ApplySuperchainUpgrades != nil {
 		c.ApplySuperchainUpgrades = *dec.ApplySuperchainUpgrades
 	}
+	if dec.SuperchainRegistry != nil {
+		c.SuperchainRegistry = dec.SuperchainRegistry
+	}
 	if dec.RollupSequencerHTTP != nil {
 		c.RollupSequencerHTTP = *dec.RollupSequencerHTTP
 	}
+	if dec.RollupSequencerRetries != nil {
+		c.RollupSequencerRetries = *dec.RollupSequencerRetries
+	}
+	if dec.RollupSequencerHealthCheckInterval != nil {
+		c.RollupSequencerHealthCheckInterval = *dec.RollupSequencerHealthCheckInterval
+	}
 	if dec.RollupHistoricalRPC != nil {
 		c.RollupHistoricalRPC = *dec.RollupHistoricalRPC
 	}
@@ -360,5 +410,17 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RollupHaltOnIncompatibleProtocolVersion != nil {
 		c.RollupHaltOnIncompatibleProtocolVersion = *dec.RollupHaltOnIncompatibleProtocolVersion
 	}
+	if dec.RollupPayloadJournal != nil {
+		c.RollupPayloadJournal = *dec.RollupPayloadJournal
+	}
+	if dec.RollupTxConditionalEnabled != nil {
+		c.RollupTxConditionalEnabled = *dec.RollupTxConditionalEnabled
+	}
+	if dec.RollupTxConditionalMaxCost != nil {
+		c.RollupTxConditionalMaxCost = *dec.RollupTxConditionalMaxCost
+	}
+	if dec.InteropMessageRPC != nil {
+		c.InteropMessageRPC = *dec.InteropMessageRPC
+	}
 	return nil
 }
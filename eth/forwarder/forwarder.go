@@ -0,0 +1,291 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package forwarder implements the forwarding of transactions from a replica
+// node to the sequencer, over a set of redundant endpoints.
+package forwarder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	forwardTimer    = metrics.NewRegisteredTimer("rollup/sequencer/forward/time", nil)
+	forwardErrMeter = metrics.NewRegisteredMeter("rollup/sequencer/forward/errors", nil)
+	retryMeter      = metrics.NewRegisteredMeter("rollup/sequencer/forward/retries", nil)
+	failoverMeter   = metrics.NewRegisteredMeter("rollup/sequencer/forward/failovers", nil)
+	healthyGauge    = metrics.NewRegisteredGauge("rollup/sequencer/healthy", nil)
+)
+
+// Config contains the settings of the forwarder.
+type Config struct {
+	Attempts            int           // Maximum number of attempts to forward a request, across all endpoints
+	RetryBackoff        time.Duration // Delay before retrying a failed request
+	HealthCheckInterval time.Duration // Interval between endpoint health checks, zero disables them
+	HealthCheckTimeout  time.Duration // Timeout of a single health check
+	DialTimeout         time.Duration // Timeout for dialing an endpoint
+}
+
+// DefaultConfig contains the default settings of the forwarder.
+var DefaultConfig = Config{
+	Attempts:            3,
+	RetryBackoff:        100 * time.Millisecond,
+	HealthCheckInterval: 10 * time.Second,
+	HealthCheckTimeout:  2 * time.Second,
+	DialTimeout:         5 * time.Second,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) sanitize() Config {
+	conf := *config
+	if conf.Attempts < 1 {
+		log.Warn("Sanitizing invalid sequencer forwarding attempts", "provided", conf.Attempts, "updated", DefaultConfig.Attempts)
+		conf.Attempts = DefaultConfig.Attempts
+	}
+	if conf.RetryBackoff < 0 {
+		log.Warn("Sanitizing invalid sequencer retry backoff", "provided", conf.RetryBackoff, "updated", DefaultConfig.RetryBackoff)
+		conf.RetryBackoff = DefaultConfig.RetryBackoff
+	}
+	if conf.HealthCheckInterval < 0 {
+		log.Warn("Sanitizing invalid sequencer health check interval", "provided", conf.HealthCheckInterval, "updated", DefaultConfig.HealthCheckInterval)
+		conf.HealthCheckInterval = DefaultConfig.HealthCheckInterval
+	}
+	if conf.HealthCheckTimeout <= 0 {
+		conf.HealthCheckTimeout = DefaultConfig.HealthCheckTimeout
+	}
+	if conf.DialTimeout <= 0 {
+		conf.DialTimeout = DefaultConfig.DialTimeout
+	}
+	return conf
+}
+
+// endpoint is a single sequencer RPC endpoint.
+type endpoint struct {
+	url     string
+	client  *rpc.Client
+	healthy atomic.Bool
+}
+
+// setHealthy updates the health of the endpoint, logging any change.
+func (ep *endpoint) setHealthy(healthy bool, err error) {
+	if ep.healthy.Swap(healthy) == healthy {
+		return
+	}
+	if healthy {
+		log.Info("Sequencer endpoint recovered", "url", ep.url)
+	} else {
+		log.Warn("Sequencer endpoint unhealthy", "url", ep.url, "err", err)
+	}
+}
+
+// Forwarder forwards requests to the first healthy of a list of sequencer
+// endpoints, in the configured order of preference. Requests that fail with a
+// transport error are retried on the next endpoint, up to a bounded number of
+// attempts. Errors returned by the sequencer itself are final.
+type Forwarder struct {
+	config    Config
+	endpoints []*endpoint
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New dials the given sequencer endpoints and starts checking their health.
+func New(urls []string, config Config) (*Forwarder, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no sequencer endpoints")
+	}
+	f := &Forwarder{
+		config:  config.sanitize(),
+		closeCh: make(chan struct{}),
+	}
+	for _, url := range urls {
+		ctx, cancel := context.WithTimeout(context.Background(), f.config.DialTimeout)
+		client, err := rpc.DialContext(ctx, url)
+		cancel()
+		if err != nil {
+			f.closeClients()
+			return nil, fmt.Errorf("failed to dial sequencer endpoint %s: %w", url, err)
+		}
+		ep := &endpoint{url: url, client: client}
+		ep.healthy.Store(true)
+		f.endpoints = append(f.endpoints, ep)
+	}
+	healthyGauge.Update(int64(len(f.endpoints)))
+
+	if f.config.HealthCheckInterval > 0 {
+		f.wg.Add(1)
+		go f.healthLoop()
+	}
+	return f, nil
+}
+
+// SplitURLs splits a comma separated list of endpoints.
+func SplitURLs(urls string) []string {
+	var res []string
+	for _, url := range strings.Split(urls, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			res = append(res, url)
+		}
+	}
+	return res
+}
+
+// Close stops the health checks and closes the connections to the endpoints.
+func (f *Forwarder) Close() {
+	close(f.closeCh)
+	f.wg.Wait()
+	f.closeClients()
+}
+
+func (f *Forwarder) closeClients() {
+	for _, ep := range f.endpoints {
+		ep.client.Close()
+	}
+}
+
+// CallContext performs a JSON-RPC call on the sequencer, failing over to the
+// other endpoints on transport errors.
+func (f *Forwarder) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	defer func(start time.Time) { forwardTimer.UpdateSince(start) }(time.Now())
+
+	var (
+		candidates = f.candidates()
+		last       *endpoint
+		err        error
+	)
+	for attempt := 0; attempt < f.config.Attempts; attempt++ {
+		if attempt > 0 {
+			retryMeter.Mark(1)
+			select {
+			case <-time.After(f.config.RetryBackoff):
+			case <-ctx.Done():
+				forwardErrMeter.Mark(1)
+				return err
+			}
+		}
+		ep := candidates[attempt%len(candidates)]
+		if last != nil && ep != last {
+			failoverMeter.Mark(1)
+			log.Debug("Failing over to sequencer endpoint", "url", ep.url, "method", method)
+		}
+		last = ep
+
+		err = ep.client.CallContext(ctx, result, method, args...)
+		if err == nil {
+			ep.setHealthy(true, nil)
+			return nil
+		}
+		// The request may have reached the sequencer before failing, in which
+		// case a retried transaction is already known.
+		if attempt > 0 && isAlreadyKnown(err) {
+			return nil
+		}
+		if !retryable(ctx, err) {
+			break
+		}
+		ep.setHealthy(false, err)
+	}
+	forwardErrMeter.Mark(1)
+	return err
+}
+
+// candidates returns the endpoints in the order they should be tried: healthy
+// endpoints first, in order of preference.
+func (f *Forwarder) candidates() []*endpoint {
+	candidates := make([]*endpoint, 0, len(f.endpoints))
+	for _, ep := range f.endpoints {
+		if ep.healthy.Load() {
+			candidates = append(candidates, ep)
+		}
+	}
+	for _, ep := range f.endpoints {
+		if !ep.healthy.Load() {
+			candidates = append(candidates, ep)
+		}
+	}
+	return candidates
+}
+
+// retryable returns whether a failed request should be retried, i.e. whether
+// the failure is not a response of the sequencer to the request itself.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+func isAlreadyKnown(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && strings.Contains(rpcErr.Error(), txpool.ErrAlreadyKnown.Error())
+}
+
+// healthLoop periodically checks the health of all endpoints.
+func (f *Forwarder) healthLoop() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.checkHealth()
+		case <-f.closeCh:
+			return
+		}
+	}
+}
+
+// checkHealth checks whether the endpoints respond to requests.
+func (f *Forwarder) checkHealth() {
+	var healthy int64
+	for _, ep := range f.endpoints {
+		ctx, cancel := context.WithTimeout(context.Background(), f.config.HealthCheckTimeout)
+		var chainID hexutil.Big
+		err := ep.client.CallContext(ctx, &chainID, "eth_chainId")
+		cancel()
+
+		ep.setHealthy(err == nil, err)
+		if err == nil {
+			healthy++
+		}
+	}
+	healthyGauge.Update(healthy)
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package forwarder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testSequencer is a sequencer RPC endpoint counting the forwarded transactions.
+type testSequencer struct {
+	received atomic.Int32
+	err      error       // Error returned for every transaction
+	down     atomic.Bool // Whether the endpoint responds with 503
+	server   *httptest.Server
+}
+
+type testService struct{ seq *testSequencer }
+
+func (s *testService) SendRawTransaction(tx hexutil.Bytes) error {
+	s.seq.received.Add(1)
+	return s.seq.err
+}
+
+func (s *testService) ChainId() hexutil.Uint64 { return 10 }
+
+func newTestSequencer(t *testing.T, err error) *testSequencer {
+	seq := &testSequencer{err: err}
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("eth", &testService{seq}); err != nil {
+		t.Fatal(err)
+	}
+	seq.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if seq.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		srv.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		seq.server.Close()
+		srv.Stop()
+	})
+	return seq
+}
+
+func newTestForwarder(t *testing.T, seqs ...*testSequencer) *Forwarder {
+	urls := make([]string, len(seqs))
+	for i, seq := range seqs {
+		urls[i] = seq.server.URL
+	}
+	f, err := New(urls, Config{Attempts: 3, RetryBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(f.Close)
+	return f
+}
+
+func forward(f *Forwarder) error {
+	return f.CallContext(context.Background(), nil, "eth_sendRawTransaction", hexutil.Bytes{0x1})
+}
+
+func TestForwardFailover(t *testing.T) {
+	primary, backup := newTestSequencer(t, nil), newTestSequencer(t, nil)
+	f := newTestForwarder(t, primary, backup)
+
+	// The primary endpoint is preferred while it's healthy.
+	if err := forward(f); err != nil {
+		t.Fatalf("forward failed: %v", err)
+	}
+	if primary.received.Load() != 1 || backup.received.Load() != 0 {
+		t.Fatalf("wrong endpoint used: primary %d, backup %d", primary.received.Load(), backup.received.Load())
+	}
+	// Transactions fail over to the backup when the primary goes down, which is
+	// then skipped until it's found healthy again.
+	primary.down.Store(true)
+	for i := 0; i < 2; i++ {
+		if err := forward(f); err != nil {
+			t.Fatalf("forward failed: %v", err)
+		}
+	}
+	if backup.received.Load() != 2 {
+		t.Fatalf("backup received %d transactions, want 2", backup.received.Load())
+	}
+	if have, want := f.candidates(), []*endpoint{f.endpoints[1], f.endpoints[0]}; !reflect.DeepEqual(have, want) {
+		t.Fatal("unhealthy primary endpoint not deprioritized")
+	}
+	primary.down.Store(false)
+	f.checkHealth()
+	if err := forward(f); err != nil {
+		t.Fatalf("forward failed: %v", err)
+	}
+	if primary.received.Load() != 2 {
+		t.Fatalf("primary received %d transactions, want 2", primary.received.Load())
+	}
+}
+
+func TestForwardAttemptsExhausted(t *testing.T) {
+	seq := newTestSequencer(t, nil)
+	seq.down.Store(true)
+	f := newTestForwarder(t, seq)
+
+	err := forward(f)
+	var httpErr rpc.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestForwardSequencerError(t *testing.T) {
+	// Errors of the sequencer are returned as is, without retrying.
+	primary, backup := newTestSequencer(t, errors.New("nonce too low")), newTestSequencer(t, nil)
+	f := newTestForwarder(t, primary, backup)
+
+	if err := forward(f); err == nil || err.Error() != "nonce too low" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if primary.received.Load() != 1 || backup.received.Load() != 0 {
+		t.Fatalf("sequencer error retried: primary %d, backup %d", primary.received.Load(), backup.received.Load())
+	}
+	// A retried transaction already known to the sequencer was forwarded.
+	if !isAlreadyKnown(&testRPCError{txpool.ErrAlreadyKnown.Error()}) {
+		t.Fatal("already known error not detected")
+	}
+}
+
+type testRPCError struct{ msg string }
+
+func (e *testRPCError) Error() string  { return e.msg }
+func (e *testRPCError) ErrorCode() int { return -32000 }
+
+func TestSplitURLs(t *testing.T) {
+	have := SplitURLs(" http://a:8545, ,http://b:8545 ")
+	if want := []string{"http://a:8545", "http://b:8545"}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("have %v, want %v", have, want)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/types/interoptypes"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// errNoInteropSupervisor is returned when executing messages need to be checked
+// but no interop supervisor was configured.
+var errNoInteropSupervisor = errors.New("no interop supervisor configured")
+
+// SetInteropSupervisor replaces the supervisor used to check cross-chain
+// executing messages, e.g. with a local stand-in on devnets.
+func (s *Ethereum) SetInteropSupervisor(supervisor interoptypes.Supervisor) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.supervisor = supervisor
+}
+
+// CheckMessages checks the given executing messages with the configured interop
+// supervisor. It implements interoptypes.Supervisor and miner.BackendWithInterop.
+func (s *Ethereum) CheckMessages(ctx context.Context, messages []interoptypes.Message, minSafety interoptypes.SafetyLevel) error {
+	s.lock.RLock()
+	supervisor := s.supervisor
+	s.lock.RUnlock()
+
+	if supervisor == nil {
+		return errNoInteropSupervisor
+	}
+	return supervisor.CheckMessages(ctx, messages, minSafety)
+}
+
+// SimLogs simulates the given transaction on top of the current chain head and
+// returns the logs it emits. Nonce and L1-cost checks are skipped, so that
+// queued transactions can be simulated as well.
+func (s *Ethereum) SimLogs(tx *types.Transaction) ([]*types.Log, error) {
+	var (
+		config = s.blockchain.Config()
+		head   = s.blockchain.CurrentBlock()
+	)
+	header := &types.Header{
+		ParentHash: head.Hash(),
+		Coinbase:   head.Coinbase,
+		Difficulty: new(big.Int),
+		Number:     new(big.Int).Add(head.Number, common.Big1),
+		GasLimit:   head.GasLimit,
+		Time:       head.Time + 1,
+		MixDigest:  head.MixDigest,
+	}
+	if config.IsLondon(header.Number) {
+		header.BaseFee = eip1559.CalcBaseFee(config, head, header.Time)
+	}
+	state, err := s.blockchain.StateAt(head.Root)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := core.TransactionToMessage(tx, types.MakeSigner(config, header.Number, header.Time), header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	msg.SkipAccountChecks = true
+
+	state.SetTxContext(tx.Hash(), 0)
+	blockContext := core.NewEVMBlockContext(header, s.blockchain, nil, config, state)
+	evm := vm.NewEVM(blockContext, core.NewEVMTxContext(msg), state, config, vm.Config{NoBaseFee: true})
+	if _, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(header.GasLimit)); err != nil {
+		return nil, err
+	}
+	return state.GetLogs(tx.Hash(), header.Number.Uint64(), common.Hash{}), nil
+}
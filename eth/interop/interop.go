@@ -0,0 +1,130 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package interop implements supervisors checking OP-stack interop executing
+// messages, either remotely through the supervisor RPC or with a local set of
+// known messages.
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types/interoptypes"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client is a supervisor backed by the RPC endpoint of an op-supervisor.
+// The connection is established lazily on first use.
+type Client struct {
+	mu        sync.Mutex
+	endpoint  string
+	rpcClient *rpc.Client
+}
+
+var _ interoptypes.Supervisor = (*Client)(nil)
+
+// NewClient creates a supervisor client for the given RPC endpoint.
+func NewClient(endpoint string) *Client {
+	return &Client{endpoint: endpoint}
+}
+
+// client returns the RPC client, dialing the endpoint if not yet connected.
+func (cl *Client) client(ctx context.Context) (*rpc.Client, error) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if cl.rpcClient != nil {
+		return cl.rpcClient, nil
+	}
+	client, err := rpc.DialContext(ctx, cl.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial interop supervisor: %w", err)
+	}
+	cl.rpcClient = client
+	return client, nil
+}
+
+// CheckMessages implements interoptypes.Supervisor by calling the
+// supervisor_checkMessages RPC method.
+func (cl *Client) CheckMessages(ctx context.Context, messages []interoptypes.Message, minSafety interoptypes.SafetyLevel) error {
+	client, err := cl.client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.CallContext(ctx, nil, "supervisor_checkMessages", messages, minSafety)
+}
+
+// Close closes the connection to the supervisor, if any.
+func (cl *Client) Close() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if cl.rpcClient != nil {
+		cl.rpcClient.Close()
+		cl.rpcClient = nil
+	}
+}
+
+// ErrUnknownMessage is returned by the local supervisor if an executing message
+// does not reference a known initiating message.
+var ErrUnknownMessage = errors.New("unknown initiating message")
+
+// LocalSupervisor is an in-memory stand-in for the supervisor, for devnets and
+// tests. It only accepts messages that were explicitly registered with it.
+type LocalSupervisor struct {
+	mu       sync.RWMutex
+	messages map[interoptypes.Message]interoptypes.SafetyLevel
+}
+
+var _ interoptypes.Supervisor = (*LocalSupervisor)(nil)
+
+// NewLocalSupervisor creates an empty local supervisor.
+func NewLocalSupervisor() *LocalSupervisor {
+	return &LocalSupervisor{
+		messages: make(map[interoptypes.Message]interoptypes.SafetyLevel),
+	}
+}
+
+// SetSafety registers the message as known at the given safety level.
+func (s *LocalSupervisor) SetSafety(msg interoptypes.Message, level interoptypes.SafetyLevel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages[msg] = level
+}
+
+// CheckMessages implements interoptypes.Supervisor.
+func (s *LocalSupervisor) CheckMessages(ctx context.Context, messages []interoptypes.Message, minSafety interoptypes.SafetyLevel) error {
+	if err := minSafety.Validate(); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i, msg := range messages {
+		level, ok := s.messages[msg]
+		if !ok {
+			return fmt.Errorf("message %d: %w", i, ErrUnknownMessage)
+		}
+		if !level.AtLeast(minSafety) {
+			return fmt.Errorf("message %d: safety level %s below %s", i, level, minSafety)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package interop
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types/interoptypes"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// supervisorAPI exposes a local supervisor over RPC, mimicking op-supervisor.
+type supervisorAPI struct {
+	local *LocalSupervisor
+}
+
+func (api *supervisorAPI) CheckMessages(ctx context.Context, messages []interoptypes.Message, minSafety interoptypes.SafetyLevel) error {
+	return api.local.CheckMessages(ctx, messages, minSafety)
+}
+
+func TestLocalSupervisor(t *testing.T) {
+	var (
+		s     = NewLocalSupervisor()
+		known = interoptypes.Message{PayloadHash: common.Hash{0x01}}
+		other = interoptypes.Message{PayloadHash: common.Hash{0x02}}
+	)
+	s.SetSafety(known, interoptypes.CrossUnsafe)
+
+	if err := s.CheckMessages(context.Background(), []interoptypes.Message{known}, interoptypes.CrossUnsafe); err != nil {
+		t.Fatalf("known message rejected: %v", err)
+	}
+	if err := s.CheckMessages(context.Background(), []interoptypes.Message{known}, interoptypes.Safe); err == nil {
+		t.Fatal("message accepted below required safety")
+	}
+	if err := s.CheckMessages(context.Background(), []interoptypes.Message{known, other}, interoptypes.Unsafe); !errors.Is(err, ErrUnknownMessage) {
+		t.Fatalf("unexpected error: have %v, want %v", err, ErrUnknownMessage)
+	}
+}
+
+func TestClient(t *testing.T) {
+	var (
+		local  = NewLocalSupervisor()
+		known  = interoptypes.Message{PayloadHash: common.Hash{0x01}}
+		server = rpc.NewServer()
+	)
+	local.SetSafety(known, interoptypes.Finalized)
+	if err := server.RegisterName("supervisor", &supervisorAPI{local}); err != nil {
+		t.Fatal(err)
+	}
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	client := NewClient(httpsrv.URL)
+	defer client.Close()
+
+	if err := client.CheckMessages(context.Background(), []interoptypes.Message{known}, interoptypes.Safe); err != nil {
+		t.Fatalf("known message rejected: %v", err)
+	}
+	unknown := interoptypes.Message{PayloadHash: common.Hash{0x02}}
+	if err := client.CheckMessages(context.Background(), []interoptypes.Message{unknown}, interoptypes.Safe); err == nil {
+		t.Fatal("unknown message accepted")
+	}
+}
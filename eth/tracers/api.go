@@ -86,7 +86,7 @@ type Backend interface {
 	ChainDb() ethdb.Database
 	StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, readOnly bool, preferDisk bool) (*state.StateDB, StateReleaseFunc, error)
 	StateAtTransaction(ctx context.Context, block *types.Block, txIndex int, reexec uint64) (*types.Transaction, vm.BlockContext, *state.StateDB, StateReleaseFunc, error)
-	HistoricalRPCService() *rpc.Client
+	HistoricalRouter() *ethapi.HistoricalRouter
 }
 
 // API is the collection of tracing APIs exposed over the private debugging endpoint.
@@ -443,17 +443,8 @@ func (api *API) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber,
 		return nil, err
 	}
 
-	if api.backend.ChainConfig().IsOptimismPreBedrock(block.Number()) {
-		if api.backend.HistoricalRPCService() != nil {
-			var histResult []*txTraceResult
-			err = api.backend.HistoricalRPCService().CallContext(ctx, &histResult, "debug_traceBlockByNumber", number, config)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return histResult, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
-		}
+	if histResult, routed, err := ethapi.RouteHistorical[[]*txTraceResult](ctx, api.backend.HistoricalRouter(), block.Number(), "debug_traceBlockByNumber", number, config); routed {
+		return histResult, err
 	}
 
 	return api.traceBlock(ctx, block, config)
@@ -467,17 +458,8 @@ func (api *API) TraceBlockByHash(ctx context.Context, hash common.Hash, config *
 		return nil, err
 	}
 
-	if api.backend.ChainConfig().IsOptimismPreBedrock(block.Number()) {
-		if api.backend.HistoricalRPCService() != nil {
-			var histResult []*txTraceResult
-			err = api.backend.HistoricalRPCService().CallContext(ctx, &histResult, "debug_traceBlockByHash", hash, config)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return histResult, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
-		}
+	if histResult, routed, err := ethapi.RouteHistorical[[]*txTraceResult](ctx, api.backend.HistoricalRouter(), block.Number(), "debug_traceBlockByHash", hash, config); routed {
+		return histResult, err
 	}
 
 	return api.traceBlock(ctx, block, config)
@@ -882,17 +864,8 @@ func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *
 		return nil, ethapi.NewTxIndexingError()
 	}
 
-	if api.backend.ChainConfig().IsOptimismPreBedrock(new(big.Int).SetUint64(blockNumber)) {
-		if api.backend.HistoricalRPCService() != nil {
-			var histResult json.RawMessage
-			err := api.backend.HistoricalRPCService().CallContext(ctx, &histResult, "debug_traceTransaction", hash, config)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return histResult, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
-		}
+	if histResult, routed, err := ethapi.RouteHistorical[json.RawMessage](ctx, api.backend.HistoricalRouter(), new(big.Int).SetUint64(blockNumber), "debug_traceTransaction", hash, config); routed {
+		return histResult, err
 	}
 	// It shouldn't happen in practice.
 	if blockNumber == 0 {
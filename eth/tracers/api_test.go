@@ -126,7 +126,7 @@ type testBackend struct {
 	refHook func() // Hook is invoked when the requested state is referenced
 	relHook func() // Hook is invoked when the requested state is released
 
-	historical     *rpc.Client
+	historical     *ethapi.HistoricalRouter
 	mockHistorical *mockHistoricalBackend
 }
 
@@ -145,7 +145,7 @@ func newTestBackend(t *testing.T, n int, gspec *core.Genesis, generator func(i i
 		chainConfig:    gspec.Config,
 		engine:         ethash.NewFaker(),
 		chaindb:        rawdb.NewMemoryDatabase(),
-		historical:     historicalClient,
+		historical:     ethapi.NewHistoricalRouter(historicalClient, gspec.Config),
 		mockHistorical: mock,
 	}
 	// Generate blocks for testing
@@ -264,7 +264,7 @@ func (b *testBackend) StateAtTransaction(ctx context.Context, block *types.Block
 	return nil, vm.BlockContext{}, nil, nil, fmt.Errorf("transaction index %d out of range for block %#x", txIndex, block.Hash())
 }
 
-func (b *testBackend) HistoricalRPCService() *rpc.Client {
+func (b *testBackend) HistoricalRouter() *ethapi.HistoricalRouter {
 	return b.historical
 }
 
@@ -731,6 +731,49 @@ func TestTraceBlockHistorical(t *testing.T) {
 	}
 }
 
+// Tests that pre-Bedrock block traces, being immutable, are only requested once
+// from the historical node and then served from the cache.
+func TestTraceBlockHistoricalCached(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{
+		Config: params.OptimismTestConfig,
+		Alloc: core.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	signer := types.HomesteadSigner{}
+	backend := newTestBackend(t, 10, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), accounts[1].addr, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, accounts[0].key)
+		b.AddTx(tx)
+	})
+	defer backend.mockHistorical.AssertExpectations(t)
+	defer backend.chain.Stop()
+	api := NewAPI(backend)
+
+	var (
+		config      *TraceConfig
+		blockNumber = rpc.BlockNumber(3)
+		want        = `[{"txHash":"0x0000000000000000000000000000000000000000000000000000000000000000","result":{"failed":false,"gas":21000,"returnValue":"","structLogs":[]}}]`
+		ret         []*txTraceResult
+	)
+	_ = json.Unmarshal([]byte(want), &ret)
+
+	// The historical node only expects a single request.
+	backend.mockHistorical.ExpectTraceBlockByNumber(blockNumber, config, ret, nil)
+
+	for i := 0; i < 2; i++ {
+		result, err := api.TraceBlockByNumber(context.Background(), blockNumber, config)
+		if err != nil {
+			t.Fatalf("request %d: want no error, have %v", i, err)
+		}
+		if have, _ := json.Marshal(result); string(have) != want {
+			t.Errorf("request %d: result mismatch, have\n%v\n, want\n%v\n", i, string(have), want)
+		}
+	}
+}
+
 func TestTracingWithOverrides(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
@@ -1184,6 +1227,7 @@ func newTestMergedBackend(t *testing.T, n int, gspec *core.Genesis, generator fu
 		chainConfig: gspec.Config,
 		engine:      beacon.NewFaker(),
 		chaindb:     rawdb.NewMemoryDatabase(),
+		historical:  ethapi.NewHistoricalRouter(nil, gspec.Config),
 	}
 	// Generate blocks for testing
 	_, blocks, _ := core.GenerateChainWithGenesis(gspec, backend.engine, n, generator)
@@ -47,17 +47,26 @@ type supplyInfoIssuance struct {
 	GenesisAlloc *hexutil.Big `json:"genesisAlloc,omitempty"`
 	Reward       *hexutil.Big `json:"reward,omitempty"`
 	Withdrawals  *hexutil.Big `json:"withdrawals,omitempty"`
+	Deposits     *hexutil.Big `json:"deposits,omitempty"`
 }
 
 type supplyInfoBurn struct {
-	EIP1559 *hexutil.Big `json:"1559,omitempty"`
-	Blob    *hexutil.Big `json:"blob,omitempty"`
-	Misc    *hexutil.Big `json:"misc,omitempty"`
+	EIP1559     *hexutil.Big `json:"1559,omitempty"`
+	Blob        *hexutil.Big `json:"blob,omitempty"`
+	Misc        *hexutil.Big `json:"misc,omitempty"`
+	Withdrawals *hexutil.Big `json:"withdrawals,omitempty"`
+}
+
+type supplyInfoVaults struct {
+	BaseFee   *hexutil.Big `json:"baseFee,omitempty"`
+	L1Fee     *hexutil.Big `json:"l1Fee,omitempty"`
+	Sequencer *hexutil.Big `json:"sequencer,omitempty"`
 }
 
 type supplyInfo struct {
 	Issuance *supplyInfoIssuance `json:"issuance,omitempty"`
 	Burn     *supplyInfoBurn     `json:"burn,omitempty"`
+	Vaults   *supplyInfoVaults   `json:"vaults,omitempty"`
 
 	// Block info
 	Number     uint64      `json:"blockNumber"`
@@ -543,6 +552,120 @@ func TestSupplySelfdestructItselfAndRevert(t *testing.T) {
 	compareAsJSON(t, expected, actual)
 }
 
+func TestSupplyOptimism(t *testing.T) {
+	var (
+		config   = *params.MergedTestChainConfig
+		zeroTime = uint64(0)
+		denom    = uint64(250)
+
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		gwei1   = big.NewInt(params.GWei)
+		eth1    = new(big.Int).Mul(common.Big1, big.NewInt(params.Ether))
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.RegolithTime, config.CanyonTime, config.EcotoneTime = &zeroTime, &zeroTime, &zeroTime
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50, EIP1559DenominatorCanyon: &denom}
+
+	gspec := &core.Genesis{
+		Config:   &config,
+		GasLimit: 30_000_000,
+		Alloc: types.GenesisAlloc{
+			addr1: {Balance: eth1},
+			params.OptimismL2ToL1MessagePasser: {
+				// Burns its balance, like the L2ToL1MessagePasser, by creating a
+				// contract selfdestructing to itself:
+				//
+				// 	mstore(0, 0x30ff) // address(), selfdestruct
+				// 	pop(create(selfbalance(), 30, 2))
+				Code:    common.FromHex("0x6130ff6000526002601e47f05000"),
+				Balance: common.Big0,
+			},
+			// L1 attributes with an L1 base fee of 20 gwei and a scalar of 1.
+			types.L1BlockAddr: {
+				Code:    []byte{byte(vm.STOP)},
+				Balance: common.Big0,
+				Storage: map[common.Hash]common.Hash{
+					types.L1BaseFeeSlot: common.BigToHash(big.NewInt(20 * params.GWei)),
+					types.ScalarSlot:    common.BigToHash(big.NewInt(1_000_000)),
+				},
+			},
+		},
+	}
+	// Every block starts with an L1 attributes deposit. The stub L1Block contract
+	// ignores it, so the L1 fee is charged with the genesis attributes.
+	l1Info := make([]byte, 164)
+	copy(l1Info, types.EcotoneL1AttributesSelector)
+	big.NewInt(20 * params.GWei).FillBytes(l1Info[36:68])
+
+	signer := types.LatestSigner(gspec.Config)
+
+	output, chain, err := testSupplyTracer(t, gspec, func(b *core.BlockGen) {
+		b.SetPoS()
+		b.AddTx(types.NewTx(&types.DepositTx{
+			SourceHash: common.Hash{0x1},
+			From:       common.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001"),
+			To:         &types.L1BlockAddr,
+			Gas:        1_000_000,
+			Data:       l1Info,
+		}))
+		// A failed deposit still mints its ETH.
+		b.AddTx(types.NewTx(&types.DepositTx{
+			SourceHash: common.Hash{0x2},
+			From:       addr1,
+			To:         &common.Address{0x1},
+			Mint:       gwei1,
+			Value:      new(big.Int).Mul(common.Big2, eth1),
+			Gas:        100_000,
+		}))
+		// Withdraw through the message passer, paying fees to the vaults.
+		b.AddTx(types.MustSignNewTx(key1, signer, &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			Nonce:     1,
+			To:        &params.OptimismL2ToL1MessagePasser,
+			Value:     gwei1,
+			Gas:       100_000,
+			GasFeeCap: gwei1,
+			GasTipCap: big.NewInt(1),
+		}))
+	})
+	if err != nil {
+		t.Fatalf("failed to test supply tracer: %v", err)
+	}
+	block := chain.GetBlockByNumber(1)
+	receipts := chain.GetReceiptsByHash(block.Hash())
+	if receipts[1].Status != types.ReceiptStatusFailed {
+		t.Fatalf("deposit did not fail")
+	}
+	gasUsed := new(big.Int).SetUint64(receipts[2].GasUsed)
+
+	statedb, _ := chain.State()
+	l1Fee := statedb.GetBalance(params.OptimismL1FeeRecipient).ToBig()
+	if l1Fee.Sign() == 0 {
+		t.Fatalf("no L1 fee paid")
+	}
+
+	expected := supplyInfo{
+		Issuance: &supplyInfoIssuance{
+			Deposits: (*hexutil.Big)(gwei1),
+		},
+		Burn: &supplyInfoBurn{
+			Withdrawals: (*hexutil.Big)(gwei1),
+		},
+		Vaults: &supplyInfoVaults{
+			BaseFee:   (*hexutil.Big)(new(big.Int).Mul(gasUsed, block.BaseFee())),
+			L1Fee:     (*hexutil.Big)(l1Fee),
+			Sequencer: (*hexutil.Big)(gasUsed),
+		},
+		Number:     1,
+		Hash:       block.Hash(),
+		ParentHash: block.ParentHash(),
+	}
+	actual := output[expected.Number]
+
+	compareAsJSON(t, expected, actual)
+}
+
 func testSupplyTracer(t *testing.T, genesis *core.Genesis, gen func(*core.BlockGen)) ([]supplyInfo, *core.BlockChain, error) {
 	var (
 		engine = beacon.New(ethash.NewFaker())
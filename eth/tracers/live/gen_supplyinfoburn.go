@@ -14,23 +14,26 @@ var _ = (*supplyInfoBurnMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (s supplyInfoBurn) MarshalJSON() ([]byte, error) {
 	type supplyInfoBurn struct {
-		EIP1559 *hexutil.Big `json:"1559,omitempty"`
-		Blob    *hexutil.Big `json:"blob,omitempty"`
-		Misc    *hexutil.Big `json:"misc,omitempty"`
+		EIP1559     *hexutil.Big `json:"1559,omitempty"`
+		Blob        *hexutil.Big `json:"blob,omitempty"`
+		Misc        *hexutil.Big `json:"misc,omitempty"`
+		Withdrawals *hexutil.Big `json:"withdrawals,omitempty"`
 	}
 	var enc supplyInfoBurn
 	enc.EIP1559 = (*hexutil.Big)(s.EIP1559)
 	enc.Blob = (*hexutil.Big)(s.Blob)
 	enc.Misc = (*hexutil.Big)(s.Misc)
+	enc.Withdrawals = (*hexutil.Big)(s.Withdrawals)
 	return json.Marshal(&enc)
 }
 
 // UnmarshalJSON unmarshals from JSON.
 func (s *supplyInfoBurn) UnmarshalJSON(input []byte) error {
 	type supplyInfoBurn struct {
-		EIP1559 *hexutil.Big `json:"1559,omitempty"`
-		Blob    *hexutil.Big `json:"blob,omitempty"`
-		Misc    *hexutil.Big `json:"misc,omitempty"`
+		EIP1559     *hexutil.Big `json:"1559,omitempty"`
+		Blob        *hexutil.Big `json:"blob,omitempty"`
+		Misc        *hexutil.Big `json:"misc,omitempty"`
+		Withdrawals *hexutil.Big `json:"withdrawals,omitempty"`
 	}
 	var dec supplyInfoBurn
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -45,5 +48,8 @@ func (s *supplyInfoBurn) UnmarshalJSON(input []byte) error {
 	if dec.Misc != nil {
 		s.Misc = (*big.Int)(dec.Misc)
 	}
+	if dec.Withdrawals != nil {
+		s.Withdrawals = (*big.Int)(dec.Withdrawals)
+	}
 	return nil
 }
@@ -17,11 +17,13 @@ func (s supplyInfoIssuance) MarshalJSON() ([]byte, error) {
 		GenesisAlloc *hexutil.Big `json:"genesisAlloc,omitempty"`
 		Reward       *hexutil.Big `json:"reward,omitempty"`
 		Withdrawals  *hexutil.Big `json:"withdrawals,omitempty"`
+		Deposits     *hexutil.Big `json:"deposits,omitempty"`
 	}
 	var enc supplyInfoIssuance
 	enc.GenesisAlloc = (*hexutil.Big)(s.GenesisAlloc)
 	enc.Reward = (*hexutil.Big)(s.Reward)
 	enc.Withdrawals = (*hexutil.Big)(s.Withdrawals)
+	enc.Deposits = (*hexutil.Big)(s.Deposits)
 	return json.Marshal(&enc)
 }
 
@@ -31,6 +33,7 @@ func (s *supplyInfoIssuance) UnmarshalJSON(input []byte) error {
 		GenesisAlloc *hexutil.Big `json:"genesisAlloc,omitempty"`
 		Reward       *hexutil.Big `json:"reward,omitempty"`
 		Withdrawals  *hexutil.Big `json:"withdrawals,omitempty"`
+		Deposits     *hexutil.Big `json:"deposits,omitempty"`
 	}
 	var dec supplyInfoIssuance
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -45,5 +48,8 @@ func (s *supplyInfoIssuance) UnmarshalJSON(input []byte) error {
 	if dec.Withdrawals != nil {
 		s.Withdrawals = (*big.Int)(dec.Withdrawals)
 	}
+	if dec.Deposits != nil {
+		s.Deposits = (*big.Int)(dec.Deposits)
+	}
 	return nil
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
@@ -26,6 +27,7 @@ type supplyInfoIssuance struct {
 	GenesisAlloc *big.Int `json:"genesisAlloc,omitempty"`
 	Reward       *big.Int `json:"reward,omitempty"`
 	Withdrawals  *big.Int `json:"withdrawals,omitempty"`
+	Deposits     *big.Int `json:"deposits,omitempty"` // ETH minted by OP-Stack deposits
 }
 
 //go:generate go run github.com/fjl/gencodec -type supplyInfoIssuance -field-override supplyInfoIssuanceMarshaling -out gen_supplyinfoissuance.go
@@ -33,24 +35,43 @@ type supplyInfoIssuanceMarshaling struct {
 	GenesisAlloc *hexutil.Big
 	Reward       *hexutil.Big
 	Withdrawals  *hexutil.Big
+	Deposits     *hexutil.Big
 }
 
 type supplyInfoBurn struct {
-	EIP1559 *big.Int `json:"1559,omitempty"`
-	Blob    *big.Int `json:"blob,omitempty"`
-	Misc    *big.Int `json:"misc,omitempty"`
+	EIP1559     *big.Int `json:"1559,omitempty"`
+	Blob        *big.Int `json:"blob,omitempty"`
+	Misc        *big.Int `json:"misc,omitempty"`
+	Withdrawals *big.Int `json:"withdrawals,omitempty"` // ETH withdrawn to L1, burned by the OP-Stack L2ToL1MessagePasser
 }
 
 //go:generate go run github.com/fjl/gencodec -type supplyInfoBurn -field-override supplyInfoBurnMarshaling -out gen_supplyinfoburn.go
 type supplyInfoBurnMarshaling struct {
-	EIP1559 *hexutil.Big
-	Blob    *hexutil.Big
-	Misc    *hexutil.Big
+	EIP1559     *hexutil.Big
+	Blob        *hexutil.Big
+	Misc        *hexutil.Big
+	Withdrawals *hexutil.Big
+}
+
+// supplyInfoVaults holds the fees routed to the OP-Stack fee vaults. These don't
+// change the supply, but replace the EIP-1559 burn on OP-Stack chains.
+type supplyInfoVaults struct {
+	BaseFee   *big.Int `json:"baseFee,omitempty"`
+	L1Fee     *big.Int `json:"l1Fee,omitempty"`
+	Sequencer *big.Int `json:"sequencer,omitempty"`
+}
+
+//go:generate go run github.com/fjl/gencodec -type supplyInfoVaults -field-override supplyInfoVaultsMarshaling -out gen_supplyinfovaults.go
+type supplyInfoVaultsMarshaling struct {
+	BaseFee   *hexutil.Big
+	L1Fee     *hexutil.Big
+	Sequencer *hexutil.Big
 }
 
 type supplyInfo struct {
 	Issuance *supplyInfoIssuance `json:"issuance,omitempty"`
 	Burn     *supplyInfoBurn     `json:"burn,omitempty"`
+	Vaults   *supplyInfoVaults   `json:"vaults,omitempty"`
 
 	// Block info
 	Number     uint64      `json:"blockNumber"`
@@ -59,14 +80,19 @@ type supplyInfo struct {
 }
 
 type supplyTxCallstack struct {
-	calls []supplyTxCallstack
-	burn  *big.Int
+	calls      []supplyTxCallstack
+	burn       *big.Int
+	withdrawal bool // Whether the burn is a withdrawal through the L2ToL1MessagePasser
 }
 
 type supply struct {
 	delta       supplyInfo
 	txCallstack []supplyTxCallstack // Callstack for current transaction
 	logger      *lumberjack.Logger
+
+	optimism bool                        // Whether the chain is an OP-Stack chain
+	coinbase common.Address              // Coinbase of the current block, the sequencer fee vault on OP-Stack chains
+	burners  map[common.Address]struct{} // Contracts created by the L2ToL1MessagePasser in the current transaction
 }
 
 type supplyTracerConfig struct {
@@ -98,14 +124,15 @@ func newSupply(cfg json.RawMessage) (*tracing.Hooks, error) {
 		logger: logger,
 	}
 	return &tracing.Hooks{
-		OnBlockStart:    t.OnBlockStart,
-		OnBlockEnd:      t.OnBlockEnd,
-		OnGenesisBlock:  t.OnGenesisBlock,
-		OnTxStart:       t.OnTxStart,
-		OnBalanceChange: t.OnBalanceChange,
-		OnEnter:         t.OnEnter,
-		OnExit:          t.OnExit,
-		OnClose:         t.OnClose,
+		OnBlockchainInit: t.OnBlockchainInit,
+		OnBlockStart:     t.OnBlockStart,
+		OnBlockEnd:       t.OnBlockEnd,
+		OnGenesisBlock:   t.OnGenesisBlock,
+		OnTxStart:        t.OnTxStart,
+		OnBalanceChange:  t.OnBalanceChange,
+		OnEnter:          t.OnEnter,
+		OnExit:           t.OnExit,
+		OnClose:          t.OnClose,
 	}, nil
 }
 
@@ -115,11 +142,18 @@ func newSupplyInfo() supplyInfo {
 			GenesisAlloc: big.NewInt(0),
 			Reward:       big.NewInt(0),
 			Withdrawals:  big.NewInt(0),
+			Deposits:     big.NewInt(0),
 		},
 		Burn: &supplyInfoBurn{
-			EIP1559: big.NewInt(0),
-			Blob:    big.NewInt(0),
-			Misc:    big.NewInt(0),
+			EIP1559:     big.NewInt(0),
+			Blob:        big.NewInt(0),
+			Misc:        big.NewInt(0),
+			Withdrawals: big.NewInt(0),
+		},
+		Vaults: &supplyInfoVaults{
+			BaseFee:   big.NewInt(0),
+			L1Fee:     big.NewInt(0),
+			Sequencer: big.NewInt(0),
 		},
 
 		Number:     0,
@@ -132,15 +166,21 @@ func (s *supply) resetDelta() {
 	s.delta = newSupplyInfo()
 }
 
+func (s *supply) OnBlockchainInit(chainConfig *params.ChainConfig) {
+	s.optimism = chainConfig.Optimism != nil
+}
+
 func (s *supply) OnBlockStart(ev tracing.BlockEvent) {
 	s.resetDelta()
 
 	s.delta.Number = ev.Block.NumberU64()
 	s.delta.Hash = ev.Block.Hash()
 	s.delta.ParentHash = ev.Block.ParentHash()
+	s.coinbase = ev.Block.Coinbase()
 
-	// Calculate Burn for this block
-	if ev.Block.BaseFee() != nil {
+	// Calculate Burn for this block. On OP-Stack chains the base fee is paid to
+	// a vault instead, which is tracked through the balance changes.
+	if ev.Block.BaseFee() != nil && !s.optimism {
 		burn := new(big.Int).Mul(new(big.Int).SetUint64(ev.Block.GasUsed()), ev.Block.BaseFee())
 		s.delta.Burn.EIP1559 = burn
 	}
@@ -187,10 +227,25 @@ func (s *supply) OnBalanceChange(a common.Address, prevBalance, newBalance *big.
 	case tracing.BalanceDecreaseSelfdestructBurn:
 		// BalanceDecreaseSelfdestructBurn is non-reversible as it happens
 		// at the end of the transaction.
-		s.delta.Burn.Misc.Sub(s.delta.Burn.Misc, diff)
-	// Technically an OP-Stack deposit mint is a "withdrawal" from L1, taking funds into L2.
+		if _, ok := s.burners[a]; ok {
+			s.delta.Burn.Withdrawals.Sub(s.delta.Burn.Withdrawals, diff)
+		} else {
+			s.delta.Burn.Misc.Sub(s.delta.Burn.Misc, diff)
+		}
 	case tracing.BalanceMint:
-		s.delta.Issuance.Withdrawals.Add(s.delta.Issuance.Withdrawals, diff)
+		s.delta.Issuance.Deposits.Add(s.delta.Issuance.Deposits, diff)
+	case tracing.BalanceIncreaseRewardTransactionFee:
+		if !s.optimism {
+			return
+		}
+		switch a {
+		case params.OptimismBaseFeeRecipient:
+			s.delta.Vaults.BaseFee.Add(s.delta.Vaults.BaseFee, diff)
+		case params.OptimismL1FeeRecipient:
+			s.delta.Vaults.L1Fee.Add(s.delta.Vaults.L1Fee, diff)
+		case s.coinbase:
+			s.delta.Vaults.Sequencer.Add(s.delta.Vaults.Sequencer, diff)
+		}
 	default:
 		return
 	}
@@ -198,13 +253,18 @@ func (s *supply) OnBalanceChange(a common.Address, prevBalance, newBalance *big.
 
 func (s *supply) OnTxStart(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
 	s.txCallstack = make([]supplyTxCallstack, 0, 1)
+	s.burners = make(map[common.Address]struct{})
 }
 
 // internalTxsHandler handles internal transactions burned amount
 func (s *supply) internalTxsHandler(call *supplyTxCallstack) {
 	// Handle Burned amount
 	if call.burn != nil {
-		s.delta.Burn.Misc.Add(s.delta.Burn.Misc, call.burn)
+		if call.withdrawal {
+			s.delta.Burn.Withdrawals.Add(s.delta.Burn.Withdrawals, call.burn)
+		} else {
+			s.delta.Burn.Misc.Add(s.delta.Burn.Misc, call.burn)
+		}
 	}
 
 	// Recursively handle internal calls
@@ -223,6 +283,12 @@ func (s *supply) OnEnter(depth int, typ byte, from common.Address, to common.Add
 	// which happens when type == selfdestruct and from == to.
 	if vm.OpCode(typ) == vm.SELFDESTRUCT && from == to && value.Cmp(common.Big0) == 1 {
 		call.burn = value
+		_, call.withdrawal = s.burners[from]
+	}
+	// The L2ToL1MessagePasser burns withdrawn ETH by creating a contract which
+	// selfdestructs to itself.
+	if s.optimism && (vm.OpCode(typ) == vm.CREATE || vm.OpCode(typ) == vm.CREATE2) && from == params.OptimismL2ToL1MessagePasser {
+		s.burners[to] = struct{}{}
 	}
 
 	// Append call to the callstack, so we can fill the details in CaptureExit
@@ -281,7 +347,11 @@ func (s *supply) write(data any) {
 		supply.Issuance.Withdrawals = nil
 	}
 
-	if supply.Issuance.GenesisAlloc == nil && supply.Issuance.Reward == nil && supply.Issuance.Withdrawals == nil {
+	if supply.Issuance.Deposits.Sign() == 0 {
+		supply.Issuance.Deposits = nil
+	}
+
+	if supply.Issuance.GenesisAlloc == nil && supply.Issuance.Reward == nil && supply.Issuance.Withdrawals == nil && supply.Issuance.Deposits == nil {
 		supply.Issuance = nil
 	}
 
@@ -297,10 +367,30 @@ func (s *supply) write(data any) {
 		supply.Burn.Misc = nil
 	}
 
-	if supply.Burn.EIP1559 == nil && supply.Burn.Blob == nil && supply.Burn.Misc == nil {
+	if supply.Burn.Withdrawals.Sign() == 0 {
+		supply.Burn.Withdrawals = nil
+	}
+
+	if supply.Burn.EIP1559 == nil && supply.Burn.Blob == nil && supply.Burn.Misc == nil && supply.Burn.Withdrawals == nil {
 		supply.Burn = nil
 	}
 
+	if supply.Vaults.BaseFee.Sign() == 0 {
+		supply.Vaults.BaseFee = nil
+	}
+
+	if supply.Vaults.L1Fee.Sign() == 0 {
+		supply.Vaults.L1Fee = nil
+	}
+
+	if supply.Vaults.Sequencer.Sign() == 0 {
+		supply.Vaults.Sequencer = nil
+	}
+
+	if supply.Vaults.BaseFee == nil && supply.Vaults.L1Fee == nil && supply.Vaults.Sequencer == nil {
+		supply.Vaults = nil
+	}
+
 	out, _ := json.Marshal(supply)
 	if _, err := s.logger.Write(out); err != nil {
 		log.Warn("failed to write to supply tracer log file", "error", err)
@@ -0,0 +1,155 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("opFeeTracer", newOpFeeTracer, false)
+}
+
+// opFeePayment is a fee paid to a fee vault.
+type opFeePayment struct {
+	Amount *hexutil.Big   `json:"amount"`
+	Vault  common.Address `json:"vault"`
+}
+
+// opFeeResult is the fee accounting of a transaction on an OP chain.
+type opFeeResult struct {
+	Deposit     bool          `json:"deposit,omitempty"`
+	Mint        *hexutil.Big  `json:"mint,omitempty"`
+	Failed      bool          `json:"failed,omitempty"`
+	L1Fee       *opFeePayment `json:"l1Fee,omitempty"`
+	BaseFee     *opFeePayment `json:"baseFee,omitempty"`
+	PriorityFee *opFeePayment `json:"priorityFee,omitempty"`
+}
+
+// opFeeTracer reports how a transaction on an OP chain is accounted for: the
+// ETH minted by a deposit, and the L1 data fee, L2 base fee and priority fee
+// paid by other transactions along with the vault receiving each of them.
+// Deposits charge no fees, and a failed deposit still mints its ETH, as the
+// mint is applied before the execution being reverted.
+//
+// Example:
+//
+//	> debug.traceTransaction("0x...", {tracer: "opFeeTracer"})
+//	{
+//	  baseFee: {
+//	    amount: "0x5208",
+//	    vault: "0x4200000000000000000000000000000000000019"
+//	  },
+//	  l1Fee: {
+//	    amount: "0x1f4",
+//	    vault: "0x420000000000000000000000000000000000001a"
+//	  },
+//	  priorityFee: {
+//	    amount: "0x5208",
+//	    vault: "0x4200000000000000000000000000000000000011"
+//	  }
+//	}
+type opFeeTracer struct {
+	result    opFeeResult
+	from      common.Address
+	coinbase  common.Address
+	inTx      bool
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+// newOpFeeTracer returns a native go tracer which reports the mint and fee
+// payments of a transaction on an OP chain.
+func newOpFeeTracer(ctx *tracers.Context, _ json.RawMessage) (*tracers.Tracer, error) {
+	t := &opFeeTracer{}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart:       t.OnTxStart,
+			OnTxEnd:         t.OnTxEnd,
+			OnBalanceChange: t.OnBalanceChange,
+		},
+		GetResult: t.GetResult,
+		Stop:      t.Stop,
+	}, nil
+}
+
+func (t *opFeeTracer) OnTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	t.from = from
+	t.coinbase = env.Coinbase
+	t.inTx = true
+	t.result.Deposit = tx.IsDepositTx()
+}
+
+func (t *opFeeTracer) OnTxEnd(receipt *types.Receipt, err error) {
+	t.inTx = false
+	if err != nil {
+		return
+	}
+	// A failed deposit reverts its execution, but keeps the minted ETH.
+	t.result.Failed = receipt.Status == types.ReceiptStatusFailed
+}
+
+// OnBalanceChange records the mint of a deposit and the fee payments to the
+// vaults. The base fee and L1 fee vaults are fixed, the priority fee is paid
+// to the coinbase, which is the sequencer fee vault on OP chains.
+func (t *opFeeTracer) OnBalanceChange(addr common.Address, prev, new *big.Int, reason tracing.BalanceChangeReason) {
+	if t.interrupt.Load() || !t.inTx {
+		return
+	}
+	amount := big.NewInt(0).Sub(new, prev)
+	switch reason {
+	case tracing.BalanceMint:
+		if addr == t.from {
+			t.result.Mint = (*hexutil.Big)(amount)
+		}
+	case tracing.BalanceIncreaseRewardTransactionFee:
+		payment := &opFeePayment{Amount: (*hexutil.Big)(amount), Vault: addr}
+		switch addr {
+		case params.OptimismBaseFeeRecipient:
+			t.result.BaseFee = payment
+		case params.OptimismL1FeeRecipient:
+			t.result.L1Fee = payment
+		case t.coinbase:
+			t.result.PriorityFee = payment
+		}
+	}
+}
+
+// GetResult returns the json-encoded fee accounting of the transaction, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *opFeeTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.result)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *opFeeTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpFeeTracer(t *testing.T) {
+	var (
+		from     = common.Address{0x01}
+		coinbase = common.HexToAddress("0x4200000000000000000000000000000000000011")
+		env      = &tracing.VMContext{Coinbase: coinbase, ChainConfig: params.OptimismTestConfig}
+	)
+	// A failed deposit keeps its mint, and charges no fees.
+	tracer, err := tracers.DefaultDirectory.New("opFeeTracer", &tracers.Context{}, nil)
+	require.NoError(t, err)
+
+	deposit := types.NewTx(&types.DepositTx{From: from, To: &common.Address{0x02}, Mint: big.NewInt(1000), Gas: 100_000})
+	tracer.OnTxStart(env, deposit, from)
+	tracer.OnBalanceChange(from, big.NewInt(5), big.NewInt(1005), tracing.BalanceMint)
+	tracer.OnBalanceChange(from, big.NewInt(1005), big.NewInt(5), tracing.BalanceChangeTransfer)
+	tracer.OnTxEnd(&types.Receipt{Status: types.ReceiptStatusFailed, GasUsed: 100_000}, nil)
+
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"deposit":true,"mint":"0x3e8","failed":true}`, string(res))
+
+	// A regular transaction pays the priority fee, base fee and L1 fee to their vaults.
+	tracer, err = tracers.DefaultDirectory.New("opFeeTracer", &tracers.Context{}, nil)
+	require.NoError(t, err)
+
+	tx := types.NewTx(&types.DynamicFeeTx{To: &common.Address{0x02}, Gas: params.TxGas, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2)})
+	tracer.OnTxStart(env, tx, from)
+	tracer.OnBalanceChange(from, big.NewInt(100_000), big.NewInt(57_500), tracing.BalanceDecreaseGasBuy)
+	tracer.OnBalanceChange(coinbase, big.NewInt(0), big.NewInt(21_000), tracing.BalanceIncreaseRewardTransactionFee)
+	tracer.OnBalanceChange(params.OptimismBaseFeeRecipient, big.NewInt(10), big.NewInt(21_010), tracing.BalanceIncreaseRewardTransactionFee)
+	tracer.OnBalanceChange(params.OptimismL1FeeRecipient, big.NewInt(0), big.NewInt(500), tracing.BalanceIncreaseRewardTransactionFee)
+	tracer.OnTxEnd(&types.Receipt{Status: types.ReceiptStatusSuccessful, GasUsed: params.TxGas}, nil)
+
+	res, err = tracer.GetResult()
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"priorityFee": {"amount": "0x5208", "vault": "0x4200000000000000000000000000000000000011"},
+		"baseFee": {"amount": "0x5208", "vault": "0x4200000000000000000000000000000000000019"},
+		"l1Fee": {"amount": "0x1f4", "vault": "0x420000000000000000000000000000000000001a"}
+	}`, string(res))
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package opclient provides an RPC client for the OP-stack specific data of a
+// rollup node.
+package opclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// blockTime is the block time of the OP-stack chains, in seconds.
+const blockTime = 2
+
+var (
+	errNotOptimism      = errors.New("not an OP-stack chain")
+	errNoL1AttributesTx = errors.New("block has no L1 attributes transaction")
+
+	// l1CostStateSlots are the slots of the L1Block contract read by the L1
+	// cost function.
+	l1CostStateSlots = []common.Hash{types.L1BaseFeeSlot, types.OverheadSlot, types.ScalarSlot, types.L1BlobBaseFeeSlot, types.L1FeeScalarsSlot}
+)
+
+// Client is a wrapper around rpc.Client that implements the OP-stack specific
+// functionality. The chain configuration is needed to decode the L1 attributes
+// according to the active hardforks.
+//
+// If you want to use the standardized Ethereum RPC functionality, use ethclient.Client instead.
+type Client struct {
+	ec     *ethclient.Client
+	config *params.ChainConfig
+}
+
+// New creates a client that uses the given RPC client.
+func New(c *rpc.Client, config *params.ChainConfig) *Client {
+	return &Client{ec: ethclient.NewClient(c), config: config}
+}
+
+// TransactionReceipt returns the receipt of a transaction by transaction hash.
+//
+// Besides the standard fields, the receipt of a non-deposit transaction has the
+// L1Fee, L1GasPrice, L1BlobBaseFee, L1GasUsed and fee scalar fields set, which
+// detail the data availability fee charged. The receipt of a deposit transaction
+// has the DepositNonce and, since Canyon, the DepositReceiptVersion set instead.
+func (oc *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return oc.ec.TransactionReceipt(ctx, txHash)
+}
+
+// BlockReceipts returns the receipts of a given block number or hash, including
+// the OP-stack specific fields documented on TransactionReceipt.
+func (oc *Client) BlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*types.Receipt, error) {
+	return oc.ec.BlockReceipts(ctx, blockNrOrHash)
+}
+
+// L1Attributes returns the L1 gas attributes of the given block, decoded from
+// its L1 attributes transaction. If number is nil, the latest known block is
+// used.
+func (oc *Client) L1Attributes(ctx context.Context, number *big.Int) (*types.L1GasParams, error) {
+	if oc.config.Optimism == nil {
+		return nil, errNotOptimism
+	}
+	block, err := oc.ec.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	txs := block.Transactions()
+	if len(txs) == 0 || !txs[0].IsDepositTx() || txs[0].To() == nil || *txs[0].To() != types.L1BlockAddr {
+		return nil, errNoL1AttributesTx
+	}
+	return types.ExtractL1GasParams(oc.config, block.Time(), txs[0].Data())
+}
+
+// EstimateL1Fee estimates the data availability fee of a transaction included
+// in the block following the given one, based on the L1 gas attributes of the
+// given block. If number is nil, the latest known block is used.
+//
+// The transaction doesn't need to be signed: a placeholder signature is added
+// to unsigned transactions, so that their encoded size matches the signed one.
+func (oc *Client) EstimateL1Fee(ctx context.Context, tx *types.Transaction, number *big.Int) (*big.Int, error) {
+	if oc.config.Optimism == nil {
+		return nil, errNotOptimism
+	}
+	head, err := oc.ec.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	// Fetch the L1 gas attributes from the L1Block contract, pinning the state
+	// to the resolved block by hash, so that a reorg can't mix up the slots.
+	state := make(l1BlockState, len(l1CostStateSlots))
+	for _, slot := range l1CostStateSlots {
+		value, err := oc.ec.StorageAtHash(ctx, types.L1BlockAddr, slot, head.Hash())
+		if err != nil {
+			return nil, err
+		}
+		state[slot] = common.BytesToHash(value)
+	}
+	if v, r, s := tx.RawSignatureValues(); !tx.IsDepositTx() && v.Sign() == 0 && r.Sign() == 0 && s.Sign() == 0 {
+		if tx, err = tx.WithPlaceholderSignature(types.LatestSigner(oc.config)); err != nil {
+			return nil, err
+		}
+	}
+	// The transaction is included in the next block, whose time decides the forks
+	fee := types.NewL1CostFunc(oc.config, state)(tx.RollupCostData(), head.Time+blockTime)
+	if fee == nil {
+		return new(big.Int), nil
+	}
+	return fee, nil
+}
+
+// l1BlockState is the storage of the L1Block contract, as needed to compute
+// the data availability fee.
+type l1BlockState map[common.Hash]common.Hash
+
+func (s l1BlockState) GetState(addr common.Address, slot common.Hash) common.Hash {
+	return s[slot]
+}
@@ -0,0 +1,202 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package opclient
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+
+	l1BaseFee           = big.NewInt(20 * params.GWei)
+	l1BlobBaseFee       = big.NewInt(3)
+	l1BaseFeeScalar     = uint32(1368)
+	l1BlobBaseFeeScalar = uint32(810949)
+)
+
+// testConfig is a chain configuration with Ecotone active since genesis.
+var testConfig = func() *params.ChainConfig {
+	config := *params.OptimismTestConfig
+	zero := uint64(0)
+	config.RegolithTime, config.CanyonTime, config.EcotoneTime = &zero, &zero, &zero
+	return &config
+}()
+
+// l1AttributesData returns the calldata of an Ecotone L1 attributes transaction.
+func l1AttributesData() []byte {
+	data := make([]byte, 164)
+	copy(data, types.EcotoneL1AttributesSelector)
+	binary.BigEndian.PutUint32(data[4:8], l1BaseFeeScalar)
+	binary.BigEndian.PutUint32(data[8:12], l1BlobBaseFeeScalar)
+	l1BaseFee.FillBytes(data[36:68])
+	l1BlobBaseFee.FillBytes(data[68:100])
+	return data
+}
+
+// testService mocks the eth namespace of a rollup node with a single block.
+type testService struct {
+	block *types.Block
+
+	storageBlocks []rpc.BlockNumberOrHash // Blocks the storage was requested at
+}
+
+func (s *testService) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	fields := ethapi.RPCMarshalHeader(s.block.Header())
+	txs := make([]map[string]interface{}, 0)
+	for i, tx := range s.block.Transactions() {
+		var txFields map[string]interface{}
+		blob, _ := json.Marshal(tx)
+		if err := json.Unmarshal(blob, &txFields); err != nil {
+			return nil, err
+		}
+		from, _ := types.Sender(types.LatestSigner(testConfig), tx)
+		txFields["from"] = from
+		txFields["blockHash"] = s.block.Hash()
+		txFields["blockNumber"] = (*hexutil.Big)(s.block.Number())
+		txFields["transactionIndex"] = hexutil.Uint64(i)
+		txs = append(txs, txFields)
+	}
+	fields["transactions"] = txs
+	fields["uncles"] = []common.Hash{}
+	return fields, nil
+}
+
+func (s *testService) GetStorageAt(address common.Address, slot common.Hash, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	s.storageBlocks = append(s.storageBlocks, blockNrOrHash)
+
+	var value common.Hash
+	switch slot {
+	case types.L1BaseFeeSlot:
+		l1BaseFee.FillBytes(value[:])
+	case types.L1BlobBaseFeeSlot:
+		l1BlobBaseFee.FillBytes(value[:])
+	case types.L1FeeScalarsSlot:
+		binary.BigEndian.PutUint32(value[32-types.BaseFeeScalarSlotOffset-4:], l1BaseFeeScalar)
+		binary.BigEndian.PutUint32(value[32-types.BlobBaseFeeScalarSlotOffset-4:], l1BlobBaseFeeScalar)
+	}
+	return value[:], nil
+}
+
+func newTestClient(t *testing.T) (*Client, *testService) {
+	attributes := types.NewTx(&types.DepositTx{
+		From: common.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001"),
+		To:   &types.L1BlockAddr,
+		Gas:  1_000_000,
+		Data: l1AttributesData(),
+	})
+	header := &types.Header{Number: big.NewInt(1), Time: 2, BaseFee: big.NewInt(params.GWei), Difficulty: common.Big0}
+	block := types.NewBlock(header, &types.Body{Transactions: types.Transactions{attributes}}, nil, trie.NewStackTrie(nil))
+
+	service := &testService{block: block}
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(srv)
+	t.Cleanup(func() {
+		client.Close()
+		srv.Stop()
+	})
+	return New(client, testConfig), service
+}
+
+func TestL1Attributes(t *testing.T) {
+	oc, _ := newTestClient(t)
+
+	attrs, err := oc.L1Attributes(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve L1 attributes: %v", err)
+	}
+	if attrs.L1BaseFee.Cmp(l1BaseFee) != 0 || attrs.L1BlobBaseFee.Cmp(l1BlobBaseFee) != 0 {
+		t.Errorf("wrong L1 fees: have %v/%v, want %v/%v", attrs.L1BaseFee, attrs.L1BlobBaseFee, l1BaseFee, l1BlobBaseFee)
+	}
+	if *attrs.L1BaseFeeScalar != uint64(l1BaseFeeScalar) || *attrs.L1BlobBaseFeeScalar != uint64(l1BlobBaseFeeScalar) {
+		t.Errorf("wrong fee scalars: have %d/%d, want %d/%d", *attrs.L1BaseFeeScalar, *attrs.L1BlobBaseFeeScalar, l1BaseFeeScalar, l1BlobBaseFeeScalar)
+	}
+}
+
+func TestEstimateL1Fee(t *testing.T) {
+	oc, service := newTestClient(t)
+	ctx := context.Background()
+
+	attributes, err := oc.L1Attributes(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve L1 attributes: %v", err)
+	}
+	unsigned := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   testConfig.ChainID,
+		Nonce:     7,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: big.NewInt(2 * params.GWei),
+		Gas:       params.TxGas,
+		To:        &common.Address{0x1},
+		Value:     big.NewInt(1),
+	})
+	signed, err := types.SignTx(unsigned, types.LatestSigner(testConfig), testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fee estimated from the L1Block state matches the one from the L1
+	// attributes of the block.
+	fee, err := oc.EstimateL1Fee(ctx, signed, nil)
+	if err != nil {
+		t.Fatalf("failed to estimate L1 fee: %v", err)
+	}
+	if want, _ := attributes.L1Cost(signed.RollupCostData()); fee.Cmp(want) != 0 {
+		t.Errorf("wrong L1 fee: have %v, want %v", fee, want)
+	}
+	// The L1Block state is read at the hash of the block.
+	for _, blockNrOrHash := range service.storageBlocks {
+		if hash, ok := blockNrOrHash.Hash(); !ok || hash != service.block.Hash() {
+			t.Errorf("storage read at %v, want block hash %x", blockNrOrHash, service.block.Hash())
+		}
+	}
+	// The forks active at the time of the next block apply.
+	config := *testConfig
+	fjordTime := service.block.Time() + blockTime
+	config.FjordTime = &fjordTime
+
+	fjordFee, err := (&Client{ec: oc.ec, config: &config}).EstimateL1Fee(ctx, signed, nil)
+	if err != nil {
+		t.Fatalf("failed to estimate L1 fee: %v", err)
+	}
+	if fjordFee.Cmp(fee) == 0 {
+		t.Errorf("fee of the next block not estimated with Fjord active: %v", fjordFee)
+	}
+	// Unsigned transactions are estimated with a placeholder signature.
+	fee, err = oc.EstimateL1Fee(ctx, unsigned, nil)
+	if err != nil {
+		t.Fatalf("failed to estimate L1 fee: %v", err)
+	}
+	if bare, _ := attributes.L1Cost(unsigned.RollupCostData()); fee.Cmp(bare) <= 0 {
+		t.Errorf("unsigned transaction fee %v not above the fee without signature %v", fee, bare)
+	}
+}
@@ -641,6 +641,126 @@ func (t *Transaction) RawReceipt(ctx context.Context) (hexutil.Bytes, error) {
 	return receipt.MarshalBinary()
 }
 
+func (t *Transaction) SourceHash(ctx context.Context) *common.Hash {
+	tx, _ := t.resolve(ctx)
+	if tx == nil || !tx.IsDepositTx() {
+		return nil
+	}
+	sourceHash := tx.SourceHash()
+	return &sourceHash
+}
+
+func (t *Transaction) Mint(ctx context.Context) *hexutil.Big {
+	tx, _ := t.resolve(ctx)
+	if tx == nil || !tx.IsDepositTx() {
+		return nil
+	}
+	return (*hexutil.Big)(tx.Mint())
+}
+
+func (t *Transaction) IsSystemTx(ctx context.Context) *bool {
+	tx, _ := t.resolve(ctx)
+	if tx == nil || !tx.IsDepositTx() {
+		return nil
+	}
+	isSystemTx := tx.IsSystemTx()
+	return &isSystemTx
+}
+
+// getDepositReceipt returns the receipt of a mined deposit transaction, or nil
+// for other transactions.
+func (t *Transaction) getDepositReceipt(ctx context.Context) (*types.Receipt, error) {
+	tx, _ := t.resolve(ctx)
+	if tx == nil || !tx.IsDepositTx() {
+		return nil, nil
+	}
+	return t.getReceipt(ctx)
+}
+
+func (t *Transaction) DepositNonce(ctx context.Context) (*hexutil.Uint64, error) {
+	receipt, err := t.getDepositReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return (*hexutil.Uint64)(receipt.DepositNonce), nil
+}
+
+func (t *Transaction) DepositReceiptVersion(ctx context.Context) (*hexutil.Uint64, error) {
+	receipt, err := t.getDepositReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return (*hexutil.Uint64)(receipt.DepositReceiptVersion), nil
+}
+
+// getL1FeeReceipt returns the receipt of a mined non-deposit transaction on an
+// OP-stack chain, which holds the data availability fee details, or nil for
+// other transactions.
+func (t *Transaction) getL1FeeReceipt(ctx context.Context) (*types.Receipt, error) {
+	tx, _ := t.resolve(ctx)
+	if tx == nil || tx.IsDepositTx() || t.r.backend.ChainConfig().Optimism == nil {
+		return nil, nil
+	}
+	return t.getReceipt(ctx)
+}
+
+func (t *Transaction) L1Fee(ctx context.Context) (*hexutil.Big, error) {
+	receipt, err := t.getL1FeeReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(receipt.L1Fee), nil
+}
+
+func (t *Transaction) L1GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	receipt, err := t.getL1FeeReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(receipt.L1GasPrice), nil
+}
+
+func (t *Transaction) L1GasUsed(ctx context.Context) (*hexutil.Big, error) {
+	receipt, err := t.getL1FeeReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(receipt.L1GasUsed), nil
+}
+
+func (t *Transaction) L1BlobBaseFee(ctx context.Context) (*hexutil.Big, error) {
+	receipt, err := t.getL1FeeReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(receipt.L1BlobBaseFee), nil
+}
+
+func (t *Transaction) L1FeeScalar(ctx context.Context) (*string, error) {
+	receipt, err := t.getL1FeeReceipt(ctx)
+	if err != nil || receipt == nil || receipt.FeeScalar == nil {
+		return nil, err
+	}
+	scalar := receipt.FeeScalar.String()
+	return &scalar, nil
+}
+
+func (t *Transaction) L1BaseFeeScalar(ctx context.Context) (*hexutil.Uint64, error) {
+	receipt, err := t.getL1FeeReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return (*hexutil.Uint64)(receipt.L1BaseFeeScalar), nil
+}
+
+func (t *Transaction) L1BlobBaseFeeScalar(ctx context.Context) (*hexutil.Uint64, error) {
+	receipt, err := t.getL1FeeReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return (*hexutil.Uint64)(receipt.L1BlobBaseFeeScalar), nil
+}
+
 type BlockType int
 
 // Block represents an Ethereum block.
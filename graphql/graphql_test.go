@@ -18,6 +18,7 @@ package graphql
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -28,6 +29,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
@@ -431,6 +433,104 @@ func TestWithdrawals(t *testing.T) {
 	}
 }
 
+func TestOptimismTransactionFields(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		config   = *params.MergedTestChainConfig
+		zeroTime = uint64(0)
+		denom    = uint64(250)
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.RegolithTime, config.CanyonTime, config.EcotoneTime = &zeroTime, &zeroTime, &zeroTime
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50, EIP1559DenominatorCanyon: &denom}
+
+	genesis := &core.Genesis{
+		Config:   &config,
+		GasLimit: 30_000_000,
+		Alloc: types.GenesisAlloc{
+			addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	// Ecotone L1 attributes, with a base fee scalar of 1368, a blob base fee
+	// scalar of 810949, an L1 base fee of 20 gwei and an L1 blob base fee of 1.
+	l1Info := make([]byte, 164)
+	copy(l1Info, types.EcotoneL1AttributesSelector)
+	binary.BigEndian.PutUint32(l1Info[4:8], 1368)
+	binary.BigEndian.PutUint32(l1Info[8:12], 810949)
+	big.NewInt(20 * params.GWei).FillBytes(l1Info[36:68])
+	big.NewInt(1).FillBytes(l1Info[68:100])
+
+	signer := types.LatestSigner(genesis.Config)
+	stack := createNode(t)
+	defer stack.Close()
+
+	handler, chain := newGQLService(t, stack, false, genesis, 1, func(i int, gen *core.BlockGen) {
+		gen.SetPoS()
+		gen.AddTx(types.NewTx(&types.DepositTx{
+			SourceHash: common.Hash{0x1},
+			From:       common.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001"),
+			To:         &types.L1BlockAddr,
+			Gas:        1_000_000,
+			Data:       l1Info,
+		}))
+		gen.AddTx(types.NewTx(&types.DepositTx{
+			SourceHash: common.Hash{0x2},
+			From:       addr,
+			To:         &common.Address{0x1},
+			Mint:       big.NewInt(params.GWei),
+			Value:      big.NewInt(params.GWei),
+			Gas:        100_000,
+		}))
+		tx, _ := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			Nonce:     1,
+			To:        &common.Address{0x2},
+			Gas:       params.TxGas,
+			GasFeeCap: big.NewInt(params.GWei),
+			GasTipCap: big.NewInt(1),
+		})
+		gen.AddTx(tx)
+	})
+	// start node
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	// The data availability fee is derived from the L1 attributes of the block.
+	txs := chain[0].Transactions()
+	gasParams, err := types.ExtractL1GasParams(&config, chain[0].Time(), txs[0].Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	l1Fee, l1GasUsed := gasParams.L1Cost(txs[2].RollupCostData())
+
+	for i, tt := range []struct {
+		body string
+		want string
+	}{
+		{
+			body: fmt.Sprintf(`{transaction(hash: "%s") { sourceHash mint isSystemTx depositNonce depositReceiptVersion l1Fee } }`, txs[1].Hash()),
+			want: `{"transaction":{"sourceHash":"0x0200000000000000000000000000000000000000000000000000000000000000","mint":"0x3b9aca00","isSystemTx":false,"depositNonce":"0x0","depositReceiptVersion":"0x1","l1Fee":null}}`,
+		},
+		{
+			body: fmt.Sprintf(`{transaction(hash: "%s") { sourceHash mint isSystemTx depositNonce l1Fee l1GasPrice l1GasUsed l1BlobBaseFee l1FeeScalar l1BaseFeeScalar l1BlobBaseFeeScalar } }`, txs[2].Hash()),
+			want: fmt.Sprintf(`{"transaction":{"sourceHash":null,"mint":null,"isSystemTx":null,"depositNonce":null,"l1Fee":"%s","l1GasPrice":"0x4a817c800","l1GasUsed":"%s","l1BlobBaseFee":"0x1","l1FeeScalar":null,"l1BaseFeeScalar":"0x558","l1BlobBaseFeeScalar":"0xc5fc5"}}`, (*hexutil.Big)(l1Fee), (*hexutil.Big)(l1GasUsed)),
+		},
+	} {
+		res := handler.Schema.Exec(context.Background(), tt.body, "", map[string]interface{}{})
+		if res.Errors != nil {
+			t.Fatalf("failed to execute query for testcase #%d: %v", i, res.Errors)
+		}
+		have, err := json.Marshal(res.Data)
+		if err != nil {
+			t.Fatalf("failed to encode graphql response for testcase #%d: %s", i, err)
+		}
+		if string(have) != tt.want {
+			t.Errorf("response unmatch for testcase #%d.\nhave:\n%s\nwant:\n%s", i, have, tt.want)
+		}
+	}
+}
+
 func createNode(t *testing.T) *node.Node {
 	stack, err := node.New(&node.Config{
 		HTTPHost:     "127.0.0.1",
@@ -466,12 +566,15 @@ func newGQLService(t *testing.T, stack *node.Node, shanghai bool, gspec *core.Ge
 		shanghaiTime := uint64(5)
 		chainCfg.ShanghaiTime = &shanghaiTime
 	}
+	if gspec.Config.Optimism != nil {
+		engine = beacon.NewFaker()
+	}
 	ethBackend, err := eth.New(stack, ethConf)
 	if err != nil {
 		t.Fatalf("could not create eth backend: %v", err)
 	}
 	// Create some blocks and import them
-	chain, _ := core.GenerateChain(params.AllEthashProtocolChanges, ethBackend.BlockChain().Genesis(),
+	chain, _ := core.GenerateChain(gspec.Config, ethBackend.BlockChain().Genesis(),
 		engine, ethBackend.ChainDb(), genBlocks, genfunc)
 	_, err = ethBackend.BlockChain().InsertChain(chain)
 	if err != nil {
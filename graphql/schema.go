@@ -170,6 +170,44 @@ const schema string = `
         rawReceipt: Bytes!
         # BlobVersionedHashes is a set of hash outputs from the blobs in the transaction.
         blobVersionedHashes: [Bytes32!]
+
+        # SourceHash uniquely identifies the source of a deposit transaction. This
+        # will be null for non-deposit transactions.
+        sourceHash: Bytes32
+        # Mint is the value, in wei, minted on L2 by a deposit transaction. This
+        # will be null for non-deposit transactions.
+        mint: BigInt
+        # IsSystemTx is true for the system transactions deposited before Regolith.
+        # This will be null for non-deposit transactions.
+        isSystemTx: Boolean
+        # DepositNonce is the nonce of the sender of a deposit transaction at the
+        # time it was executed. This will be null for non-deposit transactions, or
+        # if the transaction has not yet been mined.
+        depositNonce: Long
+        # DepositReceiptVersion is the version of the receipt of a deposit
+        # transaction, set since Canyon. This will be null for non-deposit
+        # transactions, or if the transaction has not yet been mined.
+        depositReceiptVersion: Long
+        # L1Fee is the data availability fee, in wei, charged to a non-deposit
+        # transaction. This will be null for deposit transactions, or if the
+        # transaction has not yet been mined.
+        l1Fee: BigInt
+        # L1GasPrice is the L1 base fee, in wei per unit, used for the data
+        # availability fee.
+        l1GasPrice: BigInt
+        # L1GasUsed is the amount of L1 gas the transaction data is charged for.
+        l1GasUsed: BigInt
+        # L1BlobBaseFee is the L1 blob base fee, in wei per unit, used for the data
+        # availability fee since Ecotone.
+        l1BlobBaseFee: BigInt
+        # L1FeeScalar is the scalar applied to the data availability fee before
+        # Ecotone, as a decimal number.
+        l1FeeScalar: String
+        # L1BaseFeeScalar is the scalar applied to the L1 base fee since Ecotone.
+        l1BaseFeeScalar: Long
+        # L1BlobBaseFeeScalar is the scalar applied to the L1 blob base fee since
+        # Ecotone.
+        l1BlobBaseFeeScalar: Long
     }
 
     # BlockFilterCriteria encapsulates log filter criteria for a filter applied
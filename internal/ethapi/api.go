@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net"
 	"strings"
 	"time"
 
@@ -38,6 +39,8 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -59,6 +62,8 @@ const estimateGasErrorRatio = 0.015
 
 var errBlobTxNotSupported = errors.New("signing blob transactions not supported")
 
+var errTxConditionalDisabled = errors.New("transaction conditionals not enabled")
+
 // EthereumAPI provides an API to access Ethereum related information.
 type EthereumAPI struct {
 	b Backend
@@ -281,6 +286,107 @@ func (api *TxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// Lifecycle statuses of a transaction reported by TxPoolAPI.TxStatus.
+const (
+	TxStatusUnknown  = "unknown"
+	TxStatusQueued   = "queued"
+	TxStatusPending  = "pending"
+	TxStatusIncluded = "included"
+	TxStatusReplaced = "replaced"
+	TxStatusDropped  = "dropped"
+)
+
+// TxStatusResult is the lifecycle status of a transaction as seen by the node,
+// along with the recent pool events explaining how it got there.
+type TxStatusResult struct {
+	Status      string              `json:"status"`
+	BlockNumber *hexutil.Uint64     `json:"blockNumber,omitempty"` // Set for included transactions
+	BlockHash   *common.Hash        `json:"blockHash,omitempty"`   // Set for included transactions
+	Reason      txpool.TxDropReason `json:"reason,omitempty"`      // Set for dropped transactions
+	ReplacedBy  *common.Hash        `json:"replacedBy,omitempty"`  // Set for replaced transactions
+	Events      []txpool.TxEvent    `json:"events"`
+}
+
+// TxStatus returns the lifecycle status of a transaction: whether it is queued or
+// pending in the pool, included in the chain, or why it left the pool otherwise.
+// The pool only remembers the events of a limited number of recent transactions.
+func (api *TxPoolAPI) TxStatus(ctx context.Context, hash common.Hash) (*TxStatusResult, error) {
+	result := &TxStatusResult{
+		Status: TxStatusUnknown,
+		Events: api.b.TxPoolHistory(hash),
+	}
+	if result.Events == nil {
+		result.Events = []txpool.TxEvent{}
+	}
+	found, _, blockHash, blockNumber, _, err := api.b.GetTransaction(ctx, hash)
+	if found {
+		number := hexutil.Uint64(blockNumber)
+		result.Status, result.BlockNumber, result.BlockHash = TxStatusIncluded, &number, &blockHash
+		return result, nil
+	}
+	switch api.b.TxPoolStatus(hash) {
+	case txpool.TxStatusQueued:
+		result.Status = TxStatusQueued
+	case txpool.TxStatusPending:
+		result.Status = TxStatusPending
+	default:
+		// Not in the pool anymore, report the last event of the transaction
+		if n := len(result.Events); n > 0 {
+			switch last := result.Events[n-1]; last.Type {
+			case txpool.TxEventDropped:
+				result.Status, result.Reason = TxStatusDropped, last.Reason
+			case txpool.TxEventReplaced:
+				result.Status, result.ReplacedBy = TxStatusReplaced, last.ReplacedBy
+			}
+		}
+		// The transaction may still be included, but not indexed yet
+		if result.Status == TxStatusUnknown && err != nil {
+			return nil, NewTxIndexingError()
+		}
+	}
+	return result, nil
+}
+
+// TxEvents creates a subscription that is triggered on each lifecycle event of a
+// transaction in the pool, optionally restricted to the given transactions.
+func (api *TxPoolAPI) TxEvents(ctx context.Context, hashes []common.Hash) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	var filter map[common.Hash]struct{}
+	if len(hashes) > 0 {
+		filter = make(map[common.Hash]struct{}, len(hashes))
+		for _, hash := range hashes {
+			filter[hash] = struct{}{}
+		}
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan txpool.TxEvent, 128)
+		sub := api.b.SubscribeTxPoolEvents(events)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				if filter != nil {
+					if _, ok := filter[ev.Hash]; !ok {
+						continue
+					}
+				}
+				notifier.Notify(rpcSub.ID, ev)
+			case <-rpcSub.Err():
+				return
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 // EthereumAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type EthereumAccountAPI struct {
@@ -670,17 +776,8 @@ func (api *BlockChainAPI) GetBalance(ctx context.Context, address common.Address
 		return nil, err
 	}
 
-	if api.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
-		if api.b.HistoricalRPCService() != nil {
-			var res hexutil.Big
-			err := api.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getBalance", address, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return &res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
-		}
+	if histResult, routed, err := RouteHistorical[*hexutil.Big](ctx, api.b.HistoricalRouter(), header.Number, "eth_getBalance", address, blockNrOrHash); routed {
+		return histResult, err
 	}
 
 	state, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -727,17 +824,8 @@ func (api *BlockChainAPI) GetProof(ctx context.Context, address common.Address,
 	if err != nil {
 		return nil, err
 	}
-	if api.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
-		if api.b.HistoricalRPCService() != nil {
-			var res AccountResult
-			err := api.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getProof", address, storageKeys, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return &res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
-		}
+	if histResult, routed, err := RouteHistorical[*AccountResult](ctx, api.b.HistoricalRouter(), header.Number, "eth_getProof", address, storageKeys, blockNrOrHash); routed {
+		return histResult, err
 	}
 	var (
 		keys         = make([]common.Hash, len(storageKeys))
@@ -949,17 +1037,8 @@ func (api *BlockChainAPI) GetCode(ctx context.Context, address common.Address, b
 		return nil, err
 	}
 
-	if api.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
-		if api.b.HistoricalRPCService() != nil {
-			var res hexutil.Bytes
-			err := api.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getCode", address, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
-		}
+	if histResult, routed, err := RouteHistorical[hexutil.Bytes](ctx, api.b.HistoricalRouter(), header.Number, "eth_getCode", address, blockNrOrHash); routed {
+		return histResult, err
 	}
 
 	state, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -980,17 +1059,8 @@ func (api *BlockChainAPI) GetStorageAt(ctx context.Context, address common.Addre
 		return nil, err
 	}
 
-	if api.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
-		if api.b.HistoricalRPCService() != nil {
-			var res hexutil.Bytes
-			err := api.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getStorageAt", address, hexKey, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
-		}
+	if histResult, routed, err := RouteHistorical[hexutil.Bytes](ctx, api.b.HistoricalRouter(), header.Number, "eth_getStorageAt", address, hexKey, blockNrOrHash); routed {
+		return histResult, err
 	}
 
 	state, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -1026,6 +1096,9 @@ func (api *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rp
 		// as per specification.
 		return nil, nil
 	}
+	if histResult, routed, err := RouteHistorical[[]map[string]interface{}](ctx, api.b.HistoricalRouter(), block.Number(), "eth_getBlockReceipts", blockNrOrHash); routed {
+		return histResult, err
+	}
 	receipts, err := api.b.GetReceipts(ctx, block.Hash())
 	if err != nil {
 		return nil, err
@@ -1053,11 +1126,12 @@ func (api *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rp
 // if stateDiff is set, all diff will be applied first and then execute the call
 // message.
 type OverrideAccount struct {
-	Nonce     *hexutil.Uint64             `json:"nonce"`
-	Code      *hexutil.Bytes              `json:"code"`
-	Balance   *hexutil.Big                `json:"balance"`
-	State     map[common.Hash]common.Hash `json:"state"`
-	StateDiff map[common.Hash]common.Hash `json:"stateDiff"`
+	Nonce            *hexutil.Uint64             `json:"nonce"`
+	Code             *hexutil.Bytes              `json:"code"`
+	Balance          *hexutil.Big                `json:"balance"`
+	State            map[common.Hash]common.Hash `json:"state"`
+	StateDiff        map[common.Hash]common.Hash `json:"stateDiff"`
+	MovePrecompileTo *common.Address             `json:"movePrecompileToAddress"` // Only honoured by eth_simulateV1
 }
 
 // StateOverride is the collection of overridden accounts.
@@ -1103,6 +1177,68 @@ func (diff *StateOverride) Apply(statedb *state.StateDB) error {
 	return nil
 }
 
+// overridePrecompiles records the precompiles affected by the state override in
+// the given set, on top of the precompiles active under the given rules. The
+// MovePrecompileTo feature makes it possible to move a precompile to another
+// address; if the target is another precompile, the latter is lost for this
+// session. Overriding the account of a precompile disables the precompile, so
+// that the account's code is executed instead. Entries of the set mapping to
+// nil mark precompiles which were disabled.
+func (diff *StateOverride) overridePrecompiles(rules params.Rules, set map[common.Address]vm.PrecompiledContract) error {
+	if diff == nil {
+		return nil
+	}
+	active := vm.ActivePrecompiledContracts(rules)
+	lookup := func(addr common.Address) (vm.PrecompiledContract, bool) {
+		if p, ok := set[addr]; ok {
+			return p, p != nil
+		}
+		p, ok := active[addr]
+		return p, ok
+	}
+	// Moves are applied after the loop, so the outcome does not depend on the
+	// map iteration order.
+	moved := make(map[common.Address]vm.PrecompiledContract)
+	for addr, account := range *diff {
+		p, isPrecompile := lookup(addr)
+		if account.MovePrecompileTo != nil {
+			if !isPrecompile {
+				return fmt.Errorf("account %s is not a precompile", addr.Hex())
+			}
+			// Refuse to move a precompile to an address that has been
+			// or will be overridden.
+			if _, ok := (*diff)[*account.MovePrecompileTo]; ok {
+				return fmt.Errorf("account %s is already overridden", account.MovePrecompileTo.Hex())
+			}
+			if _, ok := moved[*account.MovePrecompileTo]; ok {
+				return fmt.Errorf("account %s is the target of several precompiles", account.MovePrecompileTo.Hex())
+			}
+			moved[*account.MovePrecompileTo] = p
+		}
+		if isPrecompile {
+			set[addr] = nil
+		}
+	}
+	for addr, p := range moved {
+		set[addr] = p
+	}
+	return nil
+}
+
+// newPrecompileOverrides returns the vm.PrecompileOverrides applying the given
+// set of overridden precompiles, or nil if the set is empty.
+func newPrecompileOverrides(set map[common.Address]vm.PrecompiledContract) vm.PrecompileOverrides {
+	if len(set) == 0 {
+		return nil
+	}
+	return func(_ params.Rules, original vm.PrecompiledContract, addr common.Address) vm.PrecompiledContract {
+		if p, ok := set[addr]; ok {
+			return p
+		}
+		return original
+	}
+}
+
 // BlockOverrides is a set of header fields to override.
 type BlockOverrides struct {
 	Number      *hexutil.Big
@@ -1146,6 +1282,39 @@ func (diff *BlockOverrides) Apply(blockCtx *vm.BlockContext) {
 	}
 }
 
+// MakeHeader returns a new header object with the overridden
+// fields.
+// Note: MakeHeader ignores BlobBaseFee if set. That's because
+// header has no such field.
+func (diff *BlockOverrides) MakeHeader(header *types.Header) *types.Header {
+	if diff == nil {
+		return header
+	}
+	h := types.CopyHeader(header)
+	if diff.Number != nil {
+		h.Number = diff.Number.ToInt()
+	}
+	if diff.Difficulty != nil {
+		h.Difficulty = diff.Difficulty.ToInt()
+	}
+	if diff.Time != nil {
+		h.Time = uint64(*diff.Time)
+	}
+	if diff.GasLimit != nil {
+		h.GasLimit = uint64(*diff.GasLimit)
+	}
+	if diff.Coinbase != nil {
+		h.Coinbase = *diff.Coinbase
+	}
+	if diff.Random != nil {
+		h.MixDigest = *diff.Random
+	}
+	if diff.BaseFee != nil {
+		h.BaseFee = diff.BaseFee.ToInt()
+	}
+	return h
+}
+
 // ChainContextBackend provides methods required to implement ChainContext.
 type ChainContextBackend interface {
 	Engine() consensus.Engine
@@ -1205,6 +1374,11 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 	msg := args.ToMessage(blockCtx.BaseFee)
 	evm := b.GetEVM(ctx, msg, state, header, &vm.Config{NoBaseFee: true}, &blockCtx)
 
+	gp := new(core.GasPool).AddGas(math.MaxUint64)
+	return applyMessageWithEVM(ctx, evm, msg, state, timeout, gp)
+}
+
+func applyMessageWithEVM(ctx context.Context, evm *vm.EVM, msg *core.Message, state *state.StateDB, timeout time.Duration, gp *core.GasPool) (*core.ExecutionResult, error) {
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
 	go func() {
@@ -1213,7 +1387,6 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 	}()
 
 	// Execute the message.
-	gp := new(core.GasPool).AddGas(math.MaxUint64)
 	result, err := core.ApplyMessage(evm, msg, gp)
 	if err := state.Error(); err != nil {
 		return nil, err
@@ -1257,17 +1430,8 @@ func (api *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockN
 		return nil, err
 	}
 
-	if api.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
-		if api.b.HistoricalRPCService() != nil {
-			var res hexutil.Bytes
-			err := api.b.HistoricalRPCService().CallContext(ctx, &res, "eth_call", args, blockNrOrHash, overrides)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
-		}
+	if histResult, routed, err := RouteHistorical[hexutil.Bytes](ctx, api.b.HistoricalRouter(), header.Number, "eth_call", args, blockNrOrHash, overrides); routed {
+		return histResult, err
 	}
 
 	result, err := DoCall(ctx, api.b, args, *blockNrOrHash, overrides, blockOverrides, api.b.RPCEVMTimeout(), api.b.RPCGasCap())
@@ -1281,6 +1445,50 @@ func (api *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockN
 	return result.Return(), result.Err
 }
 
+// SimulateV1 executes series of transactions on top of a base state.
+// The transactions are packed into blocks. For each block, block header
+// fields can be overridden. The state can also be overridden prior to
+// execution of each block.
+//
+// Note, this function doesn't make any changes in the state/blockchain and is
+// useful to execute and retrieve values.
+func (api *BlockChainAPI) SimulateV1(ctx context.Context, opts simOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, &invalidParamsError{message: "empty input"}
+	} else if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, &clientLimitExceededError{message: "too many blocks"}
+	}
+	if blockNrOrHash == nil {
+		n := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &n
+	}
+	state, base, err := api.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	// The legacy l2geth does not support eth_simulateV1.
+	if api.b.ChainConfig().IsOptimismPreBedrock(base.Number) {
+		return nil, rpc.ErrNoHistoricalFallback
+	}
+	gasCap := api.b.RPCGasCap()
+	if gasCap == 0 {
+		gasCap = math.MaxUint64
+	}
+	sim := &simulator{
+		b:           api.b,
+		state:       state,
+		base:        base,
+		chainConfig: api.b.ChainConfig(),
+		// Each tx and all the series of txes shouldn't consume more gas than cap
+		gp:             new(core.GasPool).AddGas(gasCap),
+		traceTransfers: opts.TraceTransfers,
+		validate:       opts.Validation,
+		fullTx:         opts.ReturnFullTransactions,
+		precompiles:    make(map[common.Address]vm.PrecompiledContract),
+	}
+	return sim.execute(ctx, opts.BlockStateCalls)
+}
+
 // DoEstimateGas returns the lowest possible gas limit that allows the transaction to run
 // successfully at block `blockNrOrHash`. It returns error if the transaction would revert, or if
 // there are unexpected failures. The gas limit is capped by both `args.Gas` (if non-nil &
@@ -1294,6 +1502,12 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	if err = overrides.Apply(state); err != nil {
 		return 0, err
 	}
+	return doEstimateGas(ctx, b, args, state, header, gasCap)
+}
+
+// doEstimateGas estimates the gas of a transaction on top of the given state and
+// header, with any state overrides applied already.
+func doEstimateGas(ctx context.Context, b Backend, args TransactionArgs, state *state.StateDB, header *types.Header, gasCap uint64) (hexutil.Uint64, error) {
 	// Construct the gas estimator option from the user input
 	opts := &gasestimator.Options{
 		Config:     b.ChainConfig(),
@@ -1340,22 +1554,83 @@ func (api *BlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs,
 		return 0, err
 	}
 
-	if api.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
-		if api.b.HistoricalRPCService() != nil {
-			var res hexutil.Uint64
-			err := api.b.HistoricalRPCService().CallContext(ctx, &res, "eth_estimateGas", args, blockNrOrHash)
-			if err != nil {
-				return 0, fmt.Errorf("historical backend error: %w", err)
-			}
-			return res, nil
-		} else {
-			return 0, rpc.ErrNoHistoricalFallback
-		}
+	if histResult, routed, err := RouteHistorical[hexutil.Uint64](ctx, api.b.HistoricalRouter(), header.Number, "eth_estimateGas", args, blockNrOrHash); routed {
+		return histResult, err
 	}
 
 	return DoEstimateGas(ctx, api.b, args, bNrOrHash, overrides, api.b.RPCGasCap())
 }
 
+// l1FeeEstimate is the result of an eth_estimateL1Fee call.
+type l1FeeEstimate struct {
+	Gas     hexutil.Uint64 `json:"gas"`
+	L1Fee   *hexutil.Big   `json:"l1Fee"`
+	MaxCost *hexutil.Big   `json:"maxCost"`
+}
+
+// EstimateL1Fee estimates the total cost of a transaction on an OP-stack chain at
+// block `blockNrOrHash`, or the latest block if `blockNrOrHash` is unspecified. It
+// returns the L2 execution gas as estimated by EstimateGas, the L1 data fee of the
+// transaction, and the maximum cost charged to the sender: the gas limit at the fee
+// cap, plus the value and the L1 data fee.
+//
+// Unspecified fee parameters and nonce are filled in as they would be by
+// eth_sendTransaction. The transaction doesn't need to be signed, the L1 data fee
+// accounts for the size of a signature.
+func (api *BlockChainAPI) EstimateL1Fee(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, overrides *StateOverride) (*l1FeeEstimate, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	state, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	// The legacy l2geth does not support eth_estimateL1Fee.
+	if api.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
+		return nil, rpc.ErrNoHistoricalFallback
+	}
+	if err := overrides.Apply(state); err != nil {
+		return nil, err
+	}
+	// Estimate the gas and fill in the defaults on the resolved block, which may
+	// not be the head of the chain anymore.
+	gas, err := doEstimateGas(ctx, api.b, args, state, header, api.b.RPCGasCap())
+	if err != nil {
+		return nil, err
+	}
+	// Assemble the transaction as it would be sent, to compute its L1 data fee.
+	args.Gas = &gas
+	if err := args.setFeeDefaults(ctx, api.b, header); err != nil {
+		return nil, err
+	}
+	if args.Value == nil {
+		args.Value = new(hexutil.Big)
+	}
+	if args.Nonce == nil {
+		nonce := state.GetNonce(args.from())
+		args.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	if args.ChainID == nil {
+		args.ChainID = (*hexutil.Big)(api.b.ChainConfig().ChainID)
+	}
+	tx, err := args.ToTransaction().WithPlaceholderSignature(types.LatestSigner(api.b.ChainConfig()))
+	if err != nil {
+		return nil, err
+	}
+	l1Fee := new(big.Int)
+	if costFunc := types.NewL1CostFunc(api.b.ChainConfig(), state); costFunc != nil {
+		if fee := costFunc(tx.RollupCostData(), header.Time); fee != nil {
+			l1Fee = fee
+		}
+	}
+	return &l1FeeEstimate{
+		Gas:     gas,
+		L1Fee:   (*hexutil.Big)(l1Fee),
+		MaxCost: (*hexutil.Big)(new(big.Int).Add(tx.Cost(), l1Fee)),
+	}, nil
+}
+
 // RPCMarshalHeader converts the given header to the RPC output .
 func RPCMarshalHeader(head *types.Header) map[string]interface{} {
 	result := map[string]interface{}{
@@ -1659,16 +1934,9 @@ func (api *BlockChainAPI) CreateAccessList(ctx context.Context, args Transaction
 	}
 
 	header, err := headerByNumberOrHash(ctx, api.b, bNrOrHash)
-	if err == nil && header != nil && api.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
-		if api.b.HistoricalRPCService() != nil {
-			var res accessListResult
-			err := api.b.HistoricalRPCService().CallContext(ctx, &res, "eth_createAccessList", args, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return &res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+	if err == nil && header != nil {
+		if histResult, routed, err := RouteHistorical[*accessListResult](ctx, api.b.HistoricalRouter(), header.Number, "eth_createAccessList", args, blockNrOrHash); routed {
+			return histResult, err
 		}
 	}
 
@@ -1822,17 +2090,8 @@ func (api *TransactionAPI) GetTransactionCount(ctx context.Context, address comm
 		return nil, err
 	}
 
-	if api.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
-		if api.b.HistoricalRPCService() != nil {
-			var res hexutil.Uint64
-			err := api.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getTransactionCount", address, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return &res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
-		}
+	if histResult, routed, err := RouteHistorical[*hexutil.Uint64](ctx, api.b.HistoricalRouter(), header.Number, "eth_getTransactionCount", address, blockNrOrHash); routed {
+		return histResult, err
 	}
 
 	state, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -2001,6 +2260,9 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 		// Ensure only eip155 signed transactions are submitted if EIP155Required is set.
 		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
 	}
+	if origin := rpcOrigin(ctx); origin != "" {
+		ctx = txpool.WithOrigin(ctx, origin)
+	}
 	if err := b.SendTx(ctx, tx); err != nil {
 		return common.Hash{}, err
 	}
@@ -2021,6 +2283,26 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 	return tx.Hash(), nil
 }
 
+// rpcOrigin returns the IP address of the remote RPC client of the context, to
+// rate limit its transactions by. Local IPC clients have no origin.
+//
+// The address is the one forwarded by a trusted proxy if any, otherwise the one
+// of the TCP peer, shared by all the clients behind an untrusted proxy.
+func rpcOrigin(ctx context.Context) string {
+	info := rpc.PeerInfoFromContext(ctx)
+	if info.Transport != "http" && info.Transport != "ws" {
+		return ""
+	}
+	if info.HTTP.ForwardedFor != "" {
+		return info.HTTP.ForwardedFor
+	}
+	host, _, err := net.SplitHostPort(info.RemoteAddr)
+	if err != nil {
+		return info.RemoteAddr
+	}
+	return host
+}
+
 // SendTransaction creates a transaction for the given argument, sign it and submit it to the
 // transaction pool.
 func (api *TransactionAPI) SendTransaction(ctx context.Context, args TransactionArgs) (common.Hash, error) {
@@ -2085,6 +2367,64 @@ func (api *TransactionAPI) SendRawTransaction(ctx context.Context, input hexutil
 	return SubmitTransaction(ctx, api.b, tx)
 }
 
+// SendRawTransactionConditional will add the signed transaction to the transaction
+// pool, together with preconditions for its inclusion. The conditional is checked
+// against the head of the chain, and then enforced by the sequencer during block
+// building. Replicas forward the transaction and its conditional to the sequencer.
+//
+// The method is only available if enabled by the node operator, as checking the
+// conditionals is expensive.
+func (api *TransactionAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, cond types.TransactionConditional) (common.Hash, error) {
+	maxCost := api.b.RPCTxConditionalMaxCost()
+	if maxCost == 0 {
+		return common.Hash{}, errTxConditionalDisabled
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if err := cond.Validate(); err != nil {
+		return common.Hash{}, &invalidParamsError{message: err.Error()}
+	}
+	if cost := cond.Cost(); uint64(cost) > maxCost {
+		return common.Hash{}, &txConditionalCostExceededError{message: fmt.Sprintf("conditional cost %d exceeds maximum %d", cost, maxCost)}
+	}
+	state, header, err := api.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return common.Hash{}, err
+	}
+	if err := header.CheckTransactionConditional(&cond); err != nil {
+		return common.Hash{}, &txConditionalRejectedError{message: err.Error()}
+	}
+	if err := state.CheckTransactionConditional(&cond); err != nil {
+		return common.Hash{}, &txConditionalRejectedError{message: err.Error()}
+	}
+	tx.SetConditional(&cond)
+	return SubmitTransaction(ctx, api.b, tx)
+}
+
+// SendBundle adds a bundle of signed transactions to the bundle pool, for the
+// block builder to include them together, in order, or not at all. Replicas
+// forward the bundle to the sequencer. The bundle hash is returned.
+func (api *TransactionAPI) SendBundle(ctx context.Context, bundle bundlepool.Bundle) (common.Hash, error) {
+	for i, tx := range bundle.Txs {
+		if err := checkTxFee(tx.GasPrice(), tx.Gas(), api.b.RPCTxFeeCap()); err != nil {
+			return common.Hash{}, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		if !api.b.UnprotectedAllowed() && !tx.Protected() {
+			return common.Hash{}, fmt.Errorf("transaction %d: only replay-protected (EIP-155) transactions allowed over RPC", i)
+		}
+	}
+	if origin := rpcOrigin(ctx); origin != "" {
+		ctx = txpool.WithOrigin(ctx, origin)
+	}
+	if err := api.b.SendBundle(ctx, &bundle); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted bundle", "hash", bundle.Hash(), "txs", len(bundle.Txs))
+	return bundle.Hash(), nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccak256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
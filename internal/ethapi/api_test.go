@@ -21,10 +21,13 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http/httptest"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -43,10 +46,13 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -637,6 +643,7 @@ func (b testBackend) ExtRPCEnabled() bool                      { return false }
 func (b testBackend) RPCGasCap() uint64                        { return 10000000 }
 func (b testBackend) RPCEVMTimeout() time.Duration             { return time.Second }
 func (b testBackend) RPCTxFeeCap() float64                     { return 0 }
+func (b testBackend) RPCTxConditionalMaxCost() uint64          { return 1000 }
 func (b testBackend) UnprotectedAllowed() bool                 { return false }
 func (b testBackend) SetHead(number uint64)                    {}
 func (b testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
@@ -745,6 +752,9 @@ func (b testBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) even
 func (b testBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	panic("implement me")
 }
+func (b testBackend) SendBundle(ctx context.Context, bundle *bundlepool.Bundle) error {
+	panic("implement me")
+}
 func (b testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(b.db, txHash)
 	return true, tx, blockHash, blockNumber, index, nil
@@ -764,6 +774,11 @@ func (b testBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transactio
 func (b testBackend) SubscribeNewTxsEvent(events chan<- core.NewTxsEvent) event.Subscription {
 	panic("implement me")
 }
+func (b testBackend) TxPoolStatus(hash common.Hash) txpool.TxStatus   { panic("implement me") }
+func (b testBackend) TxPoolHistory(hash common.Hash) []txpool.TxEvent { panic("implement me") }
+func (b testBackend) SubscribeTxPoolEvents(ch chan<- txpool.TxEvent) event.Subscription {
+	panic("implement me")
+}
 func (b testBackend) ChainConfig() *params.ChainConfig { return b.chain.Config() }
 func (b testBackend) Engine() consensus.Engine         { return b.chain.Engine() }
 func (b testBackend) GetLogs(ctx context.Context, blockHash common.Hash, number uint64) ([][]*types.Log, error) {
@@ -779,8 +794,8 @@ func (b testBackend) BloomStatus() (uint64, uint64) { panic("implement me") }
 func (b testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	panic("implement me")
 }
-func (b testBackend) HistoricalRPCService() *rpc.Client {
-	panic("implement me")
+func (b testBackend) HistoricalRouter() *HistoricalRouter {
+	return NewHistoricalRouter(nil, b.chain.Config())
 }
 func (b testBackend) Genesis() *types.Block {
 	panic("implement me")
@@ -950,6 +965,7 @@ func TestCall(t *testing.T) {
 	var (
 		accounts = newAccounts(3)
 		dad      = common.HexToAddress("0x0000000000000000000000000000000000000dad")
+		identity = common.BytesToAddress([]byte{0x4})
 		genesis  = &core.Genesis{
 			Config: params.MergedTestChainConfig,
 			Alloc: types.GenesisAlloc{
@@ -1148,6 +1164,32 @@ func TestCall(t *testing.T) {
 			},
 			want: "0x0000000000000000000000000000000000000000000000000000000000000000",
 		},
+		// Overriding the code of a precompile does not disable it
+		{
+			blockNumber: rpc.LatestBlockNumber,
+			call: TransactionArgs{
+				From:  &accounts[1].addr,
+				To:    &identity,
+				Input: hex2Bytes("deadbeef"),
+			},
+			overrides: StateOverride{
+				identity: OverrideAccount{Code: hex2Bytes("60006000fd")},
+			},
+			want: "0xdeadbeef",
+		},
+		// Precompiles are only moved by eth_simulateV1
+		{
+			blockNumber: rpc.LatestBlockNumber,
+			call: TransactionArgs{
+				From:  &accounts[1].addr,
+				To:    &randomAccounts[0].addr,
+				Input: hex2Bytes("deadbeef"),
+			},
+			overrides: StateOverride{
+				identity: OverrideAccount{MovePrecompileTo: &randomAccounts[0].addr},
+			},
+			want: "0x",
+		},
 	}
 	for i, tc := range testSuite {
 		result, err := api.Call(context.Background(), tc.call, &rpc.BlockNumberOrHash{BlockNumber: &tc.blockNumber}, &tc.overrides, &tc.blockOverrides)
@@ -1174,6 +1216,401 @@ func TestCall(t *testing.T) {
 	}
 }
 
+func TestSimulateV1(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		// reverter reverts with empty data
+		reverter = common.HexToAddress("0x00000000000000000000000000000000000000fe")
+		moved    = common.HexToAddress("0x0000000000000000000000000000000000001234")
+		identity = common.BytesToAddress([]byte{0x4})
+		genesis  = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: types.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+				reverter:         {Code: []byte{byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.REVERT)}},
+			},
+		}
+		genBlocks = 2
+	)
+	backend := newTestBackend(t, genBlocks, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	})
+	api := NewBlockChainAPI(backend)
+	simulate := func(opts simOpts) ([]map[string]interface{}, error) {
+		return api.SimulateV1(context.Background(), opts, nil)
+	}
+	calls := func(block map[string]interface{}) []simCallResult {
+		return block["calls"].([]simCallResult)
+	}
+	number := func(block map[string]interface{}) uint64 {
+		return block["number"].(*hexutil.Big).ToInt().Uint64()
+	}
+
+	// Ether transfers are reported as logs if requested.
+	res, err := simulate(simOpts{
+		TraceTransfers: true,
+		BlockStateCalls: []simBlock{{Calls: []TransactionArgs{{
+			From:  &accounts[0].addr,
+			To:    &accounts[1].addr,
+			Value: (*hexutil.Big)(big.NewInt(1000)),
+		}}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, uint64(genBlocks+1), number(res[0]))
+	call := calls(res[0])[0]
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), call.Status)
+	require.Equal(t, hexutil.Uint64(params.TxGas), call.GasUsed)
+	require.Len(t, call.Logs, 1)
+	require.Equal(t, transferAddress, call.Logs[0].Address)
+	require.Equal(t, common.BytesToHash(accounts[1].addr.Bytes()), call.Logs[0].Topics[2])
+	require.Equal(t, res[0]["hash"], call.Logs[0].BlockHash)
+
+	// Gaps between the simulated blocks are filled with empty blocks, and the
+	// state carries over.
+	n := hexutil.Big(*big.NewInt(int64(genBlocks) + 4))
+	res, err = simulate(simOpts{
+		BlockStateCalls: []simBlock{
+			{Calls: []TransactionArgs{{From: &accounts[0].addr, To: &accounts[1].addr, Value: (*hexutil.Big)(big.NewInt(1000))}}},
+			{BlockOverrides: &BlockOverrides{Number: &n}, Calls: []TransactionArgs{{From: &accounts[1].addr, To: &accounts[0].addr, Value: (*hexutil.Big)(big.NewInt(1000))}}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, res, 4)
+	for i, block := range res {
+		require.Equal(t, uint64(genBlocks+1+i), number(block))
+		if i > 0 {
+			require.Equal(t, res[i-1]["hash"], block["parentHash"])
+			require.Greater(t, block["timestamp"], res[i-1]["timestamp"])
+		}
+	}
+	require.Len(t, calls(res[1]), 0)
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), calls(res[3])[0].Status)
+
+	// Precompiles can be moved, after which the original address has no code.
+	res, err = simulate(simOpts{
+		BlockStateCalls: []simBlock{{
+			StateOverrides: &StateOverride{identity: OverrideAccount{MovePrecompileTo: &moved}},
+			Calls: []TransactionArgs{
+				{From: &accounts[0].addr, To: &moved, Input: hex2Bytes("deadbeef")},
+				{From: &accounts[0].addr, To: &identity, Input: hex2Bytes("deadbeef")},
+			},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Bytes{0xde, 0xad, 0xbe, 0xef}, calls(res[0])[0].ReturnValue)
+	require.Empty(t, calls(res[0])[1].ReturnValue)
+
+	// Reverts are reported per call.
+	res, err = simulate(simOpts{
+		BlockStateCalls: []simBlock{{Calls: []TransactionArgs{{From: &accounts[0].addr, To: &reverter}}}},
+	})
+	require.NoError(t, err)
+	call = calls(res[0])[0]
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusFailed), call.Status)
+	require.Equal(t, errCodeReverted, call.Error.Code)
+
+	// In validation mode, invalid transactions fail the whole simulation.
+	var (
+		nonce  = hexutil.Uint64(1)
+		feeCap = (*hexutil.Big)(big.NewInt(params.GWei))
+	)
+	_, err = simulate(simOpts{
+		Validation:      true,
+		BlockStateCalls: []simBlock{{Calls: []TransactionArgs{{From: &accounts[0].addr, To: &accounts[1].addr, Nonce: &nonce, MaxFeePerGas: feeCap}}}},
+	})
+	var txErr *invalidTxError
+	require.ErrorAs(t, err, &txErr)
+	require.Equal(t, errCodeNonceTooHigh, txErr.ErrorCode())
+
+	// Without validation, the nonce is not checked.
+	_, err = simulate(simOpts{
+		BlockStateCalls: []simBlock{{Calls: []TransactionArgs{{From: &accounts[0].addr, To: &accounts[1].addr, Nonce: &nonce}}}},
+	})
+	require.NoError(t, err)
+
+	// A zero gas cap leaves the calls uncapped.
+	res, err = NewBlockChainAPI(uncappedBackend{backend}).SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{{Calls: []TransactionArgs{{From: &accounts[0].addr, To: &accounts[1].addr}}}},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), calls(res[0])[0].Status)
+}
+
+// uncappedBackend is a test backend without a gas cap for the RPC calls.
+type uncappedBackend struct {
+	*testBackend
+}
+
+func (b uncappedBackend) RPCGasCap() uint64 { return 0 }
+
+func TestSimulateV1L1Fee(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		config   = *params.MergedTestChainConfig
+		zeroTime = uint64(0)
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.RegolithTime = &zeroTime
+	config.Optimism = params.OptimismTestConfig.Optimism
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			types.L1BlockAddr: {
+				Code: []byte{byte(vm.STOP)},
+				Storage: map[common.Hash]common.Hash{
+					types.L1BaseFeeSlot: common.BigToHash(big.NewInt(params.GWei)),
+					types.OverheadSlot:  common.BigToHash(big.NewInt(188)),
+					types.ScalarSlot:    common.BigToHash(big.NewInt(684000)),
+				},
+			},
+		},
+	}
+	api := NewBlockChainAPI(newTestBackend(t, 1, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	}))
+	simulate := func(validation bool) simCallResult {
+		res, err := api.SimulateV1(context.Background(), simOpts{
+			Validation: validation,
+			BlockStateCalls: []simBlock{{Calls: []TransactionArgs{{
+				From:         &accounts[0].addr,
+				To:           &accounts[1].addr,
+				Value:        (*hexutil.Big)(big.NewInt(1000)),
+				MaxFeePerGas: (*hexutil.Big)(big.NewInt(params.GWei)),
+			}}}},
+		}, nil)
+		require.NoError(t, err)
+		return res[0]["calls"].([]simCallResult)[0]
+	}
+	// The L1 data fee is reported, whether it's charged or not.
+	call := simulate(false)
+	require.NotNil(t, call.L1Fee)
+	require.Positive(t, call.L1Fee.ToInt().Sign())
+	require.Equal(t, call.L1Fee, simulate(true).L1Fee)
+}
+
+func TestEstimateL1Fee(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _   = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		from     = crypto.PubkeyToAddress(key.PublicKey)
+		config   = *params.MergedTestChainConfig
+		zeroTime = uint64(0)
+		denom    = uint64(250)
+		scalars  common.Hash
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.RegolithTime, config.CanyonTime, config.EcotoneTime, config.FjordTime = &zeroTime, &zeroTime, &zeroTime, &zeroTime
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50, EIP1559DenominatorCanyon: &denom}
+	binary.BigEndian.PutUint32(scalars[32-types.BaseFeeScalarSlotOffset-4:], 1368)
+	binary.BigEndian.PutUint32(scalars[32-types.BlobBaseFeeScalarSlotOffset-4:], 810949)
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc: types.GenesisAlloc{
+			from: {Balance: big.NewInt(params.Ether)},
+			types.L1BlockAddr: {
+				Code: []byte{byte(vm.STOP)},
+				Storage: map[common.Hash]common.Hash{
+					types.L1BaseFeeSlot:     common.BigToHash(big.NewInt(20 * params.GWei)),
+					types.L1BlobBaseFeeSlot: common.BigToHash(big.NewInt(params.GWei)),
+					types.L1FeeScalarsSlot:  scalars,
+				},
+			},
+		},
+	}
+	backend := newTestBackend(t, 1, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	})
+	api := NewBlockChainAPI(backend)
+
+	data := hexutil.Bytes(crypto.Keccak512(make([]byte, 64), []byte("calldata"))) // incompressible calldata
+	data = append(data, make([]byte, 128)...)
+	args := TransactionArgs{
+		From:                 &from,
+		To:                   &common.Address{0x1},
+		Value:                (*hexutil.Big)(big.NewInt(1000)),
+		MaxFeePerGas:         (*hexutil.Big)(big.NewInt(2 * params.GWei)),
+		MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(params.GWei)),
+		Input:                &data,
+	}
+	res, err := api.EstimateL1Fee(context.Background(), args, nil, nil)
+	require.NoError(t, err)
+
+	// The L1 data fee is close to the one of the signed transaction, as the
+	// placeholder signature compresses like an actual one.
+	tx, err := types.SignNewTx(key, types.LatestSigner(&config), &types.DynamicFeeTx{
+		ChainID:   config.ChainID,
+		Gas:       uint64(res.Gas),
+		GasFeeCap: big.NewInt(2 * params.GWei),
+		GasTipCap: big.NewInt(params.GWei),
+		To:        &common.Address{0x1},
+		Value:     big.NewInt(1000),
+		Data:      data,
+	})
+	require.NoError(t, err)
+	state, head, err := backend.StateAndHeaderByNumber(context.Background(), rpc.LatestBlockNumber)
+	require.NoError(t, err)
+	want := types.NewL1CostFunc(&config, state)(tx.RollupCostData(), head.Time)
+
+	require.Greater(t, uint64(res.Gas), params.TxGas)
+	require.Positive(t, want.Sign())
+	diff := new(big.Int).Sub(res.L1Fee.ToInt(), want)
+	require.LessOrEqual(t, new(big.Int).Abs(diff).Cmp(new(big.Int).Div(want, big.NewInt(50))), 0, "L1 fee %v too far off %v", res.L1Fee, want)
+	require.Equal(t, new(big.Int).Add(tx.Cost(), res.L1Fee.ToInt()), res.MaxCost.ToInt())
+
+	// The fee defaults are derived from the requested block, not the head.
+	args.MaxFeePerGas, args.MaxPriorityFeePerGas = nil, nil
+	number := rpc.BlockNumberOrHashWithNumber(0)
+	res, err = api.EstimateL1Fee(context.Background(), args, &number, nil)
+	require.NoError(t, err)
+
+	parent := backend.chain.GetHeaderByNumber(0)
+	require.NotEqual(t, 0, parent.BaseFee.Cmp(head.BaseFee))
+	tip, err := backend.SuggestGasTipCap(context.Background())
+	require.NoError(t, err)
+	feeCap := new(big.Int).Add(tip, new(big.Int).Mul(parent.BaseFee, big.NewInt(2)))
+	cost := new(big.Int).Mul(feeCap, new(big.Int).SetUint64(uint64(res.Gas)))
+	cost.Add(cost, big.NewInt(1000))
+	require.Equal(t, cost.Add(cost, res.L1Fee.ToInt()), res.MaxCost.ToInt())
+}
+
+func TestL1Attributes(t *testing.T) {
+	t.Parallel()
+
+	var (
+		config   = *params.MergedTestChainConfig
+		zeroTime = uint64(0)
+		denom    = uint64(250)
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.RegolithTime, config.CanyonTime, config.EcotoneTime, config.FjordTime, config.GraniteTime, config.HoloceneTime = &zeroTime, &zeroTime, &zeroTime, &zeroTime, &zeroTime, &zeroTime
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50, EIP1559DenominatorCanyon: &denom}
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc: types.GenesisAlloc{
+			types.L1BlockAddr: {Code: []byte{byte(vm.STOP)}, Balance: common.Big0},
+		},
+	}
+	// Ecotone L1 attributes of the L1 block 100 at time 10, with a base fee
+	// scalar of 1368, a blob base fee scalar of 810949, an L1 base fee of 20 gwei
+	// and an L1 blob base fee of 1.
+	l1Info := make([]byte, 164)
+	copy(l1Info, types.EcotoneL1AttributesSelector)
+	binary.BigEndian.PutUint32(l1Info[4:8], 1368)
+	binary.BigEndian.PutUint32(l1Info[8:12], 810949)
+	binary.BigEndian.PutUint64(l1Info[12:20], 3)
+	binary.BigEndian.PutUint64(l1Info[20:28], 10)
+	binary.BigEndian.PutUint64(l1Info[28:36], 100)
+	big.NewInt(20 * params.GWei).FillBytes(l1Info[36:68])
+	big.NewInt(1).FillBytes(l1Info[68:100])
+	copy(l1Info[100:132], common.Hash{0x01}.Bytes())
+	copy(l1Info[132:164], common.Hash{0x02}.Bytes())
+
+	backend := newTestBackend(t, 1, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+		b.SetExtra(eip1559.EncodeHoloceneExtraData(100, 4))
+		b.AddTx(types.NewTx(&types.DepositTx{
+			SourceHash: common.Hash{0x1},
+			From:       common.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001"),
+			To:         &types.L1BlockAddr,
+			Gas:        1_000_000,
+			Data:       l1Info,
+		}))
+	})
+	api := NewOptimismAPI(backend)
+
+	res, err := api.L1Attributes(context.Background(), rpc.BlockNumberOrHashWithNumber(1))
+	require.NoError(t, err)
+	head := backend.chain.CurrentBlock()
+
+	baseFeeScalar, blobBaseFeeScalar := hexutil.Uint64(1368), hexutil.Uint64(810949)
+	require.Equal(t, &L1AttributesResult{
+		BlockNumber:        1,
+		BlockHash:          head.Hash(),
+		L1Number:           100,
+		L1Timestamp:        10,
+		L1BlockHash:        common.Hash{0x01},
+		L1OriginAge:        hexutil.Uint64(head.Time - 10),
+		SequenceNumber:     3,
+		BatcherHash:        common.Hash{0x02},
+		L1BaseFee:          (*hexutil.Big)(big.NewInt(20 * params.GWei)),
+		L1BlobBaseFee:      (*hexutil.Big)(big.NewInt(1)),
+		BaseFeeScalar:      &baseFeeScalar,
+		BlobBaseFeeScalar:  &blobBaseFeeScalar,
+		L1CostFunction:     L1CostFunctionFjord,
+		EIP1559Denominator: 100,
+		EIP1559Elasticity:  4,
+	}, res)
+
+	// The genesis block has no L1 attributes.
+	_, err = api.L1Attributes(context.Background(), rpc.BlockNumberOrHashWithNumber(0))
+	require.ErrorContains(t, err, "no L1 attributes deposit")
+}
+
+func TestSendRawTransactionConditional(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _  = crypto.GenerateKey()
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		known   = common.HexToAddress("0xc0de")
+		slot    = common.HexToHash("0x1")
+		genesis = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: types.GenesisAlloc{
+				addr:  {Balance: big.NewInt(params.Ether)},
+				known: {Code: []byte{byte(vm.STOP)}, Storage: map[common.Hash]common.Hash{slot: common.HexToHash("0x2")}},
+			},
+		}
+	)
+	b := newTestBackend(t, 2, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	})
+	api := NewTransactionAPI(b, nil)
+
+	tx, _ := types.SignNewTx(key, types.LatestSigner(genesis.Config), &types.DynamicFeeTx{ChainID: genesis.Config.ChainID, Gas: params.TxGas, To: &addr})
+	raw, _ := tx.MarshalBinary()
+
+	manySlots := make(map[common.Hash]common.Hash)
+	for i := 0; i <= int(b.RPCTxConditionalMaxCost()); i++ {
+		manySlots[common.BigToHash(big.NewInt(int64(i)))] = common.Hash{}
+	}
+	tests := []struct {
+		cond types.TransactionConditional
+		code int
+	}{
+		{types.TransactionConditional{BlockNumberMax: big.NewInt(1)}, errCodeTxConditionalRejected},
+		{types.TransactionConditional{KnownAccounts: types.KnownAccounts{known: {StorageSlots: map[common.Hash]common.Hash{slot: common.HexToHash("0x3")}}}}, errCodeTxConditionalRejected},
+		{types.TransactionConditional{KnownAccounts: types.KnownAccounts{known: {StorageSlots: manySlots}}}, errCodeTxConditionalCostExceeded},
+		{types.TransactionConditional{BlockNumberMin: big.NewInt(2), BlockNumberMax: big.NewInt(1)}, errCodeInvalidParams},
+	}
+	for i, tt := range tests {
+		_, err := api.SendRawTransactionConditional(context.Background(), raw, tt.cond)
+		var rpcErr interface{ ErrorCode() int }
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != tt.code {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+	}
+	// Conditionals are refused altogether unless enabled by the node operator
+	api = NewTransactionAPI(noConditionalBackend{b}, nil)
+	if _, err := api.SendRawTransactionConditional(context.Background(), raw, types.TransactionConditional{}); !errors.Is(err, errTxConditionalDisabled) {
+		t.Errorf("unexpected error with conditionals disabled: %v", err)
+	}
+}
+
+// noConditionalBackend is a test backend not accepting transaction conditionals.
+type noConditionalBackend struct {
+	*testBackend
+}
+
+func (b noConditionalBackend) RPCTxConditionalMaxCost() uint64 { return 0 }
+
 func TestSignTransaction(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
@@ -2259,3 +2696,52 @@ func testRPCResponseWithFile(t *testing.T, testid int, result interface{}, rpc s
 	}
 	require.JSONEqf(t, string(want), string(data), "test %d: json not match, want: %s, have: %s", testid, string(want), string(data))
 }
+
+// originService exposes the RPC origin of its callers.
+type originService struct{}
+
+func (s *originService) Origin(ctx context.Context) string {
+	return rpcOrigin(ctx)
+}
+
+func TestRPCOrigin(t *testing.T) {
+	tests := []struct {
+		proxy     string // Trusted proxy configured on the node
+		forwarded string // Value of the header sent by the client
+		want      string
+	}{
+		{"", "", "127.0.0.1"},
+		{"", "10.0.0.1", "127.0.0.1"},
+		{"10.0.0.0/8", "10.0.0.1", "127.0.0.1"},
+		{"127.0.0.1/32", "", "127.0.0.1"},
+		{"127.0.0.1/32", "10.0.0.1", "10.0.0.1"},
+		{"127.0.0.1/32", "1.2.3.4, 10.0.0.1", "10.0.0.1"},
+	}
+	for i, tt := range tests {
+		server := rpc.NewServer()
+		if tt.proxy != "" {
+			server.SetOriginHeader("X-Forwarded-For", []netip.Prefix{netip.MustParsePrefix(tt.proxy)})
+		}
+		if err := server.RegisterName("test", &originService{}); err != nil {
+			t.Fatalf("failed to register service: %v", err)
+		}
+		httpsrv := httptest.NewServer(server)
+
+		client, err := rpc.Dial(httpsrv.URL)
+		if err != nil {
+			t.Fatalf("failed to dial server: %v", err)
+		}
+		if tt.forwarded != "" {
+			client.SetHeader("X-Forwarded-For", tt.forwarded)
+		}
+		var origin string
+		if err := client.Call(&origin, "test_origin"); err != nil {
+			t.Errorf("test %d: call failed: %v", i, err)
+		} else if origin != tt.want {
+			t.Errorf("test %d: origin mismatch: have %s, want %s", i, origin, tt.want)
+		}
+		client.Close()
+		httpsrv.Close()
+		server.Stop()
+	}
+}
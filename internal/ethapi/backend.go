@@ -29,6 +29,8 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -49,10 +51,11 @@ type Backend interface {
 	ChainDb() ethdb.Database
 	AccountManager() *accounts.Manager
 	ExtRPCEnabled() bool
-	RPCGasCap() uint64            // global gas cap for eth_call over rpc: DoS protection
-	RPCEVMTimeout() time.Duration // global timeout for eth_call over rpc: DoS protection
-	RPCTxFeeCap() float64         // global tx fee cap for all transaction related APIs
-	UnprotectedAllowed() bool     // allows only for EIP155 transactions.
+	RPCGasCap() uint64               // global gas cap for eth_call over rpc: DoS protection
+	RPCEVMTimeout() time.Duration    // global timeout for eth_call over rpc: DoS protection
+	RPCTxFeeCap() float64            // global tx fee cap for all transaction related APIs
+	RPCTxConditionalMaxCost() uint64 // maximum cost of transaction preconditions over rpc, zero if not accepted
+	UnprotectedAllowed() bool        // allows only for EIP155 transactions.
 
 	// Blockchain API
 	SetHead(number uint64)
@@ -76,6 +79,7 @@ type Backend interface {
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	SendBundle(ctx context.Context, bundle *bundlepool.Bundle) error
 	GetTransaction(ctx context.Context, txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error)
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
@@ -84,10 +88,13 @@ type Backend interface {
 	TxPoolContent() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction)
 	TxPoolContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction)
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
+	TxPoolStatus(hash common.Hash) txpool.TxStatus
+	TxPoolHistory(hash common.Hash) []txpool.TxEvent
+	SubscribeTxPoolEvents(ch chan<- txpool.TxEvent) event.Subscription
 
 	ChainConfig() *params.ChainConfig
 	Engine() consensus.Engine
-	HistoricalRPCService() *rpc.Client
+	HistoricalRouter() *HistoricalRouter
 	Genesis() *types.Block
 
 	// This is copied from filters.Backend
@@ -125,6 +132,9 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 		}, {
 			Namespace: "personal",
 			Service:   NewPersonalAccountAPI(apiBackend, nonceLock),
+		}, {
+			Namespace: "optimism",
+			Service:   NewOptimismAPI(apiBackend),
 		},
 	}
 }
@@ -0,0 +1,100 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// historicalCacheSize is the maximum total size of the responses of the legacy
+// endpoint kept in memory.
+const historicalCacheSize = 32 * 1024 * 1024
+
+var (
+	historicalCacheHitMeter  = metrics.NewRegisteredMeter("rpc/historical/cache/hit", nil)
+	historicalCacheMissMeter = metrics.NewRegisteredMeter("rpc/historical/cache/miss", nil)
+)
+
+// HistoricalRouter routes block-scoped requests addressing blocks before the
+// Bedrock fork to the legacy l2geth node, which holds the pre-Bedrock state and
+// traces. As pre-Bedrock blocks are final, the responses never change and are
+// cached.
+type HistoricalRouter struct {
+	client *rpc.Client
+	config *params.ChainConfig
+	cache  *lru.SizeConstrainedCache[string, []byte]
+}
+
+// NewHistoricalRouter creates a router forwarding to the given legacy endpoint.
+// The client may be nil if no legacy endpoint is configured, in which case
+// requests for pre-Bedrock blocks fail with rpc.ErrNoHistoricalFallback.
+func NewHistoricalRouter(client *rpc.Client, config *params.ChainConfig) *HistoricalRouter {
+	return &HistoricalRouter{
+		client: client,
+		config: config,
+		cache:  lru.NewSizeConstrainedCache[string, []byte](historicalCacheSize),
+	}
+}
+
+// RouteHistorical forwards the request to the legacy endpoint of the router if
+// the block with the given number predates Bedrock, and reports whether it did
+// so, along with the decoded response of the legacy endpoint.
+func RouteHistorical[T any](ctx context.Context, r *HistoricalRouter, number *big.Int, method string, args ...interface{}) (T, bool, error) {
+	var result T
+	if number == nil || !r.config.IsOptimismPreBedrock(number) {
+		return result, false, nil
+	}
+	if err := r.call(ctx, &result, method, args...); err != nil {
+		var zero T
+		return zero, true, err
+	}
+	return result, true, nil
+}
+
+// call performs a request on the legacy endpoint, serving it from the cache if
+// it was done before.
+func (r *HistoricalRouter) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if r.client == nil {
+		return rpc.ErrNoHistoricalFallback
+	}
+	key, err := json.Marshal(append([]interface{}{method}, args...))
+	if err != nil {
+		return err
+	}
+	res, ok := r.cache.Get(string(key))
+	if ok {
+		historicalCacheHitMeter.Mark(1)
+	} else {
+		historicalCacheMissMeter.Mark(1)
+
+		var raw json.RawMessage
+		if err := r.client.CallContext(ctx, &raw, method, args...); err != nil {
+			return fmt.Errorf("historical backend error: %w", err)
+		}
+		res = raw
+		r.cache.Add(string(key), res)
+	}
+	return json.Unmarshal(res, result)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// legacyService mocks the eth namespace of the legacy l2geth node.
+type legacyService struct{ calls int }
+
+func (s *legacyService) GetBalance(address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	s.calls++
+	if address == (common.Address{}) {
+		return nil, errors.New("unknown account")
+	}
+	return (*hexutil.Big)(big.NewInt(1000)), nil
+}
+
+func TestHistoricalRouter(t *testing.T) {
+	legacy := new(legacyService)
+	srv := rpc.NewServer()
+	defer srv.Stop()
+	if err := srv.RegisterName("eth", legacy); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(srv)
+	defer client.Close()
+
+	var (
+		router  = NewHistoricalRouter(client, params.OptimismTestConfig)
+		ctx     = context.Background()
+		account = common.Address{0x1}
+		number  = rpc.BlockNumberOrHashWithNumber(1)
+	)
+	// Blocks since Bedrock are served locally.
+	if _, routed, err := RouteHistorical[*hexutil.Big](ctx, router, big.NewInt(5), "eth_getBalance", account, number); routed || err != nil {
+		t.Fatalf("post-Bedrock request routed: %v", err)
+	}
+	// Pre-Bedrock requests are forwarded once, and then served from the cache.
+	for i := 0; i < 2; i++ {
+		balance, routed, err := RouteHistorical[*hexutil.Big](ctx, router, big.NewInt(1), "eth_getBalance", account, number)
+		if !routed || err != nil {
+			t.Fatalf("pre-Bedrock request not routed: %v", err)
+		}
+		if balance.ToInt().Int64() != 1000 {
+			t.Fatalf("wrong balance: have %v, want 1000", balance.ToInt())
+		}
+	}
+	if legacy.calls != 1 {
+		t.Fatalf("legacy endpoint called %d times, want 1", legacy.calls)
+	}
+	// Errors of the legacy endpoint are not cached.
+	for i := 0; i < 2; i++ {
+		if _, _, err := RouteHistorical[*hexutil.Big](ctx, router, big.NewInt(1), "eth_getBalance", common.Address{}, number); err == nil {
+			t.Fatal("expected legacy error")
+		}
+	}
+	if legacy.calls != 3 {
+		t.Fatalf("legacy endpoint called %d times, want 3", legacy.calls)
+	}
+	// Without a legacy endpoint, pre-Bedrock requests can't be served.
+	router = NewHistoricalRouter(nil, params.OptimismTestConfig)
+	if _, _, err := RouteHistorical[*hexutil.Big](ctx, router, big.NewInt(1), "eth_getBalance", account, number); !errors.Is(err, rpc.ErrNoHistoricalFallback) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
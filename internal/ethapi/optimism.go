@@ -0,0 +1,132 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// L1CostFunction names of the L1 data fee formulas.
+const (
+	L1CostFunctionBedrock = "bedrock" // Also used in the first Ecotone block
+	L1CostFunctionEcotone = "ecotone"
+	L1CostFunctionFjord   = "fjord"
+)
+
+// L1AttributesResult are the decoded L1 attributes of an L2 block, along with the
+// fee parameters in effect for that block.
+type L1AttributesResult struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+
+	L1Number       hexutil.Uint64 `json:"l1Number"`
+	L1Timestamp    hexutil.Uint64 `json:"l1Timestamp"`
+	L1BlockHash    common.Hash    `json:"l1BlockHash"`
+	L1OriginAge    hexutil.Uint64 `json:"l1OriginAge"` // Seconds between the L1 origin and the L2 block
+	SequenceNumber hexutil.Uint64 `json:"sequenceNumber"`
+	BatcherHash    common.Hash    `json:"batcherHash"`
+
+	L1BaseFee         *hexutil.Big    `json:"l1BaseFee"`
+	L1BlobBaseFee     *hexutil.Big    `json:"l1BlobBaseFee,omitempty"`     // post-ecotone
+	BaseFeeScalar     *hexutil.Uint64 `json:"baseFeeScalar,omitempty"`     // post-ecotone
+	BlobBaseFeeScalar *hexutil.Uint64 `json:"blobBaseFeeScalar,omitempty"` // post-ecotone
+	L1FeeOverhead     *hexutil.Big    `json:"l1FeeOverhead,omitempty"`     // pre-ecotone
+	L1FeeScalar       *hexutil.Big    `json:"l1FeeScalar,omitempty"`       // pre-ecotone
+	L1CostFunction    string          `json:"l1CostFunction"`
+
+	// EIP-1559 parameters committed to by the block, determining the base fee
+	// of the next block. From Holocene, these are set through the system config.
+	EIP1559Denominator hexutil.Uint64 `json:"eip1559Denominator"`
+	EIP1559Elasticity  hexutil.Uint64 `json:"eip1559Elasticity"`
+}
+
+// OptimismAPI provides an API to inspect OP-Stack specific chain data.
+type OptimismAPI struct {
+	b Backend
+}
+
+// NewOptimismAPI creates a new OP-Stack inspection API.
+func NewOptimismAPI(b Backend) *OptimismAPI {
+	return &OptimismAPI{b: b}
+}
+
+// L1Attributes decodes the L1 attributes deposit at the start of the given block,
+// and returns the L1 cost function and EIP-1559 parameters in effect for it.
+func (api *OptimismAPI) L1Attributes(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*L1AttributesResult, error) {
+	config := api.b.ChainConfig()
+	if config.Optimism == nil {
+		return nil, errors.New("not an OP-Stack chain")
+	}
+	block, err := api.b.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	if config.IsOptimismPreBedrock(block.Number()) {
+		return nil, fmt.Errorf("block %d predates Bedrock", block.NumberU64())
+	}
+	txs := block.Transactions()
+	if len(txs) == 0 || !txs[0].IsDepositTx() || txs[0].To() == nil || *txs[0].To() != types.L1BlockAddr {
+		return nil, fmt.Errorf("block %d has no L1 attributes deposit", block.NumberU64())
+	}
+	attrs, err := types.DecodeL1Attributes(txs[0].Data())
+	if err != nil {
+		return nil, err
+	}
+	result := &L1AttributesResult{
+		BlockNumber:    hexutil.Uint64(block.NumberU64()),
+		BlockHash:      block.Hash(),
+		L1Number:       hexutil.Uint64(attrs.Number),
+		L1Timestamp:    hexutil.Uint64(attrs.Time),
+		L1BlockHash:    attrs.BlockHash,
+		SequenceNumber: hexutil.Uint64(attrs.SequenceNumber),
+		BatcherHash:    attrs.BatcherHash,
+		L1BaseFee:      (*hexutil.Big)(attrs.BaseFee),
+		L1CostFunction: L1CostFunctionBedrock,
+	}
+	if block.Time() > attrs.Time {
+		result.L1OriginAge = hexutil.Uint64(block.Time() - attrs.Time)
+	}
+	if attrs.IsEcotone() {
+		baseFeeScalar, blobBaseFeeScalar := hexutil.Uint64(*attrs.BaseFeeScalar), hexutil.Uint64(*attrs.BlobBaseFeeScalar)
+		result.L1BlobBaseFee = (*hexutil.Big)(attrs.BlobBaseFee)
+		result.BaseFeeScalar = &baseFeeScalar
+		result.BlobBaseFeeScalar = &blobBaseFeeScalar
+
+		result.L1CostFunction = L1CostFunctionEcotone
+		if config.IsOptimismFjord(block.Time()) {
+			result.L1CostFunction = L1CostFunctionFjord
+		}
+	} else {
+		result.L1FeeOverhead = (*hexutil.Big)(attrs.L1FeeOverhead)
+		result.L1FeeScalar = (*hexutil.Big)(attrs.L1FeeScalar)
+	}
+	denominator, elasticity := config.BaseFeeChangeDenominator(block.Time()), config.ElasticityMultiplier()
+	if config.IsOptimismHolocene(block.Time()) {
+		denominator, elasticity = eip1559.DecodeHoloceneExtraData(block.Extra())
+	}
+	result.EIP1559Denominator = hexutil.Uint64(denominator)
+	result.EIP1559Elasticity = hexutil.Uint64(elasticity)
+	return result, nil
+}
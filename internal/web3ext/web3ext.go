@@ -31,6 +31,7 @@ var Modules = map[string]string{
 	"les":      LESJs,
 	"vflux":    VfluxJs,
 	"dev":      DevJs,
+	"optimism": OptimismJs,
 }
 
 const CliqueJs = `
@@ -260,6 +261,22 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'executionWitness',
+			call: 'debug_executionWitness',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'executionWitnessByBlock',
+			call: 'debug_executionWitnessByBlock',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'executionWitnessByPayload',
+			call: 'debug_executionWitnessByPayload',
+			params: 3
+		}),
 		new web3._extend.Method({
 			name: 'chaindbProperty',
 			call: 'debug_chaindbProperty',
@@ -545,6 +562,11 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'sendBundle',
+			call: 'eth_sendBundle',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'fillTransaction',
 			call: 'eth_fillTransaction',
@@ -616,6 +638,18 @@ web3._extend({
 			params: 4,
 			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter, null, null],
 		}),
+		new web3._extend.Method({
+			name: 'simulateV1',
+			call: 'eth_simulateV1',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'estimateL1Fee',
+			call: 'eth_estimateL1Fee',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputBlockNumberFormatter, null],
+		}),
 		new web3._extend.Method({
 			name: 'getBlockReceipts',
 			call: 'eth_getBlockReceipts',
@@ -665,6 +699,17 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.utils.fromDecimal]
 		}),
+		new web3._extend.Method({
+			name: 'setMaxDASize',
+			call: 'miner_setMaxDASize',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'getPayloadReport',
+			call: 'miner_getPayloadReport',
+			params: 1
+		}),
 	],
 	properties: []
 });
@@ -780,6 +825,11 @@ web3._extend({
 			call: 'txpool_contentFrom',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'txStatus',
+			call: 'txpool_txStatus',
+			params: 1,
+		}),
 	]
 });
 `
@@ -887,3 +937,18 @@ web3._extend({
 	],
 });
 `
+
+const OptimismJs = `
+web3._extend({
+	property: 'optimism',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'l1Attributes',
+			call: 'optimism_l1Attributes',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	],
+});
+`
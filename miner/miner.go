@@ -24,14 +24,20 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/types/interoptypes"
 	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -46,6 +52,18 @@ type BackendWithHistoricalState interface {
 	StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, readOnly bool, preferDisk bool) (*state.StateDB, tracers.StateReleaseFunc, error)
 }
 
+// BackendWithInterop is an optional extension of the Backend to check the
+// cross-chain executing messages of tx-pool transactions once interop is active.
+type BackendWithInterop interface {
+	CheckMessages(ctx context.Context, messages []interoptypes.Message, minSafety interoptypes.SafetyLevel) error
+}
+
+// BackendWithBundles is an optional extension of the Backend to include the
+// transaction bundles of a bundle pool in the built blocks.
+type BackendWithBundles interface {
+	BundlePool() *bundlepool.BundlePool
+}
+
 // Config is the configuration parameters of mining.
 type Config struct {
 	Etherbase           common.Address `toml:"-"`          // Deprecated
@@ -57,6 +75,11 @@ type Config struct {
 
 	RollupComputePendingBlock bool   // Compute the pending block from tx-pool, instead of copying the latest-block
 	EffectiveGasCeil          uint64 // if non-zero, a gas ceiling to apply independent of the header's gaslimit value
+
+	Ordering OrderingConfig // Transaction ordering policy of the block builder
+
+	MaxDATxSize    uint64 `toml:",omitempty"` // Maximum data availability size of a pool transaction, 0 for no limit
+	MaxDABlockSize uint64 `toml:",omitempty"` // Maximum total data availability size of the pool transactions of a block, 0 for no limit
 }
 
 // DefaultConfig contains default settings for miner.
@@ -69,6 +92,8 @@ var DefaultConfig = Config{
 	// for payload generation. It should be enough for Geth to
 	// run 3 rounds.
 	Recommit: 2 * time.Second,
+
+	Ordering: OrderingConfig{Policy: PriceOrderingPolicy},
 }
 
 // Miner is the main object which takes care of submitting new work to consensus
@@ -82,12 +107,20 @@ type Miner struct {
 	chain       *core.BlockChain
 	pending     *pending
 	pendingMu   sync.Mutex // Lock protects the pending block
+	ordering    OrderingPolicy
+	reports     *lru.Cache[engine.PayloadID, *PayloadReport] // Reports of the recently built payloads
 
 	backend Backend
 }
 
 // New creates a new miner with provided config.
 func New(eth Backend, config Config, engine consensus.Engine) *Miner {
+	ordering, err := config.Ordering.policy()
+	if err != nil {
+		// Configs are validated on startup, only reachable by direct construction
+		log.Error("Invalid transaction ordering, using price ordering", "err", err)
+		ordering = priceOrdering{}
+	}
 	return &Miner{
 		backend:     eth,
 		config:      &config,
@@ -96,6 +129,8 @@ func New(eth Backend, config Config, engine consensus.Engine) *Miner {
 		txpool:      eth.TxPool(),
 		chain:       eth.BlockChain(),
 		pending:     &pending{},
+		ordering:    ordering,
+		reports:     newPayloadReports(),
 	}
 }
 
@@ -150,6 +185,15 @@ func (miner *Miner) SetGasTip(tip *big.Int) error {
 	return nil
 }
 
+// SetMaxDASize sets the maximum data availability size of the pool transactions
+// included in blocks, per transaction and per block. Zero means no limit.
+func (miner *Miner) SetMaxDASize(maxTxSize, maxBlockSize uint64) {
+	miner.confMu.Lock()
+	miner.config.MaxDATxSize = maxTxSize
+	miner.config.MaxDABlockSize = maxBlockSize
+	miner.confMu.Unlock()
+}
+
 // BuildPayload builds the payload according to the provided parameters.
 func (miner *Miner) BuildPayload(args *BuildPayloadArgs) (*Payload, error) {
 	return miner.buildPayload(args)
@@ -166,21 +210,31 @@ func (miner *Miner) getPending() *newPayloadResult {
 	}
 
 	var (
-		timestamp  = uint64(time.Now().Unix())
-		withdrawal types.Withdrawals
+		timestamp     = uint64(time.Now().Unix())
+		withdrawal    types.Withdrawals
+		eip1559Params []byte
 	)
 	if miner.chainConfig.IsShanghai(new(big.Int).Add(header.Number, big.NewInt(1)), timestamp) {
 		withdrawal = []*types.Withdrawal{}
 	}
+	if miner.chainConfig.IsOptimismHolocene(timestamp) {
+		// Carry over the EIP-1559 parameters of the head block, or fall back
+		// to the chain config defaults if the head predates Holocene.
+		eip1559Params = make([]byte, eip1559.HoloceneParamsLength)
+		if eip1559.ValidateHoloceneExtraData(header.Extra) == nil {
+			eip1559Params = header.Extra[1:]
+		}
+	}
 	ret := miner.generateWork(&generateParams{
-		timestamp:   timestamp,
-		forceTime:   false,
-		parentHash:  header.Hash(),
-		coinbase:    miner.config.PendingFeeRecipient,
-		random:      common.Hash{},
-		withdrawals: withdrawal,
-		beaconRoot:  nil,
-		noTxs:       false,
+		timestamp:     timestamp,
+		forceTime:     false,
+		parentHash:    header.Hash(),
+		coinbase:      miner.config.PendingFeeRecipient,
+		random:        common.Hash{},
+		withdrawals:   withdrawal,
+		beaconRoot:    nil,
+		noTxs:         false,
+		eip1559Params: eip1559Params,
 	})
 	if ret.err != nil {
 		return nil
@@ -18,11 +18,13 @@ package miner
 
 import (
 	"container/heap"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/holiman/uint256"
 )
 
@@ -54,71 +56,190 @@ func newTxWithMinerFee(tx *txpool.LazyTransaction, from common.Address, baseFee
 	}, nil
 }
 
-// txByPriceAndTime implements both the sort and the heap interface, making it useful
-// for all at once sorting as well as individually adding and removing elements.
-type txByPriceAndTime []*txWithMinerFee
+// OrderingPolicy decides in which order the block builder includes the executable
+// transactions of the pool. Whatever the policy, the transactions of a sender are
+// included in nonce order: a policy only orders the next transactions of different
+// senders.
+type OrderingPolicy interface {
+	// Less reports whether transaction a should be included before transaction b,
+	// given their effective miner tips.
+	Less(a *txpool.LazyTransaction, aTip *uint256.Int, b *txpool.LazyTransaction, bTip *uint256.Int) bool
 
-func (s txByPriceAndTime) Len() int { return len(s) }
-func (s txByPriceAndTime) Less(i, j int) bool {
-	// If the prices are equal, use the time the transaction was first seen for
-	// deterministic sorting
-	cmp := s[i].fees.Cmp(s[j].fees)
+	// Prioritized reports whether the transactions of the given sender are included
+	// before those of all other senders, local ones included.
+	Prioritized(from common.Address) bool
+
+	// ReservedGas returns the gas of a block with the given gas limit that only the
+	// transactions of prioritized senders may use.
+	ReservedGas(gasLimit uint64) uint64
+}
+
+// Names of the built-in ordering policies.
+const (
+	PriceOrderingPolicy       = "price"
+	FIFOOrderingPolicy        = "fifo"
+	PriorityFeeOrderingPolicy = "priority-fee"
+	AllowlistOrderingPolicy   = "allowlist"
+)
+
+// OrderingConfig selects the transaction ordering policy of the block builder.
+type OrderingConfig struct {
+	Policy             string           // Ordering policy, one of price (default), fifo, priority-fee or allowlist
+	Allowlist          []common.Address // Senders prioritized by the allowlist policy
+	ReservedGasPercent uint64           // Percentage of the block gas reserved for the allowlist
+}
+
+// Validate returns an error if the config does not select a known ordering policy.
+func (config *OrderingConfig) Validate() error {
+	_, err := config.policy()
+	return err
+}
+
+// policy returns the ordering policy selected by the config.
+func (config *OrderingConfig) policy() (OrderingPolicy, error) {
+	switch config.Policy {
+	case "", PriceOrderingPolicy:
+		return priceOrdering{}, nil
+	case FIFOOrderingPolicy:
+		return fifoOrdering{}, nil
+	case PriorityFeeOrderingPolicy:
+		return priorityFeeOrdering{}, nil
+	case AllowlistOrderingPolicy:
+		reserved := config.ReservedGasPercent
+		if reserved > 100 {
+			log.Warn("Sanitizing invalid reserved gas percentage", "provided", reserved, "updated", 100)
+			reserved = 100
+		}
+		allowlist := make(map[common.Address]struct{}, len(config.Allowlist))
+		for _, addr := range config.Allowlist {
+			allowlist[addr] = struct{}{}
+		}
+		return &allowlistOrdering{allowlist: allowlist, reservedPercent: reserved}, nil
+	default:
+		return nil, fmt.Errorf("unknown transaction ordering policy %q", config.Policy)
+	}
+}
+
+// noReservation can be embedded by policies without prioritized senders.
+type noReservation struct{}
+
+func (noReservation) Prioritized(common.Address) bool { return false }
+func (noReservation) ReservedGas(uint64) uint64       { return 0 }
+
+// priceOrdering orders transactions by effective miner tip, and then by the time
+// they were first seen, to avoid network spam attacks aiming for a specific
+// ordering.
+type priceOrdering struct{ noReservation }
+
+func (priceOrdering) Less(a *txpool.LazyTransaction, aTip *uint256.Int, b *txpool.LazyTransaction, bTip *uint256.Int) bool {
+	cmp := aTip.Cmp(bTip)
+	if cmp == 0 {
+		return a.Time.Before(b.Time)
+	}
+	return cmp > 0
+}
+
+// fifoOrdering orders transactions strictly by the time they were first seen.
+type fifoOrdering struct{ noReservation }
+
+func (fifoOrdering) Less(a *txpool.LazyTransaction, aTip *uint256.Int, b *txpool.LazyTransaction, bTip *uint256.Int) bool {
+	return a.Time.Before(b.Time)
+}
+
+// priorityFeeOrdering orders transactions by the priority fee they offer, no
+// matter how much of it the base fee leaves to the miner, and then by the time
+// they were first seen.
+type priorityFeeOrdering struct{ noReservation }
+
+func (priorityFeeOrdering) Less(a *txpool.LazyTransaction, aTip *uint256.Int, b *txpool.LazyTransaction, bTip *uint256.Int) bool {
+	cmp := a.GasTipCap.Cmp(b.GasTipCap)
 	if cmp == 0 {
-		return s[i].tx.Time.Before(s[j].tx.Time)
+		return a.Time.Before(b.Time)
 	}
 	return cmp > 0
 }
-func (s txByPriceAndTime) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
-func (s *txByPriceAndTime) Push(x interface{}) {
-	*s = append(*s, x.(*txWithMinerFee))
+// allowlistOrdering includes the transactions of allowlisted senders first, and
+// keeps a share of the block gas available for them. Transactions are otherwise
+// ordered by price.
+type allowlistOrdering struct {
+	priceOrdering
+	allowlist       map[common.Address]struct{}
+	reservedPercent uint64
+}
+
+func (o *allowlistOrdering) Prioritized(from common.Address) bool {
+	_, ok := o.allowlist[from]
+	return ok
+}
+
+func (o *allowlistOrdering) ReservedGas(gasLimit uint64) uint64 {
+	return gasLimit * o.reservedPercent / 100
+}
+
+// txHeads implements both the sort and the heap interface, making it useful for
+// all at once sorting as well as individually adding and removing elements. The
+// transactions are sorted according to an ordering policy.
+type txHeads struct {
+	txs    []*txWithMinerFee
+	policy OrderingPolicy
+}
+
+func (s *txHeads) Len() int { return len(s.txs) }
+func (s *txHeads) Less(i, j int) bool {
+	return s.policy.Less(s.txs[i].tx, s.txs[i].fees, s.txs[j].tx, s.txs[j].fees)
+}
+func (s *txHeads) Swap(i, j int) { s.txs[i], s.txs[j] = s.txs[j], s.txs[i] }
+
+func (s *txHeads) Push(x interface{}) {
+	s.txs = append(s.txs, x.(*txWithMinerFee))
 }
 
-func (s *txByPriceAndTime) Pop() interface{} {
-	old := *s
+func (s *txHeads) Pop() interface{} {
+	old := s.txs
 	n := len(old)
 	x := old[n-1]
 	old[n-1] = nil
-	*s = old[0 : n-1]
+	s.txs = old[0 : n-1]
 	return x
 }
 
-// transactionsByPriceAndNonce represents a set of transactions that can return
-// transactions in a profit-maximizing sorted order, while supporting removing
+// orderedTransactions represents a set of transactions that can return
+// transactions in the order of an ordering policy, while supporting removing
 // entire batches of transactions for non-executable accounts.
-type transactionsByPriceAndNonce struct {
+type orderedTransactions struct {
 	txs     map[common.Address][]*txpool.LazyTransaction // Per account nonce-sorted list of transactions
-	heads   txByPriceAndTime                             // Next transaction for each unique account (price heap)
+	heads   *txHeads                                     // Next transaction for each unique account (policy heap)
 	signer  types.Signer                                 // Signer for the set of transactions
 	baseFee *uint256.Int                                 // Current base fee
 }
 
-// newTransactionsByPriceAndNonce creates a transaction set that can retrieve
-// price sorted transactions in a nonce-honouring way.
+// newOrderedTransactions creates a transaction set that can retrieve transactions
+// sorted by the given policy in a nonce-honouring way.
 //
 // Note, the input map is reowned so the caller should not interact any more with
 // if after providing it to the constructor.
-func newTransactionsByPriceAndNonce(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int) *transactionsByPriceAndNonce {
+func newOrderedTransactions(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, policy OrderingPolicy) *orderedTransactions {
 	// Convert the basefee from header format to uint256 format
 	var baseFeeUint *uint256.Int
 	if baseFee != nil {
 		baseFeeUint = uint256.MustFromBig(baseFee)
 	}
-	// Initialize a price and received time based heap with the head transactions
-	heads := make(txByPriceAndTime, 0, len(txs))
+	// Initialize a policy based heap with the head transactions
+	heads := &txHeads{txs: make([]*txWithMinerFee, 0, len(txs)), policy: policy}
 	for from, accTxs := range txs {
 		wrapped, err := newTxWithMinerFee(accTxs[0], from, baseFeeUint)
 		if err != nil {
 			delete(txs, from)
 			continue
 		}
-		heads = append(heads, wrapped)
+		heads.txs = append(heads.txs, wrapped)
 		txs[from] = accTxs[1:]
 	}
-	heap.Init(&heads)
+	heap.Init(heads)
 
 	// Assemble and return the transaction set
-	return &transactionsByPriceAndNonce{
+	return &orderedTransactions{
 		txs:     txs,
 		heads:   heads,
 		signer:  signer,
@@ -126,41 +247,42 @@ func newTransactionsByPriceAndNonce(signer types.Signer, txs map[common.Address]
 	}
 }
 
-// Peek returns the next transaction by price.
-func (t *transactionsByPriceAndNonce) Peek() (*txpool.LazyTransaction, *uint256.Int) {
-	if len(t.heads) == 0 {
+// Peek returns the next transaction by the ordering policy, along with its
+// effective miner tip.
+func (t *orderedTransactions) Peek() (*txpool.LazyTransaction, *uint256.Int) {
+	if t.heads.Len() == 0 {
 		return nil, nil
 	}
-	return t.heads[0].tx, t.heads[0].fees
+	return t.heads.txs[0].tx, t.heads.txs[0].fees
 }
 
 // Shift replaces the current best head with the next one from the same account.
-func (t *transactionsByPriceAndNonce) Shift() {
-	acc := t.heads[0].from
+func (t *orderedTransactions) Shift() {
+	acc := t.heads.txs[0].from
 	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
 		if wrapped, err := newTxWithMinerFee(txs[0], acc, t.baseFee); err == nil {
-			t.heads[0], t.txs[acc] = wrapped, txs[1:]
-			heap.Fix(&t.heads, 0)
+			t.heads.txs[0], t.txs[acc] = wrapped, txs[1:]
+			heap.Fix(t.heads, 0)
 			return
 		}
 	}
-	heap.Pop(&t.heads)
+	heap.Pop(t.heads)
 }
 
 // Pop removes the best transaction, *not* replacing it with the next one from
 // the same account. This should be used when a transaction cannot be executed
 // and hence all subsequent ones should be discarded from the same account.
-func (t *transactionsByPriceAndNonce) Pop() {
-	heap.Pop(&t.heads)
+func (t *orderedTransactions) Pop() {
+	heap.Pop(t.heads)
 }
 
-// Empty returns if the price heap is empty. It can be used to check it simpler
-// than calling peek and checking for nil return.
-func (t *transactionsByPriceAndNonce) Empty() bool {
-	return len(t.heads) == 0
+// Empty returns if the heap is empty. It can be used to check it simpler than
+// calling peek and checking for nil return.
+func (t *orderedTransactions) Empty() bool {
+	return t.heads.Len() == 0
 }
 
 // Clear removes the entire content of the heap.
-func (t *transactionsByPriceAndNonce) Clear() {
-	t.heads, t.txs = nil, nil
+func (t *orderedTransactions) Clear() {
+	t.heads.txs, t.txs = nil, nil
 }
@@ -24,9 +24,12 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
 )
 
@@ -102,7 +105,7 @@ func testTransactionPriceNonceSort(t *testing.T, baseFee *big.Int) {
 		expectedCount += count
 	}
 	// Sort the transactions and cross check the nonce ordering
-	txset := newTransactionsByPriceAndNonce(signer, groups, baseFee)
+	txset := newOrderedTransactions(signer, groups, baseFee, priceOrdering{})
 
 	txs := types.Transactions{}
 	for tx, _ := txset.Peek(); tx != nil; tx, _ = txset.Peek() {
@@ -168,7 +171,7 @@ func TestTransactionTimeSort(t *testing.T) {
 		})
 	}
 	// Sort the transactions and cross check the nonce ordering
-	txset := newTransactionsByPriceAndNonce(signer, groups, nil)
+	txset := newOrderedTransactions(signer, groups, nil, priceOrdering{})
 
 	txs := types.Transactions{}
 	for tx, _ := txset.Peek(); tx != nil; tx, _ = txset.Peek() {
@@ -194,3 +197,181 @@ func TestTransactionTimeSort(t *testing.T) {
 		}
 	}
 }
+
+// Tests that every ordering policy includes the transactions of a sender in nonce
+// order, and otherwise follows the order of the policy.
+func TestOrderingPolicies(t *testing.T) {
+	t.Parallel()
+
+	keys := make([]*ecdsa.PrivateKey, 10)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	policies := map[string]OrderingPolicy{
+		PriceOrderingPolicy:       priceOrdering{},
+		FIFOOrderingPolicy:        fifoOrdering{},
+		PriorityFeeOrderingPolicy: priorityFeeOrdering{},
+		AllowlistOrderingPolicy:   &allowlistOrdering{allowlist: map[common.Address]struct{}{crypto.PubkeyToAddress(keys[0].PublicKey): {}}},
+	}
+	var (
+		signer  = types.LatestSignerForChainID(common.Big1)
+		baseFee = big.NewInt(10)
+	)
+	for name, policy := range policies {
+		groups := map[common.Address][]*txpool.LazyTransaction{}
+		for _, key := range keys {
+			addr := crypto.PubkeyToAddress(key.PublicKey)
+			for i := 0; i < 10; i++ {
+				gasFeeCap := 10 + rand.Intn(50)
+				tx, _ := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+					Nonce:     uint64(i),
+					To:        &common.Address{},
+					Gas:       100,
+					GasFeeCap: big.NewInt(int64(gasFeeCap)),
+					GasTipCap: big.NewInt(int64(rand.Intn(gasFeeCap + 1))),
+				})
+				tx.SetTime(time.Unix(0, rand.Int63n(1000)))
+				groups[addr] = append(groups[addr], &txpool.LazyTransaction{
+					Hash:      tx.Hash(),
+					Tx:        tx,
+					Time:      tx.Time(),
+					GasFeeCap: uint256.MustFromBig(tx.GasFeeCap()),
+					GasTipCap: uint256.MustFromBig(tx.GasTipCap()),
+					Gas:       tx.Gas(),
+				})
+			}
+		}
+		txset := newOrderedTransactions(signer, groups, baseFee, policy)
+
+		var (
+			txs   []*txpool.LazyTransaction
+			tips  []*uint256.Int
+			nonce = make(map[common.Address]uint64)
+		)
+		for tx, tip := txset.Peek(); tx != nil; tx, tip = txset.Peek() {
+			txs, tips = append(txs, tx), append(tips, tip)
+			txset.Shift()
+		}
+		if len(txs) != len(keys)*10 {
+			t.Errorf("%s: expected %d transactions, found %d", name, len(keys)*10, len(txs))
+		}
+		for i, tx := range txs {
+			from, _ := types.Sender(signer, tx.Tx)
+			if tx.Tx.Nonce() != nonce[from] {
+				t.Errorf("%s: invalid nonce ordering: tx #%d (A=%x N=%v), want nonce %d", name, i, from[:4], tx.Tx.Nonce(), nonce[from])
+			}
+			nonce[from]++
+
+			// The next transaction of another sender was already a candidate, so
+			// it can't come first according to the policy.
+			if i+1 < len(txs) {
+				next, _ := types.Sender(signer, txs[i+1].Tx)
+				if from != next && policy.Less(txs[i+1], tips[i+1], tx, tips[i]) {
+					t.Errorf("%s: invalid ordering: tx #%d (A=%x) before tx #%d (A=%x)", name, i, from[:4], i+1, next[:4])
+				}
+			}
+		}
+	}
+}
+
+// Tests that unknown ordering policies are rejected by the config validation.
+func TestOrderingConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []string{"", PriceOrderingPolicy, FIFOOrderingPolicy, PriorityFeeOrderingPolicy, AllowlistOrderingPolicy} {
+		if err := (&OrderingConfig{Policy: policy}).Validate(); err != nil {
+			t.Errorf("policy %q: want no error, have %v", policy, err)
+		}
+	}
+	if err := (&OrderingConfig{Policy: "lottery"}).Validate(); err == nil {
+		t.Errorf("unknown policy accepted")
+	}
+}
+
+// Tests that the reserved gas is computed without losing the precision of gas
+// limits that are not multiples of 100.
+func TestAllowlistReservedGas(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		gasLimit uint64
+		reserved uint64
+		want     uint64
+	}{
+		{gasLimit: 30_000_000, reserved: 10, want: 3_000_000},
+		{gasLimit: 150, reserved: 50, want: 75},
+		{gasLimit: 99, reserved: 100, want: 99},
+		{gasLimit: 99, reserved: 0, want: 0},
+	} {
+		o := &allowlistOrdering{reservedPercent: tt.reserved}
+		if have := o.ReservedGas(tt.gasLimit); have != tt.want {
+			t.Errorf("gas limit %d, reserved %d%%: have %d, want %d", tt.gasLimit, tt.reserved, have, tt.want)
+		}
+	}
+}
+
+// Tests that the allowlist policy includes the transactions of allowlisted senders
+// first, and reserves a share of the block gas for them.
+func TestAllowlistOrdering(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		allowlist []common.Address
+		reserved  uint64
+		want      int
+	}{
+		{allowlist: []common.Address{testUserAddress}, reserved: 0, want: 1},
+		{allowlist: []common.Address{testUserAddress}, reserved: 100, want: 0},
+		{allowlist: []common.Address{testBankAddress}, reserved: 100, want: 1},
+	} {
+		backend := newTestWorkerBackend(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+		backend.txPool.Add(pendingTxs, false, true)
+
+		config := testConfig
+		config.Ordering = OrderingConfig{Policy: AllowlistOrderingPolicy, Allowlist: tt.allowlist, ReservedGasPercent: tt.reserved}
+		w := New(backend, config, ethash.NewFaker())
+
+		res := w.generateWork(&generateParams{
+			parentHash: backend.chain.CurrentBlock().Hash(),
+			timestamp:  uint64(time.Now().Unix()),
+			coinbase:   testBankAddress,
+		})
+		if res.err != nil {
+			t.Fatalf("failed to generate block: %v", res.err)
+		}
+		if have := len(res.block.Transactions()); have != tt.want {
+			t.Errorf("allowlist %v, reserved %d%%: have %d transactions, want %d", tt.allowlist, tt.reserved, have, tt.want)
+		}
+	}
+}
+
+// Tests that the pending transactions retrieved for the prioritized senders to use
+// the reserved gas left skip the ones the block includes already.
+func TestPendingPrioritized(t *testing.T) {
+	t.Parallel()
+
+	backend := newTestWorkerBackend(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+	backend.txPool.Add(append(pendingTxs, newTxs...), false, true)
+
+	config := testConfig
+	config.Ordering = OrderingConfig{Policy: AllowlistOrderingPolicy, Allowlist: []common.Address{testBankAddress}, ReservedGasPercent: 50}
+	w := New(backend, config, ethash.NewFaker())
+
+	env, err := w.prepareWork(&generateParams{
+		parentHash: backend.chain.CurrentBlock().Hash(),
+		timestamp:  uint64(time.Now().Unix()),
+		coinbase:   testBankAddress,
+	})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	env.txs = append(env.txs, pendingTxs[0])
+
+	plainTxs, blobTxs := w.pendingPrioritized(env, txpool.PendingFilter{})
+	if len(blobTxs) != 0 {
+		t.Errorf("blob transactions mismatch: have %d senders, want 0", len(blobTxs))
+	}
+	if txs := plainTxs[testBankAddress]; len(txs) != 1 || txs[0].Hash != newTxs[0].Hash() {
+		t.Fatalf("prioritized transactions mismatch: have %d, want the one not included", len(txs))
+	}
+}
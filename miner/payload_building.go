@@ -45,9 +45,10 @@ type BuildPayloadArgs struct {
 	BeaconRoot   *common.Hash          // The provided beaconRoot (Cancun)
 	Version      engine.PayloadVersion // Versioning byte for payload id calculation.
 
-	NoTxPool     bool                 // Optimism addition: option to disable tx pool contents from being included
-	Transactions []*types.Transaction // Optimism addition: txs forced into the block via engine API
-	GasLimit     *uint64              // Optimism addition: override gas limit of the block to build
+	NoTxPool      bool                 // Optimism addition: option to disable tx pool contents from being included
+	Transactions  []*types.Transaction // Optimism addition: txs forced into the block via engine API
+	GasLimit      *uint64              // Optimism addition: override gas limit of the block to build
+	EIP1559Params []byte               // Optimism addition: encoded Holocene EIP-1559 parameters
 }
 
 // Id computes an 8-byte identifier by hashing the components of the payload arguments.
@@ -73,6 +74,9 @@ func (args *BuildPayloadArgs) Id() engine.PayloadID {
 	if args.GasLimit != nil {
 		binary.Write(hasher, binary.BigEndian, *args.GasLimit)
 	}
+	if args.EIP1559Params != nil {
+		hasher.Write(args.EIP1559Params)
+	}
 
 	var out engine.PayloadID
 	copy(out[:], hasher.Sum(nil)[:8])
@@ -95,6 +99,11 @@ type Payload struct {
 	lock     sync.Mutex
 	cond     *sync.Cond
 
+	report *PayloadReport // Report of the building of the full block
+	builds int            // Number of building attempts of the payload
+
+	onUpdate func(*engine.ExecutionPayloadEnvelope) // Optional callback on full block updates
+
 	err       error
 	stopOnce  sync.Once
 	interrupt *atomic.Int32 // interrupt signal shared with worker
@@ -118,6 +127,13 @@ var errInterruptedUpdate = errors.New("interrupted payload update")
 
 // update updates the full-block with latest built version.
 func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
+	// Notify about the new full block, if any, once the payload is unlocked
+	var notify func()
+	defer func() {
+		if notify != nil {
+			notify()
+		}
+	}()
 	payload.lock.Lock()
 	defer payload.lock.Unlock()
 
@@ -129,6 +145,7 @@ func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
 
 	defer payload.cond.Broadcast() // fire signal for notifying any full block result
 
+	payload.builds++
 	if errors.Is(r.err, errInterruptedUpdate) {
 		log.Debug("Ignoring interrupted payload update", "id", payload.id)
 		return
@@ -146,6 +163,11 @@ func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
 		payload.full = r.block
 		payload.fullFees = r.fees
 		payload.sidecars = r.sidecars
+		payload.report = r.report
+		if fn := payload.onUpdate; fn != nil {
+			full, fees, sidecars := payload.full, payload.fullFees, payload.sidecars
+			notify = func() { fn(engine.BlockToExecutableData(full, fees, sidecars)) }
+		}
 
 		feesInEther := new(big.Float).Quo(new(big.Float).SetInt(r.fees), big.NewFloat(params.Ether))
 		log.Info("Updated payload",
@@ -162,6 +184,36 @@ func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
 	}
 }
 
+// SetUpdateCallback sets a callback invoked with the envelope of the full block
+// whenever it is updated, and right away if a full block was built already. The
+// callback runs outside of the payload lock, but blocks the building of the next
+// update, so it should return quickly.
+func (payload *Payload) SetUpdateCallback(fn func(*engine.ExecutionPayloadEnvelope)) {
+	payload.lock.Lock()
+	payload.onUpdate = fn
+	full, fees, sidecars := payload.full, payload.fullFees, payload.sidecars
+	payload.lock.Unlock()
+
+	if full != nil {
+		fn(engine.BlockToExecutableData(full, fees, sidecars))
+	}
+}
+
+// buildReport returns a copy of the report of the full block, with the number of
+// building attempts so far, or nil if no full block was built yet.
+func (payload *Payload) buildReport() *PayloadReport {
+	payload.lock.Lock()
+	defer payload.lock.Unlock()
+
+	if payload.report == nil {
+		return nil
+	}
+	report := payload.report.copy()
+	report.ID = payload.id
+	report.Builds = payload.builds
+	return report
+}
+
 // Resolve returns the latest built payload and also terminates the background
 // thread for updating payload. It's safe to be called multiple times.
 func (payload *Payload) Resolve() *engine.ExecutionPayloadEnvelope {
@@ -252,6 +304,23 @@ func (payload *Payload) stopBuilding() {
 	})
 }
 
+// RestorePayload recreates a payload from the envelope of its full block, built
+// before a restart of the node. The payload is not updated anymore.
+func (miner *Miner) RestorePayload(id engine.PayloadID, envelope *engine.ExecutionPayloadEnvelope) (*Payload, error) {
+	if envelope.BlobsBundle != nil && len(envelope.BlobsBundle.Blobs) > 0 {
+		return nil, errors.New("payloads with blobs can't be restored")
+	}
+	block, err := engine.ExecutableDataToBlock(*envelope.ExecutionPayload, nil, envelope.ParentBeaconBlockRoot)
+	if err != nil {
+		return nil, err
+	}
+	payload := newPayload(nil, id)
+	payload.full = block
+	payload.fullFees = envelope.BlockValue
+	payload.stopBuilding()
+	return payload, nil
+}
+
 // buildPayload builds the payload according to the provided parameters.
 func (miner *Miner) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 	if args.NoTxPool { // don't start the background payload updating job if there is no tx pool to pull from
@@ -260,16 +329,17 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 		// to deliver for not missing slot.
 		// In OP-Stack, the "empty" block is constructed from provided txs only, i.e. no tx-pool usage.
 		emptyParams := &generateParams{
-			timestamp:   args.Timestamp,
-			forceTime:   true,
-			parentHash:  args.Parent,
-			coinbase:    args.FeeRecipient,
-			random:      args.Random,
-			withdrawals: args.Withdrawals,
-			beaconRoot:  args.BeaconRoot,
-			noTxs:       true,
-			txs:         args.Transactions,
-			gasLimit:    args.GasLimit,
+			timestamp:     args.Timestamp,
+			forceTime:     true,
+			parentHash:    args.Parent,
+			coinbase:      args.FeeRecipient,
+			random:        args.Random,
+			withdrawals:   args.Withdrawals,
+			beaconRoot:    args.BeaconRoot,
+			noTxs:         true,
+			txs:           args.Transactions,
+			gasLimit:      args.GasLimit,
+			eip1559Params: args.EIP1559Params,
 		}
 		empty := miner.generateWork(emptyParams)
 		if empty.err != nil {
@@ -279,21 +349,26 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 		// make sure to make it appear as full, otherwise it will wait indefinitely for payload building to complete.
 		payload.full = empty.block
 		payload.fullFees = empty.fees
+		payload.report = empty.report
+		payload.builds = 1
 		payload.cond.Broadcast() // unblocks Resolve
+
+		miner.reports.Add(payload.id, payload.buildReport())
 		return payload, nil
 	}
 
 	fullParams := &generateParams{
-		timestamp:   args.Timestamp,
-		forceTime:   true,
-		parentHash:  args.Parent,
-		coinbase:    args.FeeRecipient,
-		random:      args.Random,
-		withdrawals: args.Withdrawals,
-		beaconRoot:  args.BeaconRoot,
-		noTxs:       false,
-		txs:         args.Transactions,
-		gasLimit:    args.GasLimit,
+		timestamp:     args.Timestamp,
+		forceTime:     true,
+		parentHash:    args.Parent,
+		coinbase:      args.FeeRecipient,
+		random:        args.Random,
+		withdrawals:   args.Withdrawals,
+		beaconRoot:    args.BeaconRoot,
+		noTxs:         false,
+		txs:           args.Transactions,
+		gasLimit:      args.GasLimit,
+		eip1559Params: args.EIP1559Params,
 	}
 
 	// Since we skip building the empty block when using the tx pool, we need to explicitly
@@ -338,6 +413,9 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 			dur := time.Since(start)
 			// update handles error case
 			payload.update(r, dur)
+			if report := payload.buildReport(); report != nil {
+				miner.reports.Add(payload.id, report)
+			}
 			if r.err == nil {
 				// after first successful pass, we're updating
 				fullParams.isUpdate = true
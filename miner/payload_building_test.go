@@ -17,24 +17,34 @@
 package miner
 
 import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
 	"math/big"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/types/interoptypes"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/interop"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
 )
@@ -233,6 +243,282 @@ func testBuildPayload(t *testing.T, noTxPool, interrupt bool) {
 	}
 }
 
+func TestBuildPayloadHolocene(t *testing.T) {
+	t.Parallel()
+	var (
+		config       = *params.TestChainConfig
+		zero         = uint64(0)
+		denomCanyon  = uint64(250)
+		gasLimit     = uint64(30_000_000)
+		db           = rawdb.NewMemoryDatabase()
+		customParams = eip1559.EncodeHolocene1559Params(100, 4)
+	)
+	config.CanyonTime, config.HoloceneTime = &zero, &zero
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50, EIP1559DenominatorCanyon: &denomCanyon}
+	w, b := newTestWorker(t, &config, ethash.NewFaker(), db, 0)
+
+	build := func(eip1559Params []byte) (*engine.ExecutionPayloadEnvelope, error) {
+		payload, err := w.buildPayload(&BuildPayloadArgs{
+			Parent:        b.chain.CurrentBlock().Hash(),
+			Timestamp:     uint64(time.Now().Unix()),
+			NoTxPool:      true,
+			GasLimit:      &gasLimit,
+			EIP1559Params: eip1559Params,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return payload.ResolveFull(), nil
+	}
+	if _, err := build(nil); err == nil {
+		t.Fatal("expected error for missing eip1559 params")
+	}
+	if _, err := build(eip1559.EncodeHolocene1559Params(0, 4)); err == nil {
+		t.Fatal("expected error for invalid eip1559 params")
+	}
+	// Zero parameters should fall back to the chain config defaults
+	env, err := build(eip1559.EncodeHolocene1559Params(0, 0))
+	if err != nil {
+		t.Fatalf("failed to build payload: %v", err)
+	}
+	if want := eip1559.EncodeHoloceneExtraData(250, 6); !bytes.Equal(env.ExecutionPayload.ExtraData, want) {
+		t.Fatalf("extra-data mismatch: have %x, want %x", env.ExecutionPayload.ExtraData, want)
+	}
+	env, err = build(customParams)
+	if err != nil {
+		t.Fatalf("failed to build payload: %v", err)
+	}
+	if want := append([]byte{0}, customParams...); !bytes.Equal(env.ExecutionPayload.ExtraData, want) {
+		t.Fatalf("extra-data mismatch: have %x, want %x", env.ExecutionPayload.ExtraData, want)
+	}
+}
+
+// testInteropBackend extends the test backend with an interop supervisor.
+type testInteropBackend struct {
+	*testWorkerBackend
+	*interop.LocalSupervisor
+}
+
+// newTestInteropBackend creates a backend on top of an interop chain, with a stub
+// of the CrossL2Inbox emitting an executing message for the payload hash given
+// as calldata.
+func newTestInteropBackend(t *testing.T, supervisor *interop.LocalSupervisor) *testInteropBackend {
+	var (
+		config      = *params.TestChainConfig
+		zero        = uint64(0)
+		denomCanyon = uint64(250)
+	)
+	config.CanyonTime, config.InteropTime = &zero, &zero
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50, EIP1559DenominatorCanyon: &denomCanyon}
+
+	// The inbox stub emits an ExecutingMessage event with an empty identifier and
+	// the payload hash taken from the first calldata word.
+	inbox := []byte{byte(vm.PUSH1), 0x00, byte(vm.CALLDATALOAD), byte(vm.PUSH32)}
+	inbox = append(inbox, interoptypes.ExecutingMessageEventTopic[:]...)
+	inbox = append(inbox, byte(vm.PUSH1), 0xa0, byte(vm.PUSH1), 0x00, byte(vm.LOG2), byte(vm.STOP))
+
+	gspec := &core.Genesis{
+		Config: &config,
+		Alloc: types.GenesisAlloc{
+			testBankAddress:                   {Balance: testBankFunds},
+			params.InteropCrossL2InboxAddress: {Code: inbox},
+		},
+	}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), &core.CacheConfig{TrieDirtyDisabled: true}, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("core.NewBlockChain failed: %v", err)
+	}
+	pool := legacypool.New(testTxPoolConfig, chain)
+	txpool, _ := txpool.New(testTxPoolConfig.PriceLimit, chain, []txpool.SubPool{pool})
+	return &testInteropBackend{
+		testWorkerBackend: &testWorkerBackend{chain: chain, txPool: txpool, genesis: gspec},
+		LocalSupervisor:   supervisor,
+	}
+}
+
+func TestBuildPayloadInterop(t *testing.T) {
+	t.Parallel()
+	var (
+		supervisor  = interop.NewLocalSupervisor()
+		backend     = newTestInteropBackend(t, supervisor)
+		chain       = backend.chain
+		txpool      = backend.txPool
+		signer      = types.LatestSigner(chain.Config())
+		validHash   = common.Hash{0x01}
+		invalidHash = common.Hash{0x02}
+	)
+	supervisor.SetSafety(interoptypes.Message{PayloadHash: validHash}, interoptypes.CrossUnsafe)
+
+	var txs []*types.Transaction
+	for i, hash := range []common.Hash{validHash, invalidHash} {
+		txs = append(txs, types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &params.InteropCrossL2InboxAddress,
+			Gas:      100_000,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+			Data:     hash[:],
+		}))
+	}
+	for _, err := range txpool.Add(txs, true, true) {
+		if err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	w := New(backend, testConfig, ethash.NewFaker())
+	payload, err := w.buildPayload(&BuildPayloadArgs{
+		Parent:    chain.CurrentBlock().Hash(),
+		Timestamp: uint64(time.Now().Unix()),
+	})
+	if err != nil {
+		t.Fatalf("failed to build payload: %v", err)
+	}
+	payload.WaitFull()
+	env := payload.ResolveFull()
+	if len(env.ExecutionPayload.Transactions) != 1 {
+		t.Fatalf("transaction count mismatch: have %d, want 1", len(env.ExecutionPayload.Transactions))
+	}
+	var included types.Transaction
+	if err := included.UnmarshalBinary(env.ExecutionPayload.Transactions[0]); err != nil {
+		t.Fatal(err)
+	}
+	if included.Hash() != txs[0].Hash() {
+		t.Fatalf("unexpected transaction included: have %x, want %x", included.Hash(), txs[0].Hash())
+	}
+}
+
+// interopTx creates a call to the CrossL2Inbox stub emitting an executing message
+// for the given payload hash, declaring the inbox in its access list or not.
+func interopTx(signer types.Signer, nonce uint64, hash common.Hash, declared bool) *types.Transaction {
+	var accesses types.AccessList
+	if declared {
+		accesses = types.AccessList{{Address: params.InteropCrossL2InboxAddress}}
+	}
+	return types.MustSignNewTx(testBankKey, signer, &types.AccessListTx{
+		ChainID:    signer.ChainID(),
+		Nonce:      nonce,
+		To:         &params.InteropCrossL2InboxAddress,
+		Gas:        100_000,
+		GasPrice:   big.NewInt(params.InitialBaseFee),
+		Data:       hash[:],
+		AccessList: accesses,
+	})
+}
+
+// interopSimLogs returns the log emitted by the CrossL2Inbox stub for a call.
+func interopSimLogs(tx *types.Transaction) ([]*types.Log, error) {
+	return []*types.Log{{
+		Address: params.InteropCrossL2InboxAddress,
+		Topics:  []common.Hash{interoptypes.ExecutingMessageEventTopic, common.BytesToHash(tx.Data())},
+		Data:    make([]byte, 0xa0),
+	}}, nil
+}
+
+// countingSupervisor counts the message checks of a supervisor.
+type countingSupervisor struct {
+	*interop.LocalSupervisor
+	calls atomic.Int32
+}
+
+func (s *countingSupervisor) CheckMessages(ctx context.Context, messages []interoptypes.Message, minSafety interoptypes.SafetyLevel) error {
+	s.calls.Add(1)
+	return s.LocalSupervisor.CheckMessages(ctx, messages, minSafety)
+}
+
+// Tests that the pool only checks the executing messages of the transactions
+// declaring the CrossL2Inbox, leaving the undeclared ones to block building.
+func TestInteropUndeclaredInbox(t *testing.T) {
+	t.Parallel()
+	var (
+		supervisor  = &countingSupervisor{LocalSupervisor: interop.NewLocalSupervisor()}
+		backend     = newTestInteropBackend(t, supervisor.LocalSupervisor)
+		chain       = backend.chain
+		signer      = types.LatestSigner(chain.Config())
+		validHash   = common.Hash{0x01}
+		invalidHash = common.Hash{0x02}
+	)
+	supervisor.SetSafety(interoptypes.Message{PayloadHash: validHash}, interoptypes.CrossUnsafe)
+	backend.txPool.SetInteropValidation(&txpool.InteropValidationOptions{
+		Config:     chain.Config(),
+		SimLogs:    interopSimLogs,
+		Supervisor: supervisor,
+		MinSafety:  interoptypes.CrossUnsafe,
+	})
+	txs := []*types.Transaction{
+		interopTx(signer, 0, validHash, true),
+		interopTx(signer, 1, invalidHash, false),
+		interopTx(signer, 2, invalidHash, true),
+	}
+	errs := backend.txPool.Add(txs, false, true)
+	if errs[0] != nil || errs[1] != nil {
+		t.Fatalf("failed to add transactions: %v", errs)
+	}
+	if !errors.Is(errs[2], txpool.ErrInvalidExecutingMessage) {
+		t.Fatalf("declared invalid message not rejected: %v", errs[2])
+	}
+	if calls := supervisor.calls.Load(); calls != 2 {
+		t.Fatalf("wrong number of supervisor checks: have %d, want 2", calls)
+	}
+	// The undeclared invalid message is only caught by the block builder.
+	w := New(backend, testConfig, ethash.NewFaker())
+	payload, err := w.buildPayload(&BuildPayloadArgs{
+		Parent:    chain.CurrentBlock().Hash(),
+		Timestamp: uint64(time.Now().Unix()),
+	})
+	if err != nil {
+		t.Fatalf("failed to build payload: %v", err)
+	}
+	payload.WaitFull()
+	env := payload.ResolveFull()
+	if len(env.ExecutionPayload.Transactions) != 1 {
+		t.Fatalf("transaction count mismatch: have %d, want 1", len(env.ExecutionPayload.Transactions))
+	}
+	var included types.Transaction
+	if err := included.UnmarshalBinary(env.ExecutionPayload.Transactions[0]); err != nil {
+		t.Fatal(err)
+	}
+	if included.Hash() != txs[0].Hash() {
+		t.Fatalf("unexpected transaction included: have %x, want %x", included.Hash(), txs[0].Hash())
+	}
+}
+
+// stalledSupervisor never answers the message checks before they time out.
+type stalledSupervisor struct{}
+
+func (stalledSupervisor) CheckMessages(ctx context.Context, messages []interoptypes.Message, minSafety interoptypes.SafetyLevel) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Tests that a stalled supervisor doesn't hold up the admission of a batch of
+// transactions for longer than the time budget of the checks.
+func TestInteropStalledSupervisor(t *testing.T) {
+	t.Parallel()
+	var (
+		backend = newTestInteropBackend(t, interop.NewLocalSupervisor())
+		chain   = backend.chain
+		signer  = types.LatestSigner(chain.Config())
+	)
+	backend.txPool.SetInteropValidation(&txpool.InteropValidationOptions{
+		Config:     chain.Config(),
+		SimLogs:    interopSimLogs,
+		Supervisor: stalledSupervisor{},
+		MinSafety:  interoptypes.CrossUnsafe,
+	})
+	var txs []*types.Transaction
+	for i := uint64(0); i < 64; i++ {
+		txs = append(txs, interopTx(signer, i, common.Hash{0x01}, true))
+	}
+	start := time.Now()
+	for i, err := range backend.txPool.Add(txs, false, true) {
+		if !errors.Is(err, txpool.ErrInvalidExecutingMessage) {
+			t.Fatalf("transaction %d: unchecked message not rejected: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("admission held up by the supervisor for %v", elapsed)
+	}
+}
+
 func genTxs(startNonce, count uint64) types.Transactions {
 	txs := make(types.Transactions, 0, count)
 	signer := types.LatestSigner(params.TestChainConfig)
@@ -317,6 +603,14 @@ func TestPayloadId(t *testing.T) {
 				},
 			},
 		},
+		// Different EIP-1559 params (Holocene)
+		{
+			Parent:        common.Hash{2},
+			Timestamp:     2,
+			Random:        common.Hash{0x2},
+			FeeRecipient:  common.Address{0x2},
+			EIP1559Params: eip1559.EncodeHolocene1559Params(250, 6),
+		},
 	} {
 		id := tt.Id().String()
 		if prev, exists := ids[id]; exists {
@@ -325,3 +619,167 @@ func TestPayloadId(t *testing.T) {
 		ids[id] = i
 	}
 }
+
+// Tests that pool transactions exceeding the data availability limits are
+// skipped during block building, but kept in the pool.
+func TestBuildDASizeLimits(t *testing.T) {
+	t.Parallel()
+
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+	daSize := pendingTxs[0].RollupCostData().FastLzSize
+
+	for _, tt := range []struct {
+		maxTxSize, maxBlockSize uint64
+		want                    int
+	}{
+		{maxTxSize: daSize - 1, want: 0},
+		{maxBlockSize: daSize - 1, want: 0},
+		{maxTxSize: daSize, maxBlockSize: daSize, want: 1},
+		{want: 1},
+	} {
+		w.SetMaxDASize(tt.maxTxSize, tt.maxBlockSize)
+		res := w.generateWork(&generateParams{
+			parentHash: b.chain.CurrentBlock().Hash(),
+			timestamp:  uint64(time.Now().Unix()),
+			coinbase:   testBankAddress,
+		})
+		if res.err != nil {
+			t.Fatalf("failed to generate block: %v", res.err)
+		}
+		if have := len(res.block.Transactions()); have != tt.want {
+			t.Errorf("limits %d/%d: have %d transactions, want %d", tt.maxTxSize, tt.maxBlockSize, have, tt.want)
+		}
+		if !b.txPool.Has(pendingTxs[0].Hash()) {
+			t.Fatalf("limits %d/%d: transaction evicted from the pool", tt.maxTxSize, tt.maxBlockSize)
+		}
+	}
+}
+
+// testBundleBackend extends the test backend with a bundle pool.
+type testBundleBackend struct {
+	*testWorkerBackend
+	bundlePool *bundlepool.BundlePool
+}
+
+func (b *testBundleBackend) BundlePool() *bundlepool.BundlePool { return b.bundlePool }
+
+func TestBuildBundles(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend = newTestWorkerBackend(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+		pool    = bundlepool.New(bundlepool.DefaultConfig, backend.chain)
+		signer  = types.LatestSigner(params.TestChainConfig)
+	)
+	if err := pool.Init(testTxPoolConfig.PriceLimit, backend.chain.CurrentBlock(), nil); err != nil {
+		t.Fatalf("failed to init bundle pool: %v", err)
+	}
+	transfer := func(key *ecdsa.PrivateKey, nonce uint64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &testUserAddress,
+			Value:    big.NewInt(1000),
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+	}
+	// Contract creation running PUSH1 0, PUSH1 0, REVERT.
+	revert := types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+		Nonce:    1,
+		Value:    big.NewInt(0),
+		Gas:      100000,
+		GasPrice: big.NewInt(params.InitialBaseFee),
+		Data:     common.FromHex("0x60006000fd"),
+	})
+	bundles := []*bundlepool.Bundle{
+		// The second transaction has a nonce gap, the first transfer is rolled back.
+		{Txs: types.Transactions{transfer(testBankKey, 0), transfer(testBankKey, 5)}},
+		{Txs: types.Transactions{transfer(testBankKey, 0)}},
+		// Reverting transactions fail the bundle, unless allowed to revert.
+		{Txs: types.Transactions{revert}},
+		{Txs: types.Transactions{revert, transfer(testBankKey, 2)}, RevertingTxHashes: []common.Hash{revert.Hash()}},
+		// Bundles targeting later blocks are not included.
+		{Txs: types.Transactions{transfer(testBankKey, 3)}, MinBlock: 2},
+	}
+	for i, bundle := range bundles {
+		if err := pool.AddBundle(bundle); err != nil {
+			t.Fatalf("failed to add bundle %d: %v", i, err)
+		}
+	}
+	w := New(&testBundleBackend{backend, pool}, testConfig, ethash.NewFaker())
+	res := w.generateWork(&generateParams{
+		parentHash: backend.chain.CurrentBlock().Hash(),
+		timestamp:  uint64(time.Now().Unix()),
+		coinbase:   testBankAddress,
+	})
+	if res.err != nil {
+		t.Fatalf("failed to generate block: %v", res.err)
+	}
+	want := []common.Hash{bundles[1].Txs[0].Hash(), revert.Hash(), bundles[3].Txs[1].Hash()}
+	txs := res.block.Transactions()
+	if len(txs) != len(want) {
+		t.Fatalf("wrong number of transactions: have %d, want %d", len(txs), len(want))
+	}
+	for i, tx := range txs {
+		if tx.Hash() != want[i] {
+			t.Errorf("transaction %d: have %x, want %x", i, tx.Hash(), want[i])
+		}
+	}
+	if len(res.receipts) != len(want) || res.receipts[1].Status != types.ReceiptStatusFailed {
+		t.Errorf("allowed revert not included as failed")
+	}
+	var gasUsed uint64
+	for _, receipt := range res.receipts {
+		gasUsed += receipt.GasUsed
+	}
+	if res.block.GasUsed() != gasUsed {
+		t.Errorf("block gas used %d, receipts %d", res.block.GasUsed(), gasUsed)
+	}
+}
+
+func TestPayloadReport(t *testing.T) {
+	t.Parallel()
+
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+
+	// Force a transaction reusing the nonce of the pool transaction.
+	forced := types.MustSignNewTx(testBankKey, types.LatestSigner(params.TestChainConfig), &types.LegacyTx{
+		Nonce:    0,
+		To:       &testBankAddress,
+		Value:    big.NewInt(1),
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(params.InitialBaseFee),
+	})
+	args := &BuildPayloadArgs{
+		Parent:       b.chain.CurrentBlock().Hash(),
+		Timestamp:    uint64(time.Now().Unix()),
+		FeeRecipient: testBankAddress,
+		Transactions: []*types.Transaction{forced},
+	}
+	if w.GetPayloadReport(args.Id()) != nil {
+		t.Fatal("report of unknown payload")
+	}
+	payload, err := w.buildPayload(args)
+	if err != nil {
+		t.Fatalf("failed to build payload: %v", err)
+	}
+	payload.WaitFull()
+	full := payload.ResolveFull()
+
+	report := w.GetPayloadReport(args.Id())
+	if report == nil {
+		t.Fatal("missing payload report")
+	}
+	if report.ID != args.Id() || report.BlockHash != full.ExecutionPayload.BlockHash || report.Builds < 1 {
+		t.Errorf("wrong report header: id %v, hash %x, builds %d", report.ID, report.BlockHash, report.Builds)
+	}
+	if len(report.Included) != 1 || report.Included[0].Hash != forced.Hash() || report.Included[0].Source != SourceForced {
+		t.Errorf("wrong included transactions: %+v", report.Included)
+	}
+	if report.Included[0].GasUsed != hexutil.Uint64(params.TxGas) {
+		t.Errorf("wrong gas used: have %d, want %d", report.Included[0].GasUsed, params.TxGas)
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0].Hash != pendingTxs[0].Hash() || report.Skipped[0].Reason != SkipNonceTooLow {
+		t.Errorf("wrong skipped transactions: %+v", report.Skipped)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// maxPayloadReports is the number of reports of recently built payloads kept
+// for retrieval through the API.
+const maxPayloadReports = 64
+
+// SkipReason explains why the block builder left a transaction out of a block.
+type SkipReason string
+
+const (
+	SkipGasLimit          SkipReason = "gas-limit"          // Not enough gas left in the block
+	SkipBlobGasLimit      SkipReason = "blob-gas-limit"     // Not enough blob gas left in the block
+	SkipDASizeLimit       SkipReason = "da-size-limit"      // Data availability size limit exceeded
+	SkipNonceTooLow       SkipReason = "nonce-too-low"      // Nonce already used, e.g. by a forced transaction
+	SkipInsufficientFunds SkipReason = "insufficient-funds" // Balance doesn't cover gas, value and L1 data fee
+	SkipReplayProtected   SkipReason = "replay-protected"   // Replay protected before EIP-155 activation
+	SkipConditional       SkipReason = "conditional-failed" // Transaction conditional doesn't hold
+	SkipInterop           SkipReason = "interop-invalid"    // Invalid cross-chain executing message
+	SkipEvicted           SkipReason = "evicted"            // Evicted from the pool during building
+	SkipBundleFailed      SkipReason = "bundle-failed"      // Part of a bundle which couldn't be included
+	SkipInvalid           SkipReason = "invalid"            // Any other execution failure
+)
+
+// Transaction sources of a block.
+const (
+	SourceForced = "forced" // Forced by the rollup node through the engine API
+	SourceBundle = "bundle" // Part of a bundle of the bundle pool
+	SourcePool   = "pool"   // Picked from the transaction pool
+)
+
+// IncludedTx is a transaction included in a built payload.
+type IncludedTx struct {
+	Hash    common.Hash    `json:"hash"`
+	Source  string         `json:"source"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Failed  bool           `json:"failed,omitempty"`
+}
+
+// SkippedTx is a transaction the block builder considered but left out of a
+// built payload. When a pool transaction is skipped, the later transactions of
+// the same sender aren't considered anymore, as they are not executable.
+type SkippedTx struct {
+	Hash   common.Hash `json:"hash"`
+	Reason SkipReason  `json:"reason"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// PayloadReport describes how the block builder built a payload: the included
+// transactions, the skipped ones with the reason, and the time spent in each
+// building phase. Once a payload was built several times, the report describes
+// the build that was retained.
+type PayloadReport struct {
+	ID         engine.PayloadID `json:"id"`
+	ParentHash common.Hash      `json:"parentHash"`
+	Number     hexutil.Uint64   `json:"number"`
+	BlockHash  common.Hash      `json:"blockHash"`
+	Builds     int              `json:"builds"` // Number of times the payload was built
+
+	Included   []IncludedTx `json:"included"`
+	Skipped    []SkippedTx  `json:"skipped"`
+	StopReason string       `json:"stopReason,omitempty"` // Why the builder stopped before running out of transactions
+
+	PrepareTime   time.Duration `json:"prepareTime"`   // Header and parent state preparation, in nanoseconds
+	ForcedTxsTime time.Duration `json:"forcedTxsTime"` // Execution of the forced transactions, in nanoseconds
+	FillTime      time.Duration `json:"fillTime"`      // Selection and execution of bundles and pool transactions, in nanoseconds
+	StateRootTime time.Duration `json:"stateRootTime"` // Block finalisation, dominated by the state root computation, in nanoseconds
+}
+
+var (
+	buildPrepareTimer   = metrics.NewRegisteredTimer("miner/build/prepare", nil)
+	buildForcedTxsTimer = metrics.NewRegisteredTimer("miner/build/forcedtxs", nil)
+	buildFillTimer      = metrics.NewRegisteredTimer("miner/build/fill", nil)
+	buildStateRootTimer = metrics.NewRegisteredTimer("miner/build/stateroot", nil)
+	buildIncludedMeter  = metrics.NewRegisteredMeter("miner/build/included", nil)
+	buildSkippedMeter   = metrics.NewRegisteredMeter("miner/build/skipped", nil)
+)
+
+// include records a transaction included in the block.
+func (r *PayloadReport) include(tx *types.Transaction, receipt *types.Receipt, source string) {
+	r.Included = append(r.Included, IncludedTx{
+		Hash:    tx.Hash(),
+		Source:  source,
+		GasUsed: hexutil.Uint64(receipt.GasUsed),
+		Failed:  receipt.Status == types.ReceiptStatusFailed,
+	})
+}
+
+// skip records a transaction left out of the block.
+func (r *PayloadReport) skip(hash common.Hash, reason SkipReason, err error) {
+	skipped := SkippedTx{Hash: hash, Reason: reason}
+	if err != nil {
+		skipped.Error = err.Error()
+	}
+	r.Skipped = append(r.Skipped, skipped)
+}
+
+// export updates the block building metrics with the report of a build.
+func (r *PayloadReport) export() {
+	buildPrepareTimer.Update(r.PrepareTime)
+	buildForcedTxsTimer.Update(r.ForcedTxsTime)
+	buildFillTimer.Update(r.FillTime)
+	buildStateRootTimer.Update(r.StateRootTime)
+	buildIncludedMeter.Mark(int64(len(r.Included)))
+	buildSkippedMeter.Mark(int64(len(r.Skipped)))
+
+	for _, skipped := range r.Skipped {
+		metrics.GetOrRegisterMeter("miner/build/skipped/"+string(skipped.Reason), nil).Mark(1)
+	}
+}
+
+// copy returns a deep copy of the report.
+func (r *PayloadReport) copy() *PayloadReport {
+	cpy := *r
+	cpy.Included = slices.Clone(r.Included)
+	cpy.Skipped = slices.Clone(r.Skipped)
+	return &cpy
+}
+
+// skipReason classifies the error of a transaction which failed to apply.
+func skipReason(err error) SkipReason {
+	switch {
+	case errors.Is(err, core.ErrNonceTooLow):
+		return SkipNonceTooLow
+	case errors.Is(err, core.ErrInsufficientFunds), errors.Is(err, core.ErrInsufficientFundsForTransfer):
+		return SkipInsufficientFunds
+	case errors.Is(err, core.ErrGasLimitReached):
+		return SkipGasLimit
+	case errors.Is(err, errInvalidInteropMessage):
+		return SkipInterop
+	default:
+		return SkipInvalid
+	}
+}
+
+// newPayloadReports creates the cache of the reports of recently built payloads.
+func newPayloadReports() *lru.Cache[engine.PayloadID, *PayloadReport] {
+	return lru.NewCache[engine.PayloadID, *PayloadReport](maxPayloadReports)
+}
+
+// GetPayloadReport returns the report of a recently built payload, or nil if
+// the payload is unknown.
+func (miner *Miner) GetPayloadReport(id engine.PayloadID) *PayloadReport {
+	report, ok := miner.reports.Get(id)
+	if !ok {
+		return nil
+	}
+	return report.copy()
+}
@@ -25,13 +25,16 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/types/interoptypes"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/log"
@@ -50,6 +53,7 @@ var (
 	errBlockInterruptedByRecommit = errors.New("recommit interrupt while building block")
 	errBlockInterruptedByTimeout  = errors.New("timeout while building block")
 	errBlockInterruptedByResolve  = errors.New("payload resolution while building block")
+	errInvalidInteropMessage      = errors.New("invalid interop executing message")
 )
 
 // environment is the worker's current environment and holds all
@@ -66,6 +70,10 @@ type environment struct {
 	receipts []*types.Receipt
 	sidecars []*types.BlobTxSidecar
 	blobs    int
+	daSize   uint64         // Data availability size of the included pool transactions, tracked if limited
+	report   *PayloadReport // Included and skipped transactions, with building times
+
+	interopChecks bool // Whether the executing messages of applied transactions are checked (Optimism interop)
 }
 
 const (
@@ -84,6 +92,7 @@ type newPayloadResult struct {
 	sidecars []*types.BlobTxSidecar // collected blobs of blob transactions
 	stateDB  *state.StateDB         // StateDB after executing the transactions
 	receipts []*types.Receipt       // Receipts collected during construction
+	report   *PayloadReport         // Report of the building of the block
 }
 
 // generateParams wraps various settings for generating sealing task.
@@ -97,18 +106,25 @@ type generateParams struct {
 	beaconRoot  *common.Hash      // The beacon root (cancun field).
 	noTxs       bool              // Flag whether an empty block without any transaction is expected
 
-	txs       types.Transactions // Deposit transactions to include at the start of the block
-	gasLimit  *uint64            // Optional gas limit override
-	interrupt *atomic.Int32      // Optional interruption signal to pass down to worker.generateWork
-	isUpdate  bool               // Optional flag indicating that this is building a discardable update
+	txs           types.Transactions // Deposit transactions to include at the start of the block
+	gasLimit      *uint64            // Optional gas limit override
+	eip1559Params []byte             // Optional EIP-1559 parameters (Holocene)
+	interrupt     *atomic.Int32      // Optional interruption signal to pass down to worker.generateWork
+	isUpdate      bool               // Optional flag indicating that this is building a discardable update
 }
 
 // generateWork generates a sealing block based on the given parameters.
 func (miner *Miner) generateWork(params *generateParams) *newPayloadResult {
+	start := time.Now()
 	work, err := miner.prepareWork(params)
 	if err != nil {
 		return &newPayloadResult{err: err}
 	}
+	work.report = &PayloadReport{
+		ParentHash:  work.header.ParentHash,
+		Number:      hexutil.Uint64(work.header.Number.Uint64()),
+		PrepareTime: time.Since(start),
+	}
 	if work.gasPool == nil {
 		gasLimit := miner.config.EffectiveGasCeil
 		if gasLimit == 0 || gasLimit > work.header.GasLimit {
@@ -119,6 +135,7 @@ func (miner *Miner) generateWork(params *generateParams) *newPayloadResult {
 
 	misc.EnsureCreate2Deployer(miner.chainConfig, work.header.Time, work.state)
 
+	start = time.Now()
 	for _, tx := range params.txs {
 		from, _ := types.Sender(work.signer, tx)
 		work.state.SetTxContext(tx.Hash(), work.tcount)
@@ -126,9 +143,16 @@ func (miner *Miner) generateWork(params *generateParams) *newPayloadResult {
 		if err != nil {
 			return &newPayloadResult{err: fmt.Errorf("failed to force-include tx: %s type: %d sender: %s nonce: %d, err: %w", tx.Hash(), tx.Type(), from, tx.Nonce(), err)}
 		}
+		work.report.include(tx, work.receipts[len(work.receipts)-1], SourceForced)
 		work.tcount++
 	}
+	work.report.ForcedTxsTime = time.Since(start)
+
 	if !params.noTxs {
+		// Only the transactions taken from the tx-pool are subject to the interop
+		// checks, the forced transactions were already derived by the rollup node.
+		work.interopChecks = miner.chainConfig.IsOptimismInterop(work.header.Time)
+
 		// use shared interrupt if present
 		interrupt := params.interrupt
 		if interrupt == nil {
@@ -138,7 +162,9 @@ func (miner *Miner) generateWork(params *generateParams) *newPayloadResult {
 			interrupt.Store(commitInterruptTimeout)
 		})
 
+		start := time.Now()
 		err := miner.fillTransactions(interrupt, work)
+		work.report.FillTime = time.Since(start)
 		timer.Stop() // don't need timeout interruption any more
 		if errors.Is(err, errBlockInterruptedByTimeout) {
 			log.Warn("Block building is interrupted", "allowance", common.PrettyDuration(miner.config.Recommit))
@@ -150,17 +176,23 @@ func (miner *Miner) generateWork(params *generateParams) *newPayloadResult {
 		return &newPayloadResult{err: errInterruptedUpdate}
 	}
 
+	start = time.Now()
 	body := types.Body{Transactions: work.txs, Withdrawals: params.withdrawals}
 	block, err := miner.engine.FinalizeAndAssemble(miner.chain, work.header, work.state, &body, work.receipts)
 	if err != nil {
 		return &newPayloadResult{err: err}
 	}
+	work.report.StateRootTime = time.Since(start)
+	work.report.BlockHash = block.Hash()
+	work.report.export()
+
 	return &newPayloadResult{
 		block:    block,
 		fees:     totalFees(block, work.receipts),
 		sidecars: work.sidecars,
 		stateDB:  work.state,
 		receipts: work.receipts,
+		report:   work.report,
 	}
 }
 
@@ -201,6 +233,22 @@ func (miner *Miner) prepareWork(genParams *generateParams) (*environment, error)
 	if len(miner.config.ExtraData) != 0 && miner.chainConfig.Optimism == nil { // Optimism chains must not set any extra data.
 		header.Extra = miner.config.ExtraData
 	}
+	// Post-Holocene, the extra field commits to the EIP-1559 parameters given
+	// by the rollup node. Zero parameters select the chain config defaults.
+	if miner.chainConfig.IsOptimismHolocene(header.Time) {
+		if genParams.eip1559Params == nil {
+			return nil, errors.New("expected eip1559 params, got none")
+		}
+		if err := eip1559.ValidateHolocene1559Params(genParams.eip1559Params); err != nil {
+			return nil, err
+		}
+		denominator, elasticity := eip1559.DecodeHolocene1559Params(genParams.eip1559Params)
+		if denominator == 0 {
+			denominator = miner.chainConfig.BaseFeeChangeDenominator(header.Time)
+			elasticity = miner.chainConfig.ElasticityMultiplier()
+		}
+		header.Extra = eip1559.EncodeHoloceneExtraData(uint32(denominator), uint32(elasticity))
+	}
 	// Set the randomness field from the beacon chain if it's available.
 	if genParams.random != (common.Hash{}) {
 		header.MixDigest = genParams.random
@@ -309,11 +357,14 @@ func (miner *Miner) commitBlobTransaction(env *environment, tx *types.Transactio
 	if (env.blobs+len(sc.Blobs))*params.BlobTxBlobGasPerBlob > params.MaxBlobGasPerBlock {
 		return errors.New("max data blobs reached")
 	}
+	// Execute the transaction as included in the block, without the blobs, since
+	// the L1 data fee of rollups is charged on that encoding
+	tx = tx.WithoutBlobTxSidecar()
 	receipt, err := miner.applyTransaction(env, tx)
 	if err != nil {
 		return err
 	}
-	env.txs = append(env.txs, tx.WithoutBlobTxSidecar())
+	env.txs = append(env.txs, tx)
 	env.receipts = append(env.receipts, receipt)
 	env.sidecars = append(env.sidecars, sc)
 	env.blobs += len(sc.Blobs)
@@ -327,8 +378,16 @@ func (miner *Miner) applyTransaction(env *environment, tx *types.Transaction) (*
 	var (
 		snap = env.state.Snapshot()
 		gp   = env.gasPool.Gas()
+		opts *core.ApplyTransactionOpts
 	)
-	receipt, err := core.ApplyTransaction(miner.chainConfig, miner.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, vm.Config{})
+	if env.interopChecks && !tx.IsDepositTx() {
+		opts = &core.ApplyTransactionOpts{
+			PostValidation: func(evm *vm.EVM, result *core.ExecutionResult) error {
+				return miner.checkInterop(env.state.GetLogs(tx.Hash(), env.header.Number.Uint64(), common.Hash{}))
+			},
+		}
+	}
+	receipt, err := core.ApplyTransactionExtended(miner.chainConfig, miner.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, vm.Config{}, opts)
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
 		env.gasPool.SetGas(gp)
@@ -336,21 +395,50 @@ func (miner *Miner) applyTransaction(env *environment, tx *types.Transaction) (*
 	return receipt, err
 }
 
-func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *transactionsByPriceAndNonce, interrupt *atomic.Int32) error {
+// checkInterop checks the cross-chain executing messages found in the given
+// logs with the interop supervisor of the backend.
+func (miner *Miner) checkInterop(logs []*types.Log) error {
+	msgs, err := interoptypes.ExecutingMessagesFromLogs(logs)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvalidInteropMessage, err)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	backend, ok := miner.backend.(BackendWithInterop)
+	if !ok {
+		return fmt.Errorf("%w: backend does not support interop", errInvalidInteropMessage)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), interoptypes.CheckMessagesTimeout)
+	defer cancel()
+	if err := backend.CheckMessages(ctx, msgs, interoptypes.CrossUnsafe); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidInteropMessage, err)
+	}
+	return nil
+}
+
+func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *orderedTransactions, interrupt *atomic.Int32) error {
 	gasLimit := env.header.GasLimit
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(gasLimit)
 	}
+	miner.confMu.RLock()
+	maxDATxSize, maxDABlockSize := miner.config.MaxDATxSize, miner.config.MaxDABlockSize
+	miner.confMu.RUnlock()
+
 	for {
 		// Check interruption signal and abort building if it's fired.
 		if interrupt != nil {
 			if signal := interrupt.Load(); signal != commitInterruptNone {
-				return signalToErr(signal)
+				err := signalToErr(signal)
+				env.report.StopReason = err.Error()
+				return err
 			}
 		}
 		// If we don't have enough gas for any further transactions then we're done.
 		if env.gasPool.Gas() < params.TxGas {
 			log.Trace("Not enough gas for further transactions", "have", env.gasPool, "want", params.TxGas)
+			env.report.StopReason = "block gas limit reached"
 			break
 		}
 		// If we don't have enough blob space for any further blob transactions,
@@ -363,7 +451,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// Retrieve the next transaction and abort if all done.
 		var (
 			ltx *txpool.LazyTransaction
-			txs *transactionsByPriceAndNonce
+			txs *orderedTransactions
 		)
 		pltx, ptip := plainTxs.Peek()
 		bltx, btip := blobTxs.Peek()
@@ -374,7 +462,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		case bltx == nil:
 			txs, ltx = plainTxs, pltx
 		default:
-			if ptip.Lt(btip) {
+			if miner.ordering.Less(bltx, btip, pltx, ptip) {
 				txs, ltx = blobTxs, bltx
 			} else {
 				txs, ltx = plainTxs, pltx
@@ -386,11 +474,13 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// If we don't have enough space for the next transaction, skip the account.
 		if env.gasPool.Gas() < ltx.Gas {
 			log.Trace("Not enough gas left for transaction", "hash", ltx.Hash, "left", env.gasPool.Gas(), "needed", ltx.Gas)
+			env.report.skip(ltx.Hash, SkipGasLimit, nil)
 			txs.Pop()
 			continue
 		}
 		if left := uint64(params.MaxBlobGasPerBlock - env.blobs*params.BlobTxBlobGasPerBlob); left < ltx.BlobGas {
 			log.Trace("Not enough blob gas left for transaction", "hash", ltx.Hash, "left", left, "needed", ltx.BlobGas)
+			env.report.skip(ltx.Hash, SkipBlobGasLimit, nil)
 			txs.Pop()
 			continue
 		}
@@ -398,6 +488,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		tx := ltx.Resolve()
 		if tx == nil {
 			log.Trace("Ignoring evicted transaction", "hash", ltx.Hash)
+			env.report.skip(ltx.Hash, SkipEvicted, nil)
 			txs.Pop()
 			continue
 		}
@@ -409,9 +500,46 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// phase, start ignoring the sender until we do.
 		if tx.Protected() && !miner.chainConfig.IsEIP155(env.header.Number) {
 			log.Trace("Ignoring replay protected transaction", "hash", ltx.Hash, "eip155", miner.chainConfig.EIP155Block)
+			env.report.skip(ltx.Hash, SkipReplayProtected, nil)
 			txs.Pop()
 			continue
 		}
+		// Optimism addition: reject transactions submitted with preconditions which
+		// don't hold on top of the block being built, the pool drops them.
+		if cond := tx.Conditional(); cond != nil {
+			if err := env.header.CheckTransactionConditional(cond); err != nil {
+				log.Trace("Rejecting transaction with failed conditional", "hash", ltx.Hash, "err", err)
+				env.report.skip(ltx.Hash, SkipConditional, err)
+				tx.SetRejected()
+				txs.Pop()
+				continue
+			}
+			if err := env.state.CheckTransactionConditional(cond); err != nil {
+				log.Trace("Rejecting transaction with failed conditional", "hash", ltx.Hash, "err", err)
+				env.report.skip(ltx.Hash, SkipConditional, err)
+				tx.SetRejected()
+				txs.Pop()
+				continue
+			}
+		}
+		// Optimism addition: skip transactions exceeding the data availability
+		// limits. They are left in the pool, for a later block or a raised limit.
+		var daSize uint64
+		if maxDATxSize != 0 || maxDABlockSize != 0 {
+			daSize = tx.RollupCostData().FastLzSize
+			if maxDATxSize != 0 && daSize > maxDATxSize {
+				log.Trace("Ignoring transaction exceeding the DA size limit", "hash", ltx.Hash, "size", daSize, "limit", maxDATxSize)
+				env.report.skip(ltx.Hash, SkipDASizeLimit, nil)
+				txs.Pop()
+				continue
+			}
+			if maxDABlockSize != 0 && env.daSize+daSize > maxDABlockSize {
+				log.Trace("Not enough DA size left for transaction", "hash", ltx.Hash, "left", maxDABlockSize-env.daSize, "needed", daSize)
+				env.report.skip(ltx.Hash, SkipDASizeLimit, nil)
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -420,22 +548,114 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		case errors.Is(err, core.ErrNonceTooLow):
 			// New head notification data race between the transaction pool and miner, shift
 			log.Trace("Skipping transaction with low nonce", "hash", ltx.Hash, "sender", from, "nonce", tx.Nonce())
+			env.report.skip(ltx.Hash, SkipNonceTooLow, err)
 			txs.Shift()
 
 		case errors.Is(err, nil):
 			// Everything ok, collect the logs and shift in the next transaction from the same account
+			env.daSize += daSize
+			env.report.include(tx, env.receipts[len(env.receipts)-1], SourcePool)
 			txs.Shift()
 
 		default:
 			// Transaction is regarded as invalid, drop all consecutive transactions from
 			// the same sender because of `nonce-too-high` clause.
 			log.Debug("Transaction failed, account skipped", "hash", ltx.Hash, "err", err)
+			env.report.skip(ltx.Hash, skipReason(err), err)
 			txs.Pop()
 		}
 	}
 	return nil
 }
 
+// commitBundles includes the bundles of the bundle pool targeting the block being
+// built, in arrival order. Each bundle is included atomically: if any of its
+// transactions is invalid, or fails without being allowed to revert, the state
+// changes of the whole bundle are rolled back and the bundle is skipped.
+//
+// Bundles are only included past the Byzantium fork, as rolling back multiple
+// transactions doesn't support intermediate state roots.
+func (miner *Miner) commitBundles(env *environment, interrupt *atomic.Int32) error {
+	backend, ok := miner.backend.(BackendWithBundles)
+	if !ok || backend.BundlePool() == nil || !miner.chainConfig.IsByzantium(env.header.Number) {
+		return nil
+	}
+	pool := backend.BundlePool()
+	bundles := pool.Bundles(env.header.Number.Uint64())
+	if len(bundles) == 0 {
+		return nil
+	}
+	miner.confMu.RLock()
+	maxDATxSize, maxDABlockSize := miner.config.MaxDATxSize, miner.config.MaxDABlockSize
+	miner.confMu.RUnlock()
+
+	for _, bundle := range bundles {
+		// Check interruption signal and abort building if it's fired.
+		if interrupt != nil {
+			if signal := interrupt.Load(); signal != commitInterruptNone {
+				err := signalToErr(signal)
+				env.report.StopReason = err.Error()
+				return err
+			}
+		}
+		if err := miner.commitBundle(env, bundle, maxDATxSize, maxDABlockSize); err != nil {
+			log.Debug("Bundle failed, skipped", "hash", bundle.Hash(), "err", err)
+			for _, tx := range bundle.Txs {
+				env.report.skip(tx.Hash(), SkipBundleFailed, err)
+			}
+			pool.ReportFailure(bundle.Hash(), env.header.Number.Uint64())
+		}
+	}
+	return nil
+}
+
+// commitBundle applies the transactions of a bundle on top of the block being
+// built, reverting all of them if any fails.
+func (miner *Miner) commitBundle(env *environment, bundle *bundlepool.Bundle, maxDATxSize, maxDABlockSize uint64) error {
+	env.state.MultiTxSnapshot()
+	defer env.state.DiscardMultiTxSnapshot()
+
+	var (
+		gp      = env.gasPool.Gas()
+		gasUsed = env.header.GasUsed
+		tcount  = env.tcount
+		txs     = len(env.txs)
+		daSize  = env.daSize
+	)
+	revert := func() {
+		env.state.RevertMultiTxSnapshot()
+		env.gasPool.SetGas(gp)
+		env.header.GasUsed = gasUsed
+		env.tcount = tcount
+		env.txs, env.receipts = env.txs[:txs], env.receipts[:txs]
+		env.daSize = daSize
+	}
+	for _, tx := range bundle.Txs {
+		if maxDATxSize != 0 || maxDABlockSize != 0 {
+			size := tx.RollupCostData().FastLzSize
+			if (maxDATxSize != 0 && size > maxDATxSize) || (maxDABlockSize != 0 && env.daSize+size > maxDABlockSize) {
+				revert()
+				return fmt.Errorf("transaction %x exceeds the DA size limit", tx.Hash())
+			}
+			env.daSize += size
+		}
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+
+		if err := miner.commitTransaction(env, tx); err != nil {
+			revert()
+			return fmt.Errorf("transaction %x: %w", tx.Hash(), err)
+		}
+		if receipt := env.receipts[len(env.receipts)-1]; receipt.Status == types.ReceiptStatusFailed && !bundle.AllowsRevert(tx.Hash()) {
+			revert()
+			return fmt.Errorf("transaction %x reverted", tx.Hash())
+		}
+	}
+	for i, tx := range bundle.Txs {
+		env.report.include(tx, env.receipts[txs+i], SourceBundle)
+	}
+	return nil
+}
+
 // fillTransactions retrieves the pending transactions from the txpool and fills them
 // into the given sealing block. The transaction selection and ordering strategy can
 // be customized with the plugin in the future.
@@ -460,10 +680,28 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 	filter.OnlyPlainTxs, filter.OnlyBlobTxs = false, true
 	pendingBlobTxs := miner.txpool.Pending(filter)
 
-	// Split the pending transactions into locals and remotes.
+	// Include the bundles ahead of any pool transaction, to execute them on top
+	// of the state they were simulated against by their senders.
+	if err := miner.commitBundles(env, interrupt); err != nil {
+		return err
+	}
+	// Split the pending transactions into prioritized, locals and remotes.
+	prioPlainTxs, prioBlobTxs := make(map[common.Address][]*txpool.LazyTransaction), make(map[common.Address][]*txpool.LazyTransaction)
 	localPlainTxs, remotePlainTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingPlainTxs
 	localBlobTxs, remoteBlobTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingBlobTxs
 
+	for account, txs := range remotePlainTxs {
+		if miner.ordering.Prioritized(account) {
+			delete(remotePlainTxs, account)
+			prioPlainTxs[account] = txs
+		}
+	}
+	for account, txs := range remoteBlobTxs {
+		if miner.ordering.Prioritized(account) {
+			delete(remoteBlobTxs, account)
+			prioBlobTxs[account] = txs
+		}
+	}
 	for _, account := range miner.txpool.Locals() {
 		if txs := remotePlainTxs[account]; len(txs) > 0 {
 			delete(remotePlainTxs, account)
@@ -474,26 +712,92 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 			localBlobTxs[account] = txs
 		}
 	}
+	// Fill the block with the transactions of the prioritized senders first.
+	available := env.gasPool.Gas()
+	if len(prioPlainTxs) > 0 || len(prioBlobTxs) > 0 {
+		plainTxs := newOrderedTransactions(env.signer, prioPlainTxs, env.header.BaseFee, miner.ordering)
+		blobTxs := newOrderedTransactions(env.signer, prioBlobTxs, env.header.BaseFee, miner.ordering)
+
+		if err := miner.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
+			return err
+		}
+	}
+	// Keep the reserved gas left unused by the prioritized senders out of reach
+	// of the other transactions. It's given back to the prioritized senders once
+	// the others are included, for their transactions pooled in the meantime.
+	var reserved uint64
+	if limit, used := miner.ordering.ReservedGas(env.header.GasLimit), available-env.gasPool.Gas(); limit > used {
+		reserved = min(limit-used, env.gasPool.Gas())
+		env.gasPool.SubGas(reserved)
+	}
 	// Fill the block with all available pending transactions.
 	if len(localPlainTxs) > 0 || len(localBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, localPlainTxs, env.header.BaseFee)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, localBlobTxs, env.header.BaseFee)
+		plainTxs := newOrderedTransactions(env.signer, localPlainTxs, env.header.BaseFee, miner.ordering)
+		blobTxs := newOrderedTransactions(env.signer, localBlobTxs, env.header.BaseFee, miner.ordering)
 
 		if err := miner.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
 			return err
 		}
 	}
 	if len(remotePlainTxs) > 0 || len(remoteBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, remotePlainTxs, env.header.BaseFee)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, remoteBlobTxs, env.header.BaseFee)
+		plainTxs := newOrderedTransactions(env.signer, remotePlainTxs, env.header.BaseFee, miner.ordering)
+		blobTxs := newOrderedTransactions(env.signer, remoteBlobTxs, env.header.BaseFee, miner.ordering)
 
 		if err := miner.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
 			return err
 		}
 	}
+	if reserved > 0 {
+		env.gasPool.AddGas(reserved)
+
+		prioPlainTxs, prioBlobTxs = miner.pendingPrioritized(env, filter)
+		if len(prioPlainTxs) > 0 || len(prioBlobTxs) > 0 {
+			plainTxs := newOrderedTransactions(env.signer, prioPlainTxs, env.header.BaseFee, miner.ordering)
+			blobTxs := newOrderedTransactions(env.signer, prioBlobTxs, env.header.BaseFee, miner.ordering)
+
+			if err := miner.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// pendingPrioritized retrieves the pending transactions of the prioritized senders
+// that the block doesn't include yet, pre-filtered by the given filter.
+func (miner *Miner) pendingPrioritized(env *environment, filter txpool.PendingFilter) (map[common.Address][]*txpool.LazyTransaction, map[common.Address][]*txpool.LazyTransaction) {
+	included := make(map[common.Hash]struct{}, len(env.txs))
+	for _, tx := range env.txs {
+		included[tx.Hash()] = struct{}{}
+	}
+	collect := func(pending map[common.Address][]*txpool.LazyTransaction) map[common.Address][]*txpool.LazyTransaction {
+		prio := make(map[common.Address][]*txpool.LazyTransaction)
+		for account, txs := range pending {
+			if !miner.ordering.Prioritized(account) {
+				continue
+			}
+			// Skip the transactions included already, leading the nonce order
+			for len(txs) > 0 {
+				if _, ok := included[txs[0].Hash]; !ok {
+					break
+				}
+				txs = txs[1:]
+			}
+			if len(txs) > 0 {
+				prio[account] = txs
+			}
+		}
+		return prio
+	}
+	filter.OnlyPlainTxs, filter.OnlyBlobTxs = true, false
+	plainTxs := collect(miner.txpool.Pending(filter))
+
+	filter.OnlyPlainTxs, filter.OnlyBlobTxs = false, true
+	blobTxs := collect(miner.txpool.Pending(filter))
+
+	return plainTxs, blobTxs
+}
+
 // totalFees computes total consumed miner fees in Wei. Block transactions and receipts have to have the same order.
 func totalFees(block *types.Block, receipts []*types.Receipt) *big.Int {
 	feesWei := new(big.Int)
@@ -181,6 +181,8 @@ func (api *adminAPI) StartHTTP(host *string, port *int, cors *string, apis *stri
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			originHeader:           api.node.config.RPCOriginHeader,
+			trustedProxies:         api.node.config.RPCTrustedProxies,
 		},
 	}
 	if cors != nil {
@@ -259,6 +261,8 @@ func (api *adminAPI) StartWS(host *string, port *int, allowedOrigins *string, ap
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			originHeader:           api.node.config.RPCOriginHeader,
+			trustedProxies:         api.node.config.RPCTrustedProxies,
 		},
 	}
 	if apis != nil {
@@ -204,6 +204,14 @@ type Config struct {
 	// BatchResponseMaxSize is the maximum number of bytes returned from a batched rpc call.
 	BatchResponseMaxSize int `toml:",omitempty"`
 
+	// RPCOriginHeader is the request header holding the client address, as appended
+	// by the reverse proxies of RPCTrustedProxies, e.g. X-Forwarded-For.
+	RPCOriginHeader string `toml:",omitempty"`
+
+	// RPCTrustedProxies is the list of addresses or CIDR ranges of the reverse proxies
+	// trusted to set RPCOriginHeader on the HTTP and WebSocket requests they forward.
+	RPCTrustedProxies []string `toml:",omitempty"`
+
 	// JWTSecret is the path to the hex-encoded jwt secret.
 	JWTSecret string `toml:",omitempty"`
 
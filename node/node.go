@@ -405,6 +405,8 @@ func (n *Node) startRPC() error {
 	rpcConfig := rpcEndpointConfig{
 		batchItemLimit:         n.config.BatchRequestLimit,
 		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		originHeader:           n.config.RPCOriginHeader,
+		trustedProxies:         n.config.RPCTrustedProxies,
 	}
 
 	initHttp := func(server *httpServer, port int) error {
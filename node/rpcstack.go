@@ -24,6 +24,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/netip"
 	"sort"
 	"strconv"
 	"strings"
@@ -58,6 +59,8 @@ type rpcEndpointConfig struct {
 	batchItemLimit         int
 	batchResponseSizeLimit int
 	httpBodyLimit          int
+	originHeader           string
+	trustedProxies         []string
 }
 
 type rpcHandler struct {
@@ -304,10 +307,9 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	}
 
 	// Create RPC server and handler.
-	srv := rpc.NewServer()
-	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
-	if config.httpBodyLimit > 0 {
-		srv.SetHTTPBodyLimit(config.httpBodyLimit)
+	srv, err := newRPCServer(config.rpcEndpointConfig)
+	if err != nil {
+		return err
 	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
@@ -320,6 +322,48 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	return nil
 }
 
+// newRPCServer creates an RPC server with the given endpoint configuration.
+func newRPCServer(config rpcEndpointConfig) (*rpc.Server, error) {
+	srv := rpc.NewServer()
+	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	if config.httpBodyLimit > 0 {
+		srv.SetHTTPBodyLimit(config.httpBodyLimit)
+	}
+	if config.originHeader != "" {
+		proxies, err := parseTrustedProxies(config.trustedProxies)
+		if err != nil {
+			return nil, err
+		}
+		if len(proxies) == 0 {
+			log.Warn("RPC origin header is ignored without trusted proxies", "header", config.originHeader)
+		}
+		srv.SetOriginHeader(config.originHeader, proxies)
+	}
+	return srv, nil
+}
+
+// parseTrustedProxies parses a list of IP addresses and CIDR ranges.
+func parseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %v", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %v", proxy, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
 // disableRPC stops the HTTP RPC handler. This is internal, the caller must hold h.mu.
 func (h *httpServer) disableRPC() bool {
 	handler := h.httpHandler.Load().(*rpcHandler)
@@ -339,10 +383,9 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 		return errors.New("JSON-RPC over WebSocket is already enabled")
 	}
 	// Create RPC server and handler.
-	srv := rpc.NewServer()
-	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
-	if config.httpBodyLimit > 0 {
-		srv.SetHTTPBodyLimit(config.httpBodyLimit)
+	srv, err := newRPCServer(config.rpcEndpointConfig)
+	if err != nil {
+		return err
 	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
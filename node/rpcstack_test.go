@@ -22,6 +22,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"net/url"
 	"strconv"
 	"strings"
@@ -180,6 +181,23 @@ func TestIsWebsocket(t *testing.T) {
 	assert.True(t, isWebsocket(r))
 }
 
+// TestParseTrustedProxies tests that trusted proxies are given as addresses or CIDR ranges.
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := parseTrustedProxies([]string{"127.0.0.1", "10.1.2.3/8", "::ffff:192.168.0.1", "fd00::/8"})
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("127.0.0.1/32"),
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.0.1/32"),
+		netip.MustParsePrefix("fd00::/8"),
+	}, prefixes)
+
+	for _, proxy := range []string{"localhost", "10.0.0.0/33", ""} {
+		_, err := parseTrustedProxies([]string{proxy})
+		assert.Error(t, err, proxy)
+	}
+}
+
 func Test_checkPath(t *testing.T) {
 	tests := []struct {
 		req      *http.Request
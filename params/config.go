@@ -424,6 +424,12 @@ type OptimismConfig struct {
 	EIP1559Elasticity        uint64  `json:"eip1559Elasticity"`
 	EIP1559Denominator       uint64  `json:"eip1559Denominator"`
 	EIP1559DenominatorCanyon *uint64 `json:"eip1559DenominatorCanyon,omitempty"`
+
+	// BlobTxs opts the chain in to EIP-4844 blob transactions from Cancun on, for
+	// chains using blobs as their own data availability layer. The rollup node must
+	// then pass the versioned hashes of the blobs to engine_newPayloadV3. It can't
+	// be changed once Cancun is active.
+	BlobTxs bool `json:"blobTxs,omitempty"`
 }
 
 // String implements the stringer interface, returning the optimism fee config details.
@@ -689,6 +695,12 @@ func (c *ChainConfig) IsOptimism() bool {
 	return c.Optimism != nil
 }
 
+// HasBlobTxs returns whether the chain supports blob transactions once Cancun is
+// active: always on L1, and only if opted in on OP-Stack chains.
+func (c *ChainConfig) HasBlobTxs() bool {
+	return c.Optimism == nil || c.Optimism.BlobTxs
+}
+
 // IsOptimismBedrock returns true iff this is an optimism node & bedrock is active
 func (c *ChainConfig) IsOptimismBedrock(num *big.Int) bool {
 	return c.IsOptimism() && c.IsBedrock(num)
@@ -718,6 +730,10 @@ func (c *ChainConfig) IsOptimismHolocene(time uint64) bool {
 	return c.IsOptimism() && c.IsHolocene(time)
 }
 
+func (c *ChainConfig) IsOptimismInterop(time uint64) bool {
+	return c.IsOptimism() && c.IsInterop(time)
+}
+
 // IsOptimismPreBedrock returns true iff this is an optimism node & bedrock is not yet active
 func (c *ChainConfig) IsOptimismPreBedrock(num *big.Int) bool {
 	return c.IsOptimism() && !c.IsBedrock(num)
@@ -878,6 +894,9 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.CancunTime, newcfg.CancunTime, headTimestamp, genesisTimestamp) {
 		return newTimestampCompatError("Cancun fork timestamp", c.CancunTime, newcfg.CancunTime)
 	}
+	if c.IsCancun(headNumber, headTimestamp) && c.HasBlobTxs() != newcfg.HasBlobTxs() {
+		return newTimestampCompatError("Blob transactions flag", c.CancunTime, newcfg.CancunTime)
+	}
 	if isForkTimestampIncompatible(c.PragueTime, newcfg.PragueTime, headTimestamp, genesisTimestamp) {
 		return newTimestampCompatError("Prague fork timestamp", c.PragueTime, newcfg.PragueTime)
 	}
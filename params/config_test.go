@@ -113,6 +113,23 @@ func TestCheckCompatible(t *testing.T) {
 				RewindToTime: 9,
 			},
 		},
+		{
+			stored:        &ChainConfig{LondonBlock: big.NewInt(0), CancunTime: newUint64(10), Optimism: &OptimismConfig{}},
+			new:           &ChainConfig{LondonBlock: big.NewInt(0), CancunTime: newUint64(10), Optimism: &OptimismConfig{BlobTxs: true}},
+			headTimestamp: 9,
+			wantErr:       nil,
+		},
+		{
+			stored:        &ChainConfig{LondonBlock: big.NewInt(0), CancunTime: newUint64(10), Optimism: &OptimismConfig{}},
+			new:           &ChainConfig{LondonBlock: big.NewInt(0), CancunTime: newUint64(10), Optimism: &OptimismConfig{BlobTxs: true}},
+			headTimestamp: 25,
+			wantErr: &ConfigCompatError{
+				What:         "Blob transactions flag",
+				StoredTime:   newUint64(10),
+				NewTime:      newUint64(10),
+				RewindToTime: 9,
+			},
+		},
 		{
 			stored:           &ChainConfig{CanyonTime: newUint64(10)},
 			new:              &ChainConfig{CanyonTime: newUint64(20)},
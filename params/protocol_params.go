@@ -27,6 +27,10 @@ var (
 	OptimismBaseFeeRecipient = common.HexToAddress("0x4200000000000000000000000000000000000019")
 	// The L1 portion of the transaction fee accumulates at this predeploy
 	OptimismL1FeeRecipient = common.HexToAddress("0x420000000000000000000000000000000000001A")
+	// The L2ToL1MessagePasser predeploy burns the ETH withdrawn to L1
+	OptimismL2ToL1MessagePasser = common.HexToAddress("0x4200000000000000000000000000000000000016")
+	// The CrossL2Inbox predeploy emits the executing messages of interop transactions
+	InteropCrossL2InboxAddress = common.HexToAddress("0x4200000000000000000000000000000000000022")
 )
 
 const (
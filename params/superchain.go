@@ -46,7 +46,12 @@ func LoadOPStackChainConfig(chainID uint64) (*ChainConfig, error) {
 	if !ok {
 		return nil, fmt.Errorf("unknown chain ID: %d", chainID)
 	}
+	return newOPStackChainConfig(chainID, chConfig), nil
+}
 
+// newOPStackChainConfig converts a superchain-registry chain definition into the
+// chain configuration of the chain.
+func newOPStackChainConfig(chainID uint64, chConfig *superchain.ChainConfig) *ChainConfig {
 	genesisActivation := uint64(0)
 	out := &ChainConfig{
 		ChainID:                       new(big.Int).SetUint64(chainID),
@@ -103,7 +108,7 @@ func LoadOPStackChainConfig(chainID uint64) (*ChainConfig, error) {
 		out.BedrockBlock = big.NewInt(105235063)
 	}
 
-	return out, nil
+	return out
 }
 
 // ProtocolVersion encodes the OP-Stack protocol version. See OP-Stack superchain-upgrade specification.
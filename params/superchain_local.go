@@ -0,0 +1,345 @@
+package params
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+)
+
+// SuperchainRegistry holds the OP-Stack chain definitions loaded from a local copy
+// of the superchain-registry. They overlay the definitions embedded in the binary,
+// which are left untouched. A nil registry holds the embedded definitions only.
+type SuperchainRegistry struct {
+	chains    map[uint64]*superchain.ChainConfig // Local chain definitions by chain ID
+	extraDirs map[uint64]string                  // Directories of the genesis definitions and bytecodes by chain ID
+}
+
+// localSuperchain is a superchain target of a local superchain-registry.
+type localSuperchain struct {
+	name      string
+	hardforks superchain.HardForkConfiguration // Defaults inherited by the chains
+}
+
+// LoadSuperchainRegistry loads OP-Stack chain definitions from a local copy of the
+// superchain-registry, to be used on top of the ones embedded in the binary. The path
+// is either the superchain directory of a registry, containing the configs and extra
+// directories, or a single chain configuration file. For the latter, the superchain
+// is named after the containing directory and configured by the superchain.toml next
+// to the chain file, if present.
+//
+// Local definitions replace the embedded definition of the same chain, e.g. to change
+// hardfork times. They may not reuse the chain ID of another chain.
+func LoadSuperchainRegistry(path string) (*SuperchainRegistry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	registry := &SuperchainRegistry{
+		chains:    make(map[uint64]*superchain.ChainConfig),
+		extraDirs: make(map[uint64]string),
+	}
+	if !info.IsDir() {
+		target, err := loadLocalSuperchain(filepath.Dir(path))
+		if err != nil {
+			return nil, err
+		}
+		chain, err := loadLocalChainConfig(path, target)
+		if err != nil {
+			return nil, err
+		}
+		if err := registry.add(chain, ""); err != nil {
+			return nil, err
+		}
+		return registry, nil
+	}
+	targets, err := os.ReadDir(filepath.Join(path, "configs"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid superchain registry %s: %w", path, err)
+	}
+	for _, entry := range targets {
+		if !entry.IsDir() {
+			continue // ignore files, e.g. a readme
+		}
+		dir := filepath.Join(path, "configs", entry.Name())
+		target, err := loadLocalSuperchain(dir)
+		if err != nil {
+			return nil, err
+		}
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if file.IsDir() || !strings.HasSuffix(file.Name(), ".toml") || file.Name() == "superchain.toml" || file.Name() == "semver.toml" {
+				continue
+			}
+			chain, err := loadLocalChainConfig(filepath.Join(dir, file.Name()), target)
+			if err != nil {
+				return nil, err
+			}
+			if err := registry.add(chain, filepath.Join(path, "extra")); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return registry, nil
+}
+
+// loadLocalSuperchain loads the superchain.toml of a superchain target directory.
+// The file is optional, without it the chains don't inherit any hardfork times.
+func loadLocalSuperchain(dir string) (*localSuperchain, error) {
+	target := &localSuperchain{name: filepath.Base(dir)}
+
+	file := filepath.Join(dir, "superchain.toml")
+	if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+		return target, nil
+	}
+	config := struct {
+		*superchain.SuperchainConfig      `toml:",inline"`
+		*superchain.HardForkConfiguration `toml:",inline"`
+	}{new(superchain.SuperchainConfig), &target.hardforks}
+
+	if err := decodeRegistryFile(file, &config); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// loadLocalChainConfig loads and validates a chain configuration file of the given
+// superchain target.
+func loadLocalChainConfig(file string, target *localSuperchain) (*superchain.ChainConfig, error) {
+	var chain superchain.ChainConfig
+	if err := decodeRegistryFile(file, &chain); err != nil {
+		return nil, err
+	}
+	chain.Chain = strings.TrimSuffix(filepath.Base(file), ".toml")
+	chain.Superchain = target.name
+
+	// Missing hardfork times are inherited from the superchain, as done for the
+	// embedded registry.
+	if chain.SuperchainTime != nil {
+		hardforks := []struct {
+			override **uint64
+			fallback *uint64
+		}{
+			{&chain.CanyonTime, target.hardforks.CanyonTime},
+			{&chain.DeltaTime, target.hardforks.DeltaTime},
+			{&chain.EcotoneTime, target.hardforks.EcotoneTime},
+			{&chain.FjordTime, target.hardforks.FjordTime},
+			{&chain.GraniteTime, target.hardforks.GraniteTime},
+			{&chain.HoloceneTime, target.hardforks.HoloceneTime},
+		}
+		for _, fork := range hardforks {
+			if *fork.override != nil || fork.fallback == nil || *fork.fallback < *chain.SuperchainTime {
+				continue
+			}
+			time := uint64(0) // Activated at genesis
+			if *fork.fallback > chain.Genesis.L2Time {
+				time = *fork.fallback
+			}
+			*fork.override = &time
+		}
+	}
+	if err := validateLocalChainConfig(&chain); err != nil {
+		return nil, fmt.Errorf("invalid chain config %s: %w", file, err)
+	}
+	return &chain, nil
+}
+
+// decodeRegistryFile decodes a TOML file of the superchain-registry, rejecting any
+// field unknown to the embedded registry schema.
+func decodeRegistryFile(file string, v interface{}) error {
+	meta, err := toml.DecodeFile(file, v)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", file, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return fmt.Errorf("unknown fields in %s: %s", file, strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// validateLocalChainConfig checks that a chain configuration is complete and that
+// its hardforks are in order.
+func validateLocalChainConfig(chain *superchain.ChainConfig) error {
+	switch {
+	case chain.ChainID == 0:
+		return errors.New("missing chain_id")
+	case chain.Name == "":
+		return errors.New("missing name")
+	case chain.SuperchainLevel != superchain.Standard && chain.SuperchainLevel != superchain.Frontier:
+		return fmt.Errorf("invalid superchain_level %d", chain.SuperchainLevel)
+	case chain.BlockTime == 0:
+		return errors.New("missing block_time")
+	case chain.Genesis.L2.Hash == (superchain.Hash{}):
+		return errors.New("missing genesis.l2.hash")
+	}
+	var (
+		lastName string
+		lastTime *uint64
+	)
+	for _, fork := range []struct {
+		name string
+		time *uint64
+	}{
+		{"canyon_time", chain.CanyonTime},
+		{"delta_time", chain.DeltaTime},
+		{"ecotone_time", chain.EcotoneTime},
+		{"fjord_time", chain.FjordTime},
+		{"granite_time", chain.GraniteTime},
+		{"holocene_time", chain.HoloceneTime},
+	} {
+		if fork.time == nil {
+			lastName, lastTime = fork.name, nil
+			continue
+		}
+		if lastName != "" && lastTime == nil {
+			return fmt.Errorf("%s set without %s", fork.name, lastName)
+		}
+		if lastTime != nil && *fork.time < *lastTime {
+			return fmt.Errorf("%s %d before %s %d", fork.name, *fork.time, lastName, *lastTime)
+		}
+		lastName, lastTime = fork.name, fork.time
+	}
+	return nil
+}
+
+// add adds a local chain definition to the registry, replacing the embedded
+// definition of the same chain.
+func (r *SuperchainRegistry) add(chain *superchain.ChainConfig, extraDir string) error {
+	if other, ok := r.OPChain(chain.ChainID); ok && other.Identifier() != chain.Identifier() {
+		return fmt.Errorf("chain %s with chain ID %d conflicts with chain %s", chain.Identifier(), chain.ChainID, other.Identifier())
+	}
+	for _, chains := range []map[uint64]*superchain.ChainConfig{superchain.OPChains, r.chains} {
+		for id, other := range chains {
+			if id != chain.ChainID && other.Identifier() == chain.Identifier() {
+				return fmt.Errorf("chain %s with chain ID %d conflicts with its definition with chain ID %d", chain.Identifier(), chain.ChainID, id)
+			}
+		}
+	}
+	r.chains[chain.ChainID] = chain
+	if extraDir != "" {
+		r.extraDirs[chain.ChainID] = extraDir
+	}
+	return nil
+}
+
+// ChainIDs returns the IDs of the chains defined by the local registry, sorted.
+func (r *SuperchainRegistry) ChainIDs() []uint64 {
+	if r == nil {
+		return nil
+	}
+	ids := make([]uint64, 0, len(r.chains))
+	for id := range r.chains {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// OPChain returns the definition of an OP-Stack chain, from the local registry if
+// it defines the chain, or else from the embedded registry.
+func (r *SuperchainRegistry) OPChain(chainID uint64) (*superchain.ChainConfig, bool) {
+	if r != nil {
+		if chain, ok := r.chains[chainID]; ok {
+			return chain, true
+		}
+	}
+	chain, ok := superchain.OPChains[chainID]
+	return chain, ok
+}
+
+// ChainIDByName returns the ID of the OP-Stack chain with the given name, in the
+// <chain>-<superchain> format of the --op-network flag.
+func (r *SuperchainRegistry) ChainIDByName(name string) (uint64, error) {
+	if r != nil {
+		for id, ch := range r.chains {
+			if ch.Chain+"-"+ch.Superchain == name {
+				return id, nil
+			}
+		}
+	}
+	return OPStackChainIDByName(name)
+}
+
+// ChainConfig returns the chain configuration of an OP-Stack chain, as defined by
+// the local registry if it does, or else by the embedded registry.
+func (r *SuperchainRegistry) ChainConfig(chainID uint64) (*ChainConfig, error) {
+	chain, ok := r.OPChain(chainID)
+	if !ok {
+		return nil, fmt.Errorf("unknown chain ID: %d", chainID)
+	}
+	return newOPStackChainConfig(chainID, chain), nil
+}
+
+// GenesisDefinition returns the genesis definition of an OP-Stack chain, from the
+// local registry the chain was loaded from, if any, or else from the embedded one.
+func (r *SuperchainRegistry) GenesisDefinition(chainID uint64) (*superchain.Genesis, error) {
+	if r != nil {
+		if dir, ok := r.extraDirs[chainID]; ok {
+			chain := r.chains[chainID]
+			data, err := readRegistryFile(filepath.Join(dir, "genesis", chain.Superchain, chain.Chain+".json"))
+			if err == nil {
+				var genesis superchain.Genesis
+				if err := json.Unmarshal(data, &genesis); err != nil {
+					return nil, fmt.Errorf("failed to decode genesis definition of %d: %w", chainID, err)
+				}
+				return &genesis, nil
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+	return superchain.LoadGenesis(chainID)
+}
+
+// ContractBytecode returns the contract bytecode with the given hash, as referenced
+// by genesis definitions, from the local registry or else from the embedded one.
+func (r *SuperchainRegistry) ContractBytecode(codeHash superchain.Hash) ([]byte, error) {
+	if r != nil {
+		for _, dir := range r.extraDirs {
+			code, err := readRegistryFile(filepath.Join(dir, "bytecodes", codeHash.String()+".bin"))
+			if err == nil {
+				return code, nil
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+	return superchain.LoadContractBytecode(codeHash)
+}
+
+// readRegistryFile reads a file of a local superchain-registry, either gzipped with
+// a .gz suffix as in the upstream registry, or uncompressed.
+func readRegistryFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path + ".gz")
+	if errors.Is(err, fs.ErrNotExist) {
+		return os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip reader of %s: %w", path, err)
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,157 @@
+package params
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+)
+
+const (
+	testLocalChainID = 901901
+
+	testLocalSuperchainConfig = `name = "Devnet"
+canyon_time = 0
+delta_time = 0
+ecotone_time = 0
+fjord_time = 1000
+
+[l1]
+  chain_id = 900
+  public_rpc = "http://localhost:8545"
+`
+	testLocalChainConfig = `name = "Local Devnet"
+chain_id = %d
+superchain_level = 0
+superchain_time = 0
+granite_time = %d
+block_time = 2
+seq_window_size = 3600
+max_sequencer_drift = 600
+%s
+[optimism]
+  eip1559_elasticity = 6
+  eip1559_denominator = 50
+  eip1559_denominator_canyon = 250
+
+[genesis]
+  l2_time = 10
+  [genesis.l2]
+    hash = "0x0000000000000000000000000000000000000000000000000000000000000001"
+    number = 0
+`
+)
+
+// writeLocalRegistry writes a superchain-registry with a single chain to a
+// temporary directory.
+func writeLocalRegistry(t *testing.T, chain string) string {
+	dir := t.TempDir()
+	configs := filepath.Join(dir, "configs", "devnet")
+	if err := os.MkdirAll(configs, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configs, "superchain.toml"), []byte(testLocalSuperchainConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configs, "local.toml"), []byte(chain), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoadSuperchainRegistry(t *testing.T) {
+	dir := writeLocalRegistry(t, fmt.Sprintf(testLocalChainConfig, testLocalChainID, 2000, ""))
+
+	registry, err := LoadSuperchainRegistry(dir)
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	if ids := registry.ChainIDs(); len(ids) != 1 || ids[0] != testLocalChainID {
+		t.Fatalf("wrong chains loaded: %v", ids)
+	}
+	id, err := registry.ChainIDByName("local-devnet")
+	if err != nil || id != testLocalChainID {
+		t.Fatalf("chain not found by name: %d, %v", id, err)
+	}
+	config, err := registry.ChainConfig(id)
+	if err != nil {
+		t.Fatalf("failed to load chain config: %v", err)
+	}
+	// Hardforks before the genesis are activated at genesis, later ones are
+	// inherited from the superchain unless overridden by the chain.
+	if config.CanyonTime == nil || *config.CanyonTime != 0 {
+		t.Errorf("wrong canyon time: %v", config.CanyonTime)
+	}
+	if config.FjordTime == nil || *config.FjordTime != 1000 {
+		t.Errorf("wrong fjord time: %v", config.FjordTime)
+	}
+	if config.GraniteTime == nil || *config.GraniteTime != 2000 {
+		t.Errorf("wrong granite time: %v", config.GraniteTime)
+	}
+	// Embedded chains remain available through the registry, which leaves the
+	// global superchain definitions untouched.
+	if id, err := registry.ChainIDByName("op-mainnet"); err != nil || id != OPMainnetChainID {
+		t.Errorf("embedded chain not found by name: %d, %v", id, err)
+	}
+	if _, ok := superchain.OPChains[testLocalChainID]; ok {
+		t.Errorf("local chain registered globally")
+	}
+	if _, err := LoadOPStackChainConfig(testLocalChainID); err == nil {
+		t.Errorf("local chain config loaded without the registry")
+	}
+	// Reloading the registry picks up the changed definition.
+	if err := os.WriteFile(filepath.Join(dir, "configs", "devnet", "local.toml"), []byte(fmt.Sprintf(testLocalChainConfig, testLocalChainID, 3000, "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadSuperchainRegistry(dir)
+	if err != nil {
+		t.Fatalf("failed to reload registry: %v", err)
+	}
+	if config, _ := reloaded.ChainConfig(id); *config.GraniteTime != 3000 {
+		t.Errorf("granite time not updated: %d", *config.GraniteTime)
+	}
+	if config, _ := registry.ChainConfig(id); *config.GraniteTime != 2000 {
+		t.Errorf("previous registry changed by reload: %d", *config.GraniteTime)
+	}
+}
+
+func TestLoadSuperchainRegistryFile(t *testing.T) {
+	dir := writeLocalRegistry(t, fmt.Sprintf(testLocalChainConfig, testLocalChainID, 2000, ""))
+
+	registry, err := LoadSuperchainRegistry(filepath.Join(dir, "configs", "devnet", "local.toml"))
+	if err != nil {
+		t.Fatalf("failed to load chain file: %v", err)
+	}
+	if ids := registry.ChainIDs(); len(ids) != 1 || ids[0] != testLocalChainID {
+		t.Fatalf("wrong chains loaded: %v", ids)
+	}
+	if _, err := registry.ChainIDByName("local-devnet"); err != nil {
+		t.Fatalf("chain not found by name: %v", err)
+	}
+}
+
+func TestLoadSuperchainRegistryInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{"unknown field", fmt.Sprintf(testLocalChainConfig, testLocalChainID, 2000, "unknown_time = 5"), "unknown fields"},
+		{"fork order", fmt.Sprintf(testLocalChainConfig, testLocalChainID, 500, ""), "granite_time 500 before fjord_time 1000"},
+		{"missing chain ID", fmt.Sprintf(testLocalChainConfig, 0, 2000, ""), "missing chain_id"},
+		{"chain ID conflict", fmt.Sprintf(testLocalChainConfig, OPMainnetChainID, 2000, ""), "conflicts with chain mainnet/op"},
+	}
+	for _, tt := range tests {
+		dir := writeLocalRegistry(t, tt.config)
+		_, err := LoadSuperchainRegistry(dir)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: wrong error: have %v, want %q", tt.name, err, tt.err)
+		}
+	}
+	if chain := superchain.OPChains[OPMainnetChainID]; chain.Chain != "op" {
+		t.Errorf("embedded chain replaced by %s", chain.Identifier())
+	}
+}
@@ -25,9 +25,12 @@ import (
 	"io"
 	"math"
 	"mime"
+	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -306,6 +309,36 @@ func (t *httpServerConn) RemoteAddr() string {
 // SetWriteDeadline does nothing and always returns nil.
 func (t *httpServerConn) SetWriteDeadline(time.Time) error { return nil }
 
+// forwardedFor returns the client address appended to the origin header by the
+// proxy sending the request, or an empty string if the proxy isn't trusted.
+func (s *Server) forwardedFor(r *http.Request) string {
+	if s.originHeader == "" {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	peer, err := netip.ParseAddr(host)
+	if err != nil {
+		return ""
+	}
+	peer = peer.Unmap()
+	for _, proxy := range s.trustedProxies {
+		if proxy.Contains(peer) {
+			// Only the last address is appended by the proxy, earlier ones are
+			// set by the client.
+			values := r.Header.Values(s.originHeader)
+			if len(values) == 0 {
+				return ""
+			}
+			addrs := strings.Split(values[len(values)-1], ",")
+			return strings.TrimSpace(addrs[len(addrs)-1])
+		}
+	}
+	return ""
+}
+
 // ServeHTTP serves JSON-RPC requests over HTTP.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Permit dumb empty requests for remote health-checks (AWS)
@@ -324,6 +357,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	connInfo.HTTP.Host = r.Host
 	connInfo.HTTP.Origin = r.Header.Get("Origin")
 	connInfo.HTTP.UserAgent = r.Header.Get("User-Agent")
+	connInfo.HTTP.ForwardedFor = s.forwardedFor(r)
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, connInfo)
 
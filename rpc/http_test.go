@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"strings"
 	"testing"
 )
@@ -204,6 +205,49 @@ func TestHTTPPeerInfo(t *testing.T) {
 	}
 }
 
+// Tests that the origin header is only exposed for the requests of trusted proxies.
+func TestPeerInfoForwardedFor(t *testing.T) {
+	tests := []struct {
+		proxies   []netip.Prefix // Trusted proxies configured on the server
+		forwarded string         // Value of the origin header sent by the peer
+		want      string
+	}{
+		{nil, "10.0.0.1", ""},
+		{[]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, "10.0.0.1", ""},
+		{[]netip.Prefix{netip.MustParsePrefix("127.0.0.1/32")}, "", ""},
+		{[]netip.Prefix{netip.MustParsePrefix("127.0.0.1/32")}, "10.0.0.1", "10.0.0.1"},
+		{[]netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}, "1.2.3.4, 10.0.0.1", "10.0.0.1"},
+	}
+	for i, tt := range tests {
+		s := newTestServer()
+		s.SetOriginHeader("X-Forwarded-For", tt.proxies)
+		ts := httptest.NewServer(s)
+		ws := httptest.NewServer(s.WebsocketHandler([]string{"*"}))
+
+		for _, url := range []string{ts.URL, "ws:" + strings.TrimPrefix(ws.URL, "http:")} {
+			var opts []ClientOption
+			if tt.forwarded != "" {
+				opts = append(opts, WithHeader("X-Forwarded-For", tt.forwarded))
+			}
+			c, err := DialOptions(context.Background(), url, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var info PeerInfo
+			if err := c.Call(&info, "test_peerInfo"); err != nil {
+				t.Fatal(err)
+			}
+			if info.HTTP.ForwardedFor != tt.want {
+				t.Errorf("test %d, %s: wrong HTTP.ForwardedFor %q, want %q", i, info.Transport, info.HTTP.ForwardedFor, tt.want)
+			}
+			c.Close()
+		}
+		ts.Close()
+		ws.Close()
+		s.Stop()
+	}
+}
+
 func TestNewContextWithHeaders(t *testing.T) {
 	expectedHeaders := 0
 	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
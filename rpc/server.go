@@ -21,6 +21,7 @@ import (
 	"errors"
 	"io"
 	"net"
+	"net/netip"
 	"sync"
 	"sync/atomic"
 
@@ -54,6 +55,8 @@ type Server struct {
 	batchItemLimit     int
 	batchResponseLimit int
 	httpBodyLimit      int
+	originHeader       string
+	trustedProxies     []netip.Prefix
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -89,6 +92,16 @@ func (s *Server) SetHTTPBodyLimit(limit int) {
 	s.httpBodyLimit = limit
 }
 
+// SetOriginHeader sets the request header holding the client address, as appended
+// by the given trusted proxies. The address is only exposed to handlers through
+// PeerInfo for the requests sent by one of the proxies.
+//
+// This method should be called before processing any requests via ServeHTTP.
+func (s *Server) SetOriginHeader(header string, proxies []netip.Prefix) {
+	s.originHeader = header
+	s.trustedProxies = proxies
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either an RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -234,6 +247,10 @@ type PeerInfo struct {
 		UserAgent string
 		Origin    string
 		Host      string
+
+		// Client address appended to the origin header by a trusted proxy. This
+		// is only set if the server has an origin header configured.
+		ForwardedFor string
 	}
 }
 
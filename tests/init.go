@@ -396,6 +396,46 @@ var Forks = map[string]*params.ChainConfig{
 	},
 }
 
+// Optimism forks. These are not part of the Ethereum test suites, but allow the
+// evm tool to produce and run OP-stack fixtures. Each fork is derived from the
+// previous one, starting from Paris.
+func init() {
+	bedrock := *Forks["Paris"]
+	bedrock.BedrockBlock = big.NewInt(0)
+	bedrock.Optimism = optimismTestConfig
+	Forks["OptimismBedrock"] = &bedrock
+
+	regolith := bedrock
+	regolith.RegolithTime = u64(0)
+	Forks["OptimismRegolith"] = &regolith
+
+	canyon := regolith
+	canyon.ShanghaiTime = u64(0)
+	canyon.CanyonTime = u64(0)
+	Forks["OptimismCanyon"] = &canyon
+
+	ecotone := canyon
+	ecotone.CancunTime = u64(0)
+	ecotone.EcotoneTime = u64(0)
+	Forks["OptimismEcotone"] = &ecotone
+
+	fjord := ecotone
+	fjord.FjordTime = u64(0)
+	Forks["OptimismFjord"] = &fjord
+
+	granite := fjord
+	granite.GraniteTime = u64(0)
+	Forks["OptimismGranite"] = &granite
+}
+
+// optimismTestConfig is the EIP-1559 configuration of the Optimism forks, matching
+// OP Mainnet.
+var optimismTestConfig = &params.OptimismConfig{
+	EIP1559Elasticity:        6,
+	EIP1559Denominator:       50,
+	EIP1559DenominatorCanyon: u64(250),
+}
+
 // AvailableForks returns the set of defined fork names
 func AvailableForks() []string {
 	var availableForks []string